@@ -0,0 +1,350 @@
+package geom
+
+import (
+	"math"
+	"sort"
+)
+
+type PolyOp int
+
+const (
+	PolyOpUnion PolyOp = iota
+	PolyOpIntersection
+	PolyOpDifference
+	PolyOpXor
+)
+
+func PolyUnion[T Num](a, b []Poly[T]) []Poly[T] {
+	return PolyBoolean(a, b, PolyOpUnion)
+}
+
+func PolyIntersection[T Num](a, b []Poly[T]) []Poly[T] {
+	return PolyBoolean(a, b, PolyOpIntersection)
+}
+
+/* a with b removed */
+func PolyDifference[T Num](a, b []Poly[T]) []Poly[T] {
+	return PolyBoolean(a, b, PolyOpDifference)
+}
+
+func PolyXor[T Num](a, b []Poly[T]) []Poly[T] {
+	return PolyBoolean(a, b, PolyOpXor)
+}
+
+/* Set operation between the regions a and b. Each region is a set of rings of
+ * any winding filled with the even-odd rule, so holes are rings inside others.
+ * Self-intersecting and self-touching rings are allowed.
+ *
+ * Returns outer rings clockwise (positive Area) and holes anti-clockwise
+ * (negative Area). Rings touching at a single vert are returned separately and
+ * collinear verts are removed.
+ */
+func PolyBoolean[T Num](a, b []Poly[T], op PolyOp) []Poly[T] {
+	o := overlay{}
+
+	scale := 0.0
+	for _, region := range [][]Poly[T]{a, b} {
+		for _, ring := range region {
+			for _, v := range ring {
+				scale = math.Max(scale, math.Max(math.Abs(float64(v.X)), math.Abs(float64(v.Y))))
+			}
+		}
+	}
+	o.eps = 1e-9 * math.Max(scale, 1e-300)
+	if isFloat32[T]() {
+		o.eps = 1e-5 * math.Max(scale, 1e-300)
+	}
+	o.pool = vertexPool{cell: o.eps, buckets: map[[2]int64][]int{}}
+
+	for src, region := range [][]Poly[T]{a, b} {
+		for _, ring := range region {
+			for i := range ring {
+				p := Vec2Convert[T, float64](ring[i])
+				q := Vec2Convert[T, float64](ring[(i+1)%len(ring)])
+				if p != q {
+					o.segments = append(o.segments, overlaySegment{p: p, q: q, src: src})
+				}
+			}
+		}
+	}
+
+	o.split()
+	o.classify(op)
+
+	rings := []Poly[T]{}
+	for _, ring := range o.link() {
+		rings = append(rings, PolyConvert[float64, T](ring))
+	}
+	return rings
+}
+
+type vertexPool struct {
+	verts   []Vec2[float64]
+	buckets map[[2]int64][]int
+	cell    float64
+}
+
+/* returns the id of an existing vert within cell of v, or adds v */
+func (pool *vertexPool) id(v Vec2[float64]) int {
+	cx := int64(math.Floor(v.X / pool.cell))
+	cy := int64(math.Floor(v.Y / pool.cell))
+
+	for x := cx - 1; x <= cx+1; x++ {
+		for y := cy - 1; y <= cy+1; y++ {
+			for _, i := range pool.buckets[[2]int64{x, y}] {
+				if d := pool.verts[i].Minus(v); math.Abs(d.X) <= pool.cell && math.Abs(d.Y) <= pool.cell {
+					return i
+				}
+			}
+		}
+	}
+
+	pool.verts = append(pool.verts, v)
+	key := [2]int64{cx, cy}
+	pool.buckets[key] = append(pool.buckets[key], len(pool.verts)-1)
+	return len(pool.verts) - 1
+}
+
+type overlaySegment struct {
+	p, q   Vec2[float64]
+	src    int
+	splits []Vec2[float64]
+}
+
+type overlayEdge struct {
+	a, b   int // pool ids, a < b
+	counts [2]int
+	result int // 1 if the result is on the left of a->b, -1 if on the right
+}
+
+type overlay struct {
+	segments []overlaySegment
+	pool     vertexPool
+	subEdges []overlayEdge // one per split piece, with duplicates
+	edges    []overlayEdge // unique undirected edges
+	eps      float64
+}
+
+func (o *overlay) split() {
+	for i := range o.segments {
+		for j := i + 1; j < len(o.segments); j++ {
+			o.intersect(&o.segments[i], &o.segments[j])
+		}
+	}
+
+	index := map[[2]int]int{}
+	for _, s := range o.segments {
+		d := s.q.Minus(s.p)
+		points := append([]Vec2[float64]{s.p, s.q}, s.splits...)
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Minus(s.p).Dot(d) < points[j].Minus(s.p).Dot(d)
+		})
+
+		ids := []int{}
+		for _, v := range points {
+			id := o.pool.id(v)
+			if len(ids) == 0 || ids[len(ids)-1] != id {
+				ids = append(ids, id)
+			}
+		}
+
+		for k := 0; k+1 < len(ids); k++ {
+			a, b := ids[k], ids[k+1]
+			if a > b {
+				a, b = b, a
+			}
+
+			e := overlayEdge{a: a, b: b}
+			e.counts[s.src] = 1
+			o.subEdges = append(o.subEdges, e)
+
+			key := [2]int{a, b}
+			if i, ok := index[key]; ok {
+				o.edges[i].counts[s.src]++
+			} else {
+				index[key] = len(o.edges)
+				o.edges = append(o.edges, e)
+			}
+		}
+	}
+}
+
+func (o *overlay) intersect(s, t *overlaySegment) {
+	d1 := s.q.Minus(s.p)
+	d2 := t.q.Minus(t.p)
+	denom := d1.Cross(d2)
+	len1, len2 := d1.Len(), d2.Len()
+
+	if math.Abs(denom) <= o.eps*math.Max(len1, len2) { // parallel
+		if math.Abs(t.p.Minus(s.p).Cross(d1)) > o.eps*len1 {
+			return
+		}
+		// collinear, split each at the other's endpoints
+		for _, v := range []Vec2[float64]{t.p, t.q} {
+			if f := v.Minus(s.p).Dot(d1) / (len1 * len1); f > 0 && f < 1 {
+				s.splits = append(s.splits, v)
+			}
+		}
+		for _, v := range []Vec2[float64]{s.p, s.q} {
+			if f := v.Minus(t.p).Dot(d2) / (len2 * len2); f > 0 && f < 1 {
+				t.splits = append(t.splits, v)
+			}
+		}
+		return
+	}
+
+	f1 := t.p.Minus(s.p).Cross(d2) / denom
+	f2 := t.p.Minus(s.p).Cross(d1) / denom
+	tol1, tol2 := o.eps/len1, o.eps/len2
+	if f1 < -tol1 || f1 > 1+tol1 || f2 < -tol2 || f2 > 1+tol2 {
+		return
+	}
+
+	v := s.p.Plus(d1.ScaledBy(f1))
+	switch { // snap to an endpoint when touching
+	case f1 <= tol1:
+		v = s.p
+	case f1 >= 1-tol1:
+		v = s.q
+	case f2 <= tol2:
+		v = t.p
+	case f2 >= 1-tol2:
+		v = t.q
+	}
+
+	s.splits = append(s.splits, v)
+	t.splits = append(t.splits, v)
+}
+
+/* Determines which side of each edge the result is on. A ray is cast from the
+ * edge midpoint to the left, counting crossings of each region's edges.
+ */
+func (o *overlay) classify(op PolyOp) {
+	for i := range o.edges {
+		e := &o.edges[i]
+		p, q := o.pool.verts[e.a], o.pool.verts[e.b]
+		m := p.Plus(q).ScaledBy(0.5)
+		n := q.Minus(p).Perpendicular()
+
+		crossings := [2]int{}
+		for src := range crossings {
+			for _, s := range o.subEdges {
+				if s.counts[src] == 0 || (s.a == e.a && s.b == e.b) {
+					continue
+				}
+
+				u, v := o.pool.verts[s.a], o.pool.verts[s.b]
+				su, sv := n.Cross(u.Minus(m)), n.Cross(v.Minus(m))
+				if (su > 0) == (sv > 0) {
+					continue
+				}
+
+				// distance along the ray to the crossing, interpolating by side so
+				// segments nearly collinear with the ray stay consistent
+				x := u.Plus(v.Minus(u).ScaledBy(su / (su - sv)))
+				if x.Minus(m).Dot(n) > 0 {
+					crossings[src]++
+				}
+			}
+		}
+
+		var left, right [2]bool
+		for src := range crossings {
+			left[src] = crossings[src]%2 == 1
+			right[src] = left[src] != (e.counts[src]%2 == 1)
+		}
+
+		l, r := op.apply(left[0], left[1]), op.apply(right[0], right[1])
+		switch {
+		case l && !r:
+			e.result = 1
+		case r && !l:
+			e.result = -1
+		}
+	}
+}
+
+func (op PolyOp) apply(a, b bool) bool {
+	switch op {
+	case PolyOpUnion:
+		return a || b
+	case PolyOpIntersection:
+		return a && b
+	case PolyOpDifference:
+		return a && !b
+	case PolyOpXor:
+		return a != b
+	}
+	panic("invalid PolyOp")
+}
+
+/* joins the result edges into rings with the result on the left */
+func (o *overlay) link() []Poly[float64] {
+	type directed struct{ from, to int }
+
+	edges := []directed{}
+	outgoing := map[int][]int{}
+	for _, e := range o.edges {
+		d := directed{e.a, e.b}
+		switch e.result {
+		case 0:
+			continue
+		case -1:
+			d = directed{e.b, e.a}
+		}
+		outgoing[d.from] = append(outgoing[d.from], len(edges))
+		edges = append(edges, d)
+	}
+
+	used := make([]bool, len(edges))
+	rings := []Poly[float64]{}
+
+	for start := range edges {
+		if used[start] {
+			continue
+		}
+
+		ids := []int{}
+		for cur := start; !used[cur]; {
+			used[cur] = true
+			ids = append(ids, edges[cur].from)
+
+			// take the first edge clockwise from the way back, keeping touching
+			// rings separate
+			v := o.pool.verts[edges[cur].to]
+			back := o.pool.verts[edges[cur].from].Minus(v)
+			next, best := -1, 0.0
+			for _, k := range outgoing[edges[cur].to] {
+				w := o.pool.verts[edges[k].to].Minus(v)
+				angle := math.Atan2(-back.Cross(w), back.Dot(w))
+				if angle <= 0 {
+					angle += 2 * math.Pi
+				}
+				if next < 0 || angle < best {
+					next, best = k, angle
+				}
+			}
+			if next < 0 {
+				break
+			}
+			cur = next
+		}
+
+		ring := Poly[float64]{}
+		for i, id := range ids { // remove collinear verts
+			prev := o.pool.verts[ids[(i+len(ids)-1)%len(ids)]]
+			next := o.pool.verts[ids[(i+1)%len(ids)]]
+			v := o.pool.verts[id]
+			if d1, d2 := v.Minus(prev), next.Minus(v); math.Abs(d1.Cross(d2)) <= o.eps*(d1.Len()+d2.Len()) && d1.Dot(d2) > 0 {
+				continue
+			}
+			ring = append(ring, v)
+		}
+
+		if len(ring) >= 3 && math.Abs(ring.Area()) > o.eps*o.eps {
+			rings = append(rings, ring)
+		}
+	}
+
+	return rings
+}
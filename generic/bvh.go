@@ -0,0 +1,334 @@
+package geom
+
+import "sort"
+
+const (
+	bvhBins    = 12
+	bvhMaxLeaf = 4 // larger leaves are split even if the cost does not improve
+)
+
+/* Bounding volume hierarchy over boxes. Objects are identified by their index
+ * into the slice given to NewBVH.
+ */
+type BVH[T Num] struct {
+	boxes []Cuboid[T]
+	order []int // object indices, each leaf owns a contiguous range
+	nodes []bvhNode[T]
+}
+
+type bvhNode[T Num] struct {
+	bounds       Cuboid[T]
+	left, right  int // child node indices
+	start, count int // range of order for a leaf, count is 0 for a branch
+}
+
+/* Top-down build using the surface area heuristic with binned centroids */
+func NewBVH[T Num](boxes []Cuboid[T]) *BVH[T] {
+	b := &BVH[T]{
+		boxes: append([]Cuboid[T]{}, boxes...),
+		order: make([]int, len(boxes)),
+	}
+	for i := range b.order {
+		b.order[i] = i
+	}
+	if len(boxes) > 0 {
+		b.build(0, len(boxes))
+	}
+	return b
+}
+
+func (b *BVH[T]) Len() int {
+	return len(b.boxes)
+}
+
+/* Bounds of every object, the zero Cuboid if there are none */
+func (b *BVH[T]) Bounds() Cuboid[T] {
+	if len(b.nodes) == 0 {
+		return Cuboid[T]{}
+	}
+	return b.nodes[0].bounds
+}
+
+/* Updates the boxes after objects have moved, keeping the tree structure.
+ * Cheaper than a rebuild but queries slow down as the tree loses its shape.
+ * boxes must be the same length as those given to NewBVH.
+ */
+func (b *BVH[T]) Refit(boxes []Cuboid[T]) {
+	if len(boxes) != len(b.boxes) {
+		panic("BVH.Refit: number of boxes changed")
+	}
+	copy(b.boxes, boxes)
+
+	// children always follow their parent
+	for i := len(b.nodes) - 1; i >= 0; i-- {
+		n := &b.nodes[i]
+		if n.count > 0 {
+			n.bounds = b.leafBounds(n.start, n.count)
+		} else {
+			n.bounds = b.nodes[n.left].bounds.Union(b.nodes[n.right].bounds)
+		}
+	}
+}
+
+/* Calls fn for each object whose box intersects box, touching faces included.
+ * Stops early if fn returns false.
+ */
+func (b *BVH[T]) Query(box Cuboid[T], fn func(index int) bool) {
+	if len(b.nodes) == 0 {
+		return
+	}
+
+	stack := []int{0}
+	for len(stack) > 0 {
+		n := &b.nodes[stack[len(stack)-1]]
+		stack = stack[:len(stack)-1]
+		if !n.bounds.Intersects(box) {
+			continue
+		}
+
+		if n.count == 0 {
+			stack = append(stack, n.right, n.left)
+			continue
+		}
+		for _, i := range b.order[n.start : n.start+n.count] {
+			if b.boxes[i].Intersects(box) && !fn(i) {
+				return
+			}
+		}
+	}
+}
+
+/* Nearest hit along r. Boxes are visited front to back and fn is called for
+ * each object whose box the ray passes through. fn returns the distance to
+ * the object along r and whether it was hit.
+ */
+func (b *BVH[T]) Raycast(r Ray3[T], fn func(index int) (T, bool)) (index int, dist T, ok bool) {
+	if len(b.nodes) == 0 {
+		return -1, 0, false
+	}
+
+	type visit struct {
+		node  int
+		entry T
+	}
+
+	index = -1
+	entry, hit := r.entry(b.nodes[0].bounds)
+	if !hit {
+		return -1, 0, false
+	}
+
+	stack := []visit{{0, entry}}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if ok && v.entry > dist {
+			continue
+		}
+
+		n := &b.nodes[v.node]
+		if n.count > 0 {
+			for _, i := range b.order[n.start : n.start+n.count] {
+				if _, hit := r.entry(b.boxes[i]); !hit {
+					continue
+				}
+				if d, hit := fn(i); hit && (!ok || d < dist) {
+					index, dist, ok = i, d, true
+				}
+			}
+			continue
+		}
+
+		// push the far child first so the near one is visited first
+		el, hitL := r.entry(b.nodes[n.left].bounds)
+		er, hitR := r.entry(b.nodes[n.right].bounds)
+		near, far := visit{n.left, el}, visit{n.right, er}
+		if hitL && hitR && er < el {
+			near, far = far, near
+			hitL, hitR = hitR, hitL
+		}
+		if hitR {
+			stack = append(stack, far)
+		}
+		if hitL {
+			stack = append(stack, near)
+		}
+	}
+
+	return index, dist, ok
+}
+
+/* Calls fn for each pair of objects, i from a and j from b, whose boxes
+ * intersect. Stops early if fn returns false.
+ */
+func (a *BVH[T]) Overlaps(b *BVH[T], fn func(i, j int) bool) {
+	if len(a.nodes) == 0 || len(b.nodes) == 0 {
+		return
+	}
+
+	stack := [][2]int{{0, 0}}
+	for len(stack) > 0 {
+		pair := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		na, nb := &a.nodes[pair[0]], &b.nodes[pair[1]]
+		if !na.bounds.Intersects(nb.bounds) {
+			continue
+		}
+
+		switch {
+		case na.count > 0 && nb.count > 0:
+			for _, i := range a.order[na.start : na.start+na.count] {
+				for _, j := range b.order[nb.start : nb.start+nb.count] {
+					if a.boxes[i].Intersects(b.boxes[j]) && !fn(i, j) {
+						return
+					}
+				}
+			}
+		case nb.count > 0 || (na.count == 0 && na.bounds.halfArea() > nb.bounds.halfArea()):
+			// descend the larger branch
+			stack = append(stack, [2]int{na.left, pair[1]}, [2]int{na.right, pair[1]})
+		default:
+			stack = append(stack, [2]int{pair[0], nb.left}, [2]int{pair[0], nb.right})
+		}
+	}
+}
+
+func (b *BVH[T]) leafBounds(start, count int) Cuboid[T] {
+	bounds := b.boxes[b.order[start]]
+	for _, i := range b.order[start+1 : start+count] {
+		bounds = bounds.Union(b.boxes[i])
+	}
+	return bounds
+}
+
+/* builds the node for order[start:start+count], returning its index */
+func (b *BVH[T]) build(start, count int) int {
+	node := len(b.nodes)
+	b.nodes = append(b.nodes, bvhNode[T]{
+		bounds: b.leafBounds(start, count),
+		start:  start,
+		count:  count,
+	})
+
+	mid, ok := b.split(start, count, b.nodes[node].bounds)
+	if !ok {
+		return node
+	}
+
+	left := b.build(start, mid-start)
+	right := b.build(mid, start+count-mid)
+	b.nodes[node].left = left
+	b.nodes[node].right = right
+	b.nodes[node].count = 0
+	return node
+}
+
+/* Partitions order[start:start+count] by the cheapest binned split, returning
+ * the start of the right half. Returns false if a leaf is cheaper.
+ */
+func (b *BVH[T]) split(start, count int, bounds Cuboid[T]) (int, bool) {
+	if count <= 1 {
+		return 0, false
+	}
+
+	items := b.order[start : start+count]
+	points := make([]Vec3[T], len(items))
+	for k, i := range items {
+		points[k] = b.boxes[i].Centre()
+	}
+	centres := CuboidBounds(points)
+
+	bestAxis, bestBin := -1, 0
+	bestCost := T(count) // cost of a leaf, one per object
+	parentArea := bounds.halfArea()
+
+	for axis := 0; axis < 3; axis++ {
+		lo, hi := vec3Axis(centres.Min, axis), vec3Axis(centres.Max, axis)
+		if hi <= lo {
+			continue
+		}
+
+		bins := [bvhBins]bvhBin[T]{}
+		for _, i := range items {
+			k := bvhBinIndex(vec3Axis(b.boxes[i].Centre(), axis), lo, hi)
+			bins[k] = bins[k].merge(bvhBin[T]{b.boxes[i], 1})
+		}
+
+		// sweep from the right accumulating the cost of each right half
+		var rightCost [bvhBins]T
+		var acc bvhBin[T]
+		for k := bvhBins - 1; k > 0; k-- {
+			acc = acc.merge(bins[k])
+			rightCost[k] = acc.bounds.halfArea() * T(acc.count)
+		}
+
+		acc = bvhBin[T]{}
+		for k := 0; k < bvhBins-1; k++ {
+			acc = acc.merge(bins[k])
+			if acc.count == 0 || acc.count == count {
+				continue
+			}
+
+			cost := 1 + (acc.bounds.halfArea()*T(acc.count)+rightCost[k+1])/parentArea
+			if parentArea == 0 {
+				cost = T(count) // zero sized boxes, any split is as good as a leaf
+			}
+			if cost < bestCost {
+				bestAxis, bestBin, bestCost = axis, k, cost
+			}
+		}
+	}
+
+	if bestAxis < 0 {
+		if count <= bvhMaxLeaf {
+			return 0, false
+		}
+
+		// no split improves the cost, halve along the widest axis to bound leaf size
+		size := centres.Max.Minus(centres.Min)
+		axis := 0
+		for a := 1; a < 3; a++ {
+			if vec3Axis(size, a) > vec3Axis(size, axis) {
+				axis = a
+			}
+		}
+		sort.Slice(items, func(i, j int) bool {
+			return vec3Axis(b.boxes[items[i]].Centre(), axis) < vec3Axis(b.boxes[items[j]].Centre(), axis)
+		})
+		return start + count/2, true
+	}
+
+	lo, hi := vec3Axis(centres.Min, bestAxis), vec3Axis(centres.Max, bestAxis)
+	mid := 0
+	for k, i := range items {
+		if bvhBinIndex(vec3Axis(b.boxes[i].Centre(), bestAxis), lo, hi) <= bestBin {
+			items[mid], items[k] = items[k], items[mid]
+			mid++
+		}
+	}
+	return start + mid, true
+}
+
+type bvhBin[T Num] struct {
+	bounds Cuboid[T]
+	count  int
+}
+
+func bvhBinIndex[T Num](c, lo, hi T) int {
+	k := int(T(bvhBins) * (c - lo) / (hi - lo))
+	if k >= bvhBins {
+		k = bvhBins - 1
+	}
+	return k
+}
+
+func (a bvhBin[T]) merge(b bvhBin[T]) bvhBin[T] {
+	switch {
+	case b.count == 0:
+		return a
+	case a.count == 0:
+		return b
+	}
+	return bvhBin[T]{a.bounds.Union(b.bounds), a.count + b.count}
+}
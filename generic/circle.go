@@ -0,0 +1,15 @@
+package geom
+
+type Circle[T Num] struct {
+	Centre Vec2[T]
+	Radius T
+}
+
+/* Points on the circumference count as contained */
+func (c Circle[T]) Contains(v Vec2[T]) bool {
+	return v.Minus(c.Centre).Len2() <= c.Radius*c.Radius
+}
+
+func (c Circle[T]) Bounds() Rect[T] {
+	return RectCentredAt(2*c.Radius, 2*c.Radius, c.Centre)
+}
@@ -0,0 +1,419 @@
+package geom
+
+import "math"
+
+/* Contact between two overlapping shapes. Normal is unit length and points
+ * from the first shape towards the second, moving the second by Normal*Depth
+ * separates them. Points lie midway between the two surfaces, only the first
+ * NumPoints are used. Touching shapes collide with zero Depth.
+ */
+type Manifold2[T Num] struct {
+	Normal    Vec2[T]
+	Depth     T
+	Points    [2]Vec2[T]
+	NumPoints int
+}
+
+/* Distance between convex polys of either winding, with the closest point on
+ * each. Returns zero if they overlap, with a shared point for both.
+ */
+func ConvexDistance[T Num](a, b Poly[T]) (T, Vec2[T], Vec2[T]) {
+	ca, cb := convexPoly(a, 0), convexPoly(b, 0)
+	s := gjk(&ca, &cb, convexEps(&ca, &cb))
+	pa, pb := s.closest()
+	return T(s.point.Len()), Vec2Convert[float64, T](pa), Vec2Convert[float64, T](pb)
+}
+
+/* GJK for separated polys and EPA for overlapping ones. a and b must be convex
+ * and may have either winding.
+ */
+func CollidePolys[T Num](a, b Poly[T]) (Manifold2[T], bool) {
+	ca, cb := convexPoly(a, 0), convexPoly(b, 0)
+	return collide[T](&ca, &cb)
+}
+
+/* a must be convex and may have either winding */
+func CollidePolyCircle[T Num](a Poly[T], b Circle[T]) (Manifold2[T], bool) {
+	ca := convexPoly(a, 0)
+	cb := convexPoly(Poly[T]{b.Centre}, b.Radius)
+	return collide[T](&ca, &cb)
+}
+
+/* a must be convex and may have either winding */
+func CollidePolyRect[T Num](a Poly[T], b Rect[T]) (Manifold2[T], bool) {
+	verts := b.Verts()
+	return CollidePolys(a, verts[:])
+}
+
+func CollideRectCircle[T Num](a Rect[T], b Circle[T]) (Manifold2[T], bool) {
+	verts := a.Verts()
+	return CollidePolyCircle(verts[:], b)
+}
+
+func CollideCircles[T Num](a, b Circle[T]) (Manifold2[T], bool) {
+	d := b.Centre.Minus(a.Centre)
+	dist := d.Len()
+	if dist > a.Radius+b.Radius {
+		return Manifold2[T]{}, false
+	}
+
+	n := Vec2[T]{1, 0} // concentric, any direction separates
+	if dist > 0 {
+		n = d.ScaledBy(1 / dist)
+	}
+
+	depth := a.Radius + b.Radius - dist
+	return Manifold2[T]{
+		Normal:    n,
+		Depth:     depth,
+		Points:    [2]Vec2[T]{a.Centre.Plus(n.ScaledBy(a.Radius - depth/2))},
+		NumPoints: 1,
+	}, true
+}
+
+/* Separating axis test, with two points for overlapping edges */
+func CollideRects[T Num](a, b Rect[T]) (Manifold2[T], bool) {
+	overlap, ok := a.Intersection(b)
+	if !ok {
+		return Manifold2[T]{}, false
+	}
+
+	ca, cb := a.Centre(), b.Centre()
+	m := Manifold2[T]{NumPoints: 2}
+
+	if overlap.Width() < overlap.Height() {
+		m.Normal, m.Depth = Vec2[T]{1, 0}, overlap.Width()
+		if cb.X < ca.X {
+			m.Normal.X = -1
+		}
+		x := (overlap.Min.X + overlap.Max.X) / 2
+		m.Points = [2]Vec2[T]{{x, overlap.Min.Y}, {x, overlap.Max.Y}}
+	} else {
+		m.Normal, m.Depth = Vec2[T]{0, 1}, overlap.Height()
+		if cb.Y < ca.Y {
+			m.Normal.Y = -1
+		}
+		y := (overlap.Min.Y + overlap.Max.Y) / 2
+		m.Points = [2]Vec2[T]{{overlap.Min.X, y}, {overlap.Max.X, y}}
+	}
+
+	if m.Points[0] == m.Points[1] {
+		m.NumPoints = 1
+	}
+	return m, true
+}
+
+/* convex polygon, segment or point inflated by radius */
+type convex2 struct {
+	verts  []Vec2[float64]
+	radius float64
+}
+
+func convexPoly[T Num](poly Poly[T], radius T) convex2 {
+	if len(poly) == 0 {
+		panic("must have at least one vert")
+	}
+	return convex2{PolyConvert[T, float64](poly), float64(radius)}
+}
+
+func (c *convex2) support(d Vec2[float64]) Vec2[float64] {
+	best, bestDot := c.verts[0], c.verts[0].Dot(d)
+	for _, v := range c.verts[1:] {
+		if dot := v.Dot(d); dot > bestDot {
+			best, bestDot = v, dot
+		}
+	}
+	return best
+}
+
+/* outward normal of each edge, zero for repeated verts */
+func (c *convex2) normals() []Vec2[float64] {
+	sign := 1.0
+	if Poly[float64](c.verts).Area() < 0 {
+		sign = -1
+	}
+
+	normals := make([]Vec2[float64], len(c.verts))
+	for i, v := range c.verts {
+		e := c.verts[(i+1)%len(c.verts)].Minus(v)
+		normals[i] = Vec2[float64]{e.Y, -e.X}.ScaledBy(sign).Normal()
+	}
+	return normals
+}
+
+func convexEps(a, b *convex2) float64 {
+	scale := a.radius + b.radius
+	for _, c := range []*convex2{a, b} {
+		for _, v := range c.verts {
+			scale = math.Max(scale, math.Max(math.Abs(v.X), math.Abs(v.Y)))
+		}
+	}
+	return 1e-10 * math.Max(scale, 1e-300)
+}
+
+/* point of the Minkowski difference a-b, with the points of a and b */
+type gjkVert struct {
+	w, a, b Vec2[float64]
+}
+
+func minkowski(a, b *convex2, d Vec2[float64]) gjkVert {
+	pa, pb := a.support(d), b.support(d.ScaledBy(-1))
+	return gjkVert{pa.Minus(pb), pa, pb}
+}
+
+type gjkSimplex struct {
+	verts  []gjkVert
+	lambda []float64 // barycentric weights of point
+	point  Vec2[float64]
+}
+
+/* closest points on a and b */
+func (s gjkSimplex) closest() (pa, pb Vec2[float64]) {
+	for i, v := range s.verts {
+		pa = pa.Plus(v.a.ScaledBy(s.lambda[i]))
+		pb = pb.Plus(v.b.ScaledBy(s.lambda[i]))
+	}
+	return pa, pb
+}
+
+/* Closest point of the Minkowski difference a-b to the origin. The cores of a
+ * and b overlap if it is within eps of the origin.
+ */
+func gjk(a, b *convex2, eps float64) gjkSimplex {
+	s := gjkSimplex{verts: []gjkVert{minkowski(a, b, a.verts[0].Minus(b.verts[0]).ScaledBy(-1))}}
+
+	for iter := 0; iter < 64; iter++ {
+		s = gjkReduce(s.verts)
+		if s.point.Len() <= eps {
+			break
+		}
+
+		w := minkowski(a, b, s.point.ScaledBy(-1))
+		if s.point.Len2()-s.point.Dot(w.w) <= eps*s.point.Len() {
+			break // no progress towards the origin
+		}
+		s.verts = append(s.verts, w)
+	}
+
+	return s
+}
+
+/* reduces verts to the smallest sub-simplex containing the closest point */
+func gjkReduce(verts []gjkVert) gjkSimplex {
+	switch len(verts) {
+	case 1:
+		return gjkSimplex{verts, []float64{1}, verts[0].w}
+	case 2:
+		return gjkSegment(verts[0], verts[1])
+	}
+
+	a, b, c := verts[0].w, verts[1].w, verts[2].w
+	if area := b.Minus(a).Cross(c.Minus(a)); area != 0 {
+		la, lb, lc := b.Cross(c)/area, c.Cross(a)/area, a.Cross(b)/area
+		if la >= 0 && lb >= 0 && lc >= 0 { // contains the origin
+			return gjkSimplex{verts, []float64{la, lb, lc}, Vec2[float64]{}}
+		}
+	}
+
+	best := gjkSimplex{}
+	for i := 0; i < 3; i++ {
+		s := gjkSegment(verts[i], verts[(i+1)%3])
+		if i == 0 || s.point.Len2() < best.point.Len2() {
+			best = s
+		}
+	}
+	return best
+}
+
+func gjkSegment(a, b gjkVert) gjkSimplex {
+	e := b.w.Minus(a.w)
+	l2 := e.Len2()
+	t := 0.0
+	if l2 > 0 {
+		t = -a.w.Dot(e) / l2
+	}
+
+	switch {
+	case t <= 0:
+		return gjkSimplex{[]gjkVert{a}, []float64{1}, a.w}
+	case t >= 1:
+		return gjkSimplex{[]gjkVert{b}, []float64{1}, b.w}
+	}
+	return gjkSimplex{[]gjkVert{a, b}, []float64{1 - t, t}, a.w.Plus(e.ScaledBy(t))}
+}
+
+/* Expanding polytope. Returns the normal and depth of the smallest translation
+ * separating the cores of a and b, given a simplex containing the origin.
+ */
+func epa(a, b *convex2, s gjkSimplex, eps float64) (Vec2[float64], float64) {
+	poly := append([]gjkVert{}, s.verts...)
+
+	// grow a point or segment into a triangle
+	for _, d := range []Vec2[float64]{{1, 0}, {-1, 0}, {0, 1}, {0, -1}} {
+		if len(poly) != 1 {
+			break
+		}
+		if w := minkowski(a, b, d); w.w.Minus(poly[0].w).Len() > eps {
+			poly = append(poly, w)
+		}
+	}
+	if len(poly) == 1 {
+		return Vec2[float64]{1, 0}, 0 // both are points
+	}
+
+	if len(poly) == 2 {
+		n := poly[1].w.Minus(poly[0].w).Perpendicular().Normal()
+		for _, d := range []Vec2[float64]{n, n.ScaledBy(-1)} {
+			if w := minkowski(a, b, d); math.Abs(w.w.Minus(poly[0].w).Dot(n)) > eps {
+				poly = append(poly, w)
+				break
+			}
+		}
+		if len(poly) == 2 {
+			return n, 0 // the difference is a segment through the origin
+		}
+	}
+
+	if poly[1].w.Minus(poly[0].w).Cross(poly[2].w.Minus(poly[0].w)) < 0 {
+		poly[1], poly[2] = poly[2], poly[1]
+	}
+
+	var normal Vec2[float64]
+	var depth float64
+	for iter := 0; iter < 64; iter++ {
+		edge := -1
+		for i := range poly {
+			p, q := poly[i].w, poly[(i+1)%len(poly)].w
+			e := q.Minus(p)
+			if e.Len2() == 0 {
+				continue
+			}
+
+			n := Vec2[float64]{e.Y, -e.X}.Normal()
+			if d := n.Dot(p); edge < 0 || d < depth {
+				edge, normal, depth = i, n, d
+			}
+		}
+
+		w := minkowski(a, b, normal)
+		if w.w.Dot(normal)-depth <= eps {
+			break
+		}
+
+		poly = append(poly[:edge+1], append([]gjkVert{w}, poly[edge+1:]...)...)
+	}
+
+	return normal, math.Max(depth, 0)
+}
+
+func collide[T Num](a, b *convex2) (Manifold2[T], bool) {
+	eps := convexEps(a, b)
+	s := gjk(a, b, eps)
+	r := a.radius + b.radius
+
+	var normal Vec2[float64]
+	var depth float64
+	var points [2]Vec2[float64]
+	count := 1
+
+	if dist := s.point.Len(); dist > eps {
+		if dist > r {
+			return Manifold2[T]{}, false
+		}
+
+		pa, pb := s.closest()
+		normal = pb.Minus(pa).ScaledBy(1 / dist)
+		depth = r - dist
+		points[0] = pa.Plus(normal.ScaledBy(a.radius - depth/2))
+	} else {
+		normal, depth = epa(a, b, s, eps)
+		depth += r
+
+		switch {
+		case len(b.verts) == 1:
+			points[0] = b.verts[0].Minus(normal.ScaledBy(b.radius - depth/2))
+		case len(a.verts) == 1:
+			points[0] = a.verts[0].Plus(normal.ScaledBy(a.radius - depth/2))
+		default:
+			points, count = clipContacts(a, b, normal, eps)
+		}
+	}
+
+	return Manifold2[T]{
+		Normal:    Vec2Convert[float64, T](normal),
+		Depth:     T(depth),
+		Points:    [2]Vec2[T]{Vec2Convert[float64, T](points[0]), Vec2Convert[float64, T](points[1])},
+		NumPoints: count,
+	}, true
+}
+
+/* Clips the incident edge against the side planes of the reference edge, the
+ * edge of either shape most aligned with normal.
+ */
+func clipContacts(a, b *convex2, normal Vec2[float64], eps float64) ([2]Vec2[float64], int) {
+	na, nb := a.normals(), b.normals()
+	fa := convexFace(na, normal)
+	fb := convexFace(nb, normal.ScaledBy(-1))
+
+	ref, inc := a, b
+	refNormals, incNormals := na, nb
+	face := fa
+	if nb[fb].Dot(normal.ScaledBy(-1)) > na[fa].Dot(normal)+1e-3 { // prefer a
+		ref, inc = b, a
+		refNormals, incNormals = nb, na
+		face = fb
+	}
+
+	m := refNormals[face]
+	v1, v2 := ref.verts[face], ref.verts[(face+1)%len(ref.verts)]
+	in := convexFace(incNormals, m.ScaledBy(-1))
+	p1, p2 := inc.verts[in], inc.verts[(in+1)%len(inc.verts)]
+
+	t := v2.Minus(v1).Normal()
+	var ok bool
+	if p1, p2, ok = clipSegment(p1, p2, t.ScaledBy(-1), -t.Dot(v1)); ok {
+		p1, p2, ok = clipSegment(p1, p2, t, t.Dot(v2))
+	}
+
+	var points [2]Vec2[float64]
+	count := 0
+	if ok {
+		for _, p := range []Vec2[float64]{p1, p2} {
+			if sep := m.Dot(p.Minus(v1)); sep <= eps && (count == 0 || p.Minus(points[0]).Len() > eps) {
+				points[count] = p.Minus(m.ScaledBy(sep / 2))
+				count++
+			}
+		}
+	}
+
+	if count == 0 { // numerical fallback, the deepest points
+		points[0] = a.support(normal).Plus(b.support(normal.ScaledBy(-1))).ScaledBy(0.5)
+		count = 1
+	}
+	return points, count
+}
+
+/* index of the edge whose normal is most aligned with d */
+func convexFace(normals []Vec2[float64], d Vec2[float64]) int {
+	best := 0
+	for i := range normals {
+		if normals[i].Dot(d) > normals[best].Dot(d) {
+			best = i
+		}
+	}
+	return best
+}
+
+/* keeps the part of segment pq where n.Dot(v) <= offset */
+func clipSegment(p, q, n Vec2[float64], offset float64) (Vec2[float64], Vec2[float64], bool) {
+	dp, dq := n.Dot(p)-offset, n.Dot(q)-offset
+	switch {
+	case dp > 0 && dq > 0:
+		return p, q, false
+	case dp > 0:
+		p = p.Plus(q.Minus(p).ScaledBy(dp / (dp - dq)))
+	case dq > 0:
+		q = q.Plus(p.Minus(q).ScaledBy(dq / (dq - dp)))
+	}
+	return p, q, true
+}
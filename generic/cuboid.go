@@ -38,3 +38,84 @@ func (c Cuboid[T]) Contains(v Vec3[T]) bool {
 		v.Z >= c.Min.Z &&
 		v.Z <= c.Max.Z
 }
+
+/* Bounding cuboid of points, the zero Cuboid if there are none */
+func CuboidBounds[T Num](points []Vec3[T]) Cuboid[T] {
+	if len(points) == 0 {
+		return Cuboid[T]{}
+	}
+
+	c := Cuboid[T]{points[0], points[0]}
+	for _, v := range points[1:] {
+		c = c.ExpandToInclude(v)
+	}
+	return c
+}
+
+func (c Cuboid[T]) Centre() Vec3[T] {
+	return c.Min.Plus(c.Max).ScaledBy(0.5)
+}
+
+/* Touching faces count as intersecting */
+func (a Cuboid[T]) Intersects(b Cuboid[T]) bool {
+	return a.Min.X <= b.Max.X &&
+		a.Max.X >= b.Min.X &&
+		a.Min.Y <= b.Max.Y &&
+		a.Max.Y >= b.Min.Y &&
+		a.Min.Z <= b.Max.Z &&
+		a.Max.Z >= b.Min.Z
+}
+
+/* Returns false if a and b do not intersect */
+func (a Cuboid[T]) Intersection(b Cuboid[T]) (Cuboid[T], bool) {
+	if !a.Intersects(b) {
+		return Cuboid[T]{}, false
+	}
+	return Cuboid[T]{
+		Min: Vec3[T]{max(a.Min.X, b.Min.X), max(a.Min.Y, b.Min.Y), max(a.Min.Z, b.Min.Z)},
+		Max: Vec3[T]{min(a.Max.X, b.Max.X), min(a.Max.Y, b.Max.Y), min(a.Max.Z, b.Max.Z)},
+	}, true
+}
+
+/* Smallest cuboid containing a and b */
+func (a Cuboid[T]) Union(b Cuboid[T]) Cuboid[T] {
+	return Cuboid[T]{
+		Min: Vec3[T]{min(a.Min.X, b.Min.X), min(a.Min.Y, b.Min.Y), min(a.Min.Z, b.Min.Z)},
+		Max: Vec3[T]{max(a.Max.X, b.Max.X), max(a.Max.Y, b.Max.Y), max(a.Max.Z, b.Max.Z)},
+	}
+}
+
+func (c Cuboid[T]) ExpandToInclude(v Vec3[T]) Cuboid[T] {
+	return Cuboid[T]{
+		Min: Vec3[T]{min(c.Min.X, v.X), min(c.Min.Y, v.Y), min(c.Min.Z, v.Z)},
+		Max: Vec3[T]{max(c.Max.X, v.X), max(c.Max.Y, v.Y), max(c.Max.Z, v.Z)},
+	}
+}
+
+/* Moves each face inwards by margin, collapsing to the centre if too small */
+func (c Cuboid[T]) Inset(margin T) Cuboid[T] {
+	m := c.Centre()
+	return Cuboid[T]{
+		Min: Vec3[T]{min(c.Min.X+margin, m.X), min(c.Min.Y+margin, m.Y), min(c.Min.Z+margin, m.Z)},
+		Max: Vec3[T]{max(c.Max.X-margin, m.X), max(c.Max.Y-margin, m.Y), max(c.Max.Z-margin, m.Z)},
+	}
+}
+
+func (c Cuboid[T]) Outset(margin T) Cuboid[T] {
+	return c.Inset(-margin)
+}
+
+/* Closest point in c to v */
+func (c Cuboid[T]) Clamp(v Vec3[T]) Vec3[T] {
+	return Vec3[T]{
+		clamp(v.X, c.Min.X, c.Max.X),
+		clamp(v.Y, c.Min.Y, c.Max.Y),
+		clamp(v.Z, c.Min.Z, c.Max.Z),
+	}
+}
+
+/* half the surface area */
+func (c Cuboid[T]) halfArea() T {
+	w, h, d := c.Width(), c.Height(), c.Depth()
+	return w*h + h*d + d*w
+}
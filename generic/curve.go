@@ -0,0 +1,649 @@
+package geom
+
+import (
+	"math"
+	"sort"
+)
+
+const curveFlattenDepth = 16 // maximum subdivisions of a segment when flattening
+
+/* Curves are parameterised by t from 0 to 1. Splines are made of cubic Bézier
+ * segments which each take an equal share of t.
+ */
+
+/* Bézier curve of any degree, quadratic with 3 points and cubic with 4 */
+type Bezier2[T Num] []Vec2[T]
+
+/* Bézier curve of any degree, quadratic with 3 points and cubic with 4 */
+type Bezier3[T Num] []Vec3[T]
+
+/* Centripetal Catmull-Rom spline passing through every point. The ends are
+ * extended by reflecting the neighbouring point. Needs at least 2 points.
+ */
+type CatmullRom2[T Num] []Vec2[T]
+
+/* Centripetal Catmull-Rom spline passing through every point. The ends are
+ * extended by reflecting the neighbouring point. Needs at least 2 points.
+ */
+type CatmullRom3[T Num] []Vec3[T]
+
+/* Uniform cubic B-spline over control points. Needs at least 4 points. */
+type BSpline2[T Num] []Vec2[T]
+
+/* Uniform cubic B-spline over control points. Needs at least 4 points. */
+type BSpline3[T Num] []Vec3[T]
+
+/* Maps distance along a curve to the curve parameter */
+type ArcLength[T Num] struct {
+	params  []T
+	lengths []T
+}
+
+func (b Bezier2[T]) At(t T) Vec2[T] {
+	return bezierAt(b, t)
+}
+
+/* First derivative with respect to t */
+func (b Bezier2[T]) Derivative(t T) Vec2[T] {
+	return bezierAt(bezierHodograph(b), t)
+}
+
+/* Second derivative with respect to t */
+func (b Bezier2[T]) SecondDerivative(t T) Vec2[T] {
+	return bezierAt(bezierHodograph(bezierHodograph(b)), t)
+}
+
+/* Curves covering 0 to t and t to 1 */
+func (b Bezier2[T]) Split(t T) (Bezier2[T], Bezier2[T]) {
+	left, right := bezierSplit(b, t)
+	return left, right
+}
+
+/* Exact for curves up to cubic, the bounds of the points otherwise */
+func (b Bezier2[T]) Bounds() Rect[T] {
+	if len(b) > 4 {
+		return RectBounds(b)
+	}
+
+	xs, ys := make([]T, len(b)), make([]T, len(b))
+	for i, p := range b {
+		xs[i], ys[i] = p.X, p.Y
+	}
+
+	r := RectBounds([]Vec2[T]{b[0], b[len(b)-1]})
+	for _, t := range append(bezierExtrema(xs), bezierExtrema(ys)...) {
+		r = r.ExpandToInclude(b.At(t))
+	}
+	return r
+}
+
+/* Polyline within tolerance of the curve */
+func (b Bezier2[T]) Flatten(tolerance T) Poly[T] {
+	points, _ := flattenBeziers([][]Vec2[T]{b}, tolerance, false)
+	return points
+}
+
+/* Arc length table accurate to the flattening tolerance */
+func (b Bezier2[T]) ArcLength(tolerance T) ArcLength[T] {
+	return newArcLength(flattenBeziers([][]Vec2[T]{b}, tolerance, true))
+}
+
+func (b Bezier3[T]) At(t T) Vec3[T] {
+	return bezierAt(b, t)
+}
+
+/* First derivative with respect to t */
+func (b Bezier3[T]) Derivative(t T) Vec3[T] {
+	return bezierAt(bezierHodograph(b), t)
+}
+
+/* Second derivative with respect to t */
+func (b Bezier3[T]) SecondDerivative(t T) Vec3[T] {
+	return bezierAt(bezierHodograph(bezierHodograph(b)), t)
+}
+
+/* Curves covering 0 to t and t to 1 */
+func (b Bezier3[T]) Split(t T) (Bezier3[T], Bezier3[T]) {
+	left, right := bezierSplit(b, t)
+	return left, right
+}
+
+/* Exact for curves up to cubic, the bounds of the points otherwise */
+func (b Bezier3[T]) Bounds() Cuboid[T] {
+	if len(b) > 4 {
+		return CuboidBounds(b)
+	}
+
+	xs, ys, zs := make([]T, len(b)), make([]T, len(b)), make([]T, len(b))
+	for i, p := range b {
+		xs[i], ys[i], zs[i] = p.X, p.Y, p.Z
+	}
+
+	c := CuboidBounds([]Vec3[T]{b[0], b[len(b)-1]})
+	ts := append(bezierExtrema(xs), bezierExtrema(ys)...)
+	for _, t := range append(ts, bezierExtrema(zs)...) {
+		c = c.ExpandToInclude(b.At(t))
+	}
+	return c
+}
+
+/* Polyline within tolerance of the curve */
+func (b Bezier3[T]) Flatten(tolerance T) []Vec3[T] {
+	points, _ := flattenBeziers([][]Vec3[T]{b}, tolerance, false)
+	return points
+}
+
+/* Arc length table accurate to the flattening tolerance */
+func (b Bezier3[T]) ArcLength(tolerance T) ArcLength[T] {
+	return newArcLength(flattenBeziers([][]Vec3[T]{b}, tolerance, true))
+}
+
+/* Cubic Bézier segments between consecutive points */
+func (c CatmullRom2[T]) Beziers() []Bezier2[T] {
+	beziers := make([]Bezier2[T], 0, len(c))
+	for _, b := range catmullRomBeziers(c) {
+		beziers = append(beziers, b)
+	}
+	return beziers
+}
+
+/* Passes through point i at t = i/(len-1) */
+func (c CatmullRom2[T]) At(t T) Vec2[T] {
+	return splineAt(catmullRomBeziers(c), t, 0)
+}
+
+/* First derivative with respect to t */
+func (c CatmullRom2[T]) Derivative(t T) Vec2[T] {
+	return splineAt(catmullRomBeziers(c), t, 1)
+}
+
+/* Second derivative with respect to t */
+func (c CatmullRom2[T]) SecondDerivative(t T) Vec2[T] {
+	return splineAt(catmullRomBeziers(c), t, 2)
+}
+
+/* Bézier segments covering 0 to t and t to 1, the segment containing t is split */
+func (c CatmullRom2[T]) Split(t T) ([]Bezier2[T], []Bezier2[T]) {
+	left, right := splineSplit(catmullRomBeziers(c), t)
+	return bezier2s(left), bezier2s(right)
+}
+
+func (c CatmullRom2[T]) Bounds() Rect[T] {
+	return bezier2Bounds(c.Beziers())
+}
+
+/* Polyline within tolerance of the curve */
+func (c CatmullRom2[T]) Flatten(tolerance T) Poly[T] {
+	points, _ := flattenBeziers(catmullRomBeziers(c), tolerance, false)
+	return points
+}
+
+/* Arc length table accurate to the flattening tolerance */
+func (c CatmullRom2[T]) ArcLength(tolerance T) ArcLength[T] {
+	return newArcLength(flattenBeziers(catmullRomBeziers(c), tolerance, true))
+}
+
+/* Cubic Bézier segments between consecutive points */
+func (c CatmullRom3[T]) Beziers() []Bezier3[T] {
+	beziers := make([]Bezier3[T], 0, len(c))
+	for _, b := range catmullRomBeziers(c) {
+		beziers = append(beziers, b)
+	}
+	return beziers
+}
+
+/* Passes through point i at t = i/(len-1) */
+func (c CatmullRom3[T]) At(t T) Vec3[T] {
+	return splineAt(catmullRomBeziers(c), t, 0)
+}
+
+/* First derivative with respect to t */
+func (c CatmullRom3[T]) Derivative(t T) Vec3[T] {
+	return splineAt(catmullRomBeziers(c), t, 1)
+}
+
+/* Second derivative with respect to t */
+func (c CatmullRom3[T]) SecondDerivative(t T) Vec3[T] {
+	return splineAt(catmullRomBeziers(c), t, 2)
+}
+
+/* Bézier segments covering 0 to t and t to 1, the segment containing t is split */
+func (c CatmullRom3[T]) Split(t T) ([]Bezier3[T], []Bezier3[T]) {
+	left, right := splineSplit(catmullRomBeziers(c), t)
+	return bezier3s(left), bezier3s(right)
+}
+
+func (c CatmullRom3[T]) Bounds() Cuboid[T] {
+	return bezier3Bounds(c.Beziers())
+}
+
+/* Polyline within tolerance of the curve */
+func (c CatmullRom3[T]) Flatten(tolerance T) []Vec3[T] {
+	points, _ := flattenBeziers(catmullRomBeziers(c), tolerance, false)
+	return points
+}
+
+/* Arc length table accurate to the flattening tolerance */
+func (c CatmullRom3[T]) ArcLength(tolerance T) ArcLength[T] {
+	return newArcLength(flattenBeziers(catmullRomBeziers(c), tolerance, true))
+}
+
+/* Cubic Bézier segments, one for each run of 4 control points */
+func (s BSpline2[T]) Beziers() []Bezier2[T] {
+	beziers := make([]Bezier2[T], 0, len(s))
+	for _, b := range bSplineBeziers(s) {
+		beziers = append(beziers, b)
+	}
+	return beziers
+}
+
+func (s BSpline2[T]) At(t T) Vec2[T] {
+	return splineAt(bSplineBeziers(s), t, 0)
+}
+
+/* First derivative with respect to t */
+func (s BSpline2[T]) Derivative(t T) Vec2[T] {
+	return splineAt(bSplineBeziers(s), t, 1)
+}
+
+/* Second derivative with respect to t */
+func (s BSpline2[T]) SecondDerivative(t T) Vec2[T] {
+	return splineAt(bSplineBeziers(s), t, 2)
+}
+
+/* Bézier segments covering 0 to t and t to 1, the segment containing t is split */
+func (s BSpline2[T]) Split(t T) ([]Bezier2[T], []Bezier2[T]) {
+	left, right := splineSplit(bSplineBeziers(s), t)
+	return bezier2s(left), bezier2s(right)
+}
+
+func (s BSpline2[T]) Bounds() Rect[T] {
+	return bezier2Bounds(s.Beziers())
+}
+
+/* Polyline within tolerance of the curve */
+func (s BSpline2[T]) Flatten(tolerance T) Poly[T] {
+	points, _ := flattenBeziers(bSplineBeziers(s), tolerance, false)
+	return points
+}
+
+/* Arc length table accurate to the flattening tolerance */
+func (s BSpline2[T]) ArcLength(tolerance T) ArcLength[T] {
+	return newArcLength(flattenBeziers(bSplineBeziers(s), tolerance, true))
+}
+
+/* Cubic Bézier segments, one for each run of 4 control points */
+func (s BSpline3[T]) Beziers() []Bezier3[T] {
+	beziers := make([]Bezier3[T], 0, len(s))
+	for _, b := range bSplineBeziers(s) {
+		beziers = append(beziers, b)
+	}
+	return beziers
+}
+
+func (s BSpline3[T]) At(t T) Vec3[T] {
+	return splineAt(bSplineBeziers(s), t, 0)
+}
+
+/* First derivative with respect to t */
+func (s BSpline3[T]) Derivative(t T) Vec3[T] {
+	return splineAt(bSplineBeziers(s), t, 1)
+}
+
+/* Second derivative with respect to t */
+func (s BSpline3[T]) SecondDerivative(t T) Vec3[T] {
+	return splineAt(bSplineBeziers(s), t, 2)
+}
+
+/* Bézier segments covering 0 to t and t to 1, the segment containing t is split */
+func (s BSpline3[T]) Split(t T) ([]Bezier3[T], []Bezier3[T]) {
+	left, right := splineSplit(bSplineBeziers(s), t)
+	return bezier3s(left), bezier3s(right)
+}
+
+func (s BSpline3[T]) Bounds() Cuboid[T] {
+	return bezier3Bounds(s.Beziers())
+}
+
+/* Polyline within tolerance of the curve */
+func (s BSpline3[T]) Flatten(tolerance T) []Vec3[T] {
+	points, _ := flattenBeziers(bSplineBeziers(s), tolerance, false)
+	return points
+}
+
+/* Arc length table accurate to the flattening tolerance */
+func (s BSpline3[T]) ArcLength(tolerance T) ArcLength[T] {
+	return newArcLength(flattenBeziers(bSplineBeziers(s), tolerance, true))
+}
+
+func (a ArcLength[T]) Length() T {
+	return a.lengths[len(a.lengths)-1]
+}
+
+/* Curve parameter at distance s along the curve, s is clamped to the length */
+func (a ArcLength[T]) Param(s T) T {
+	i := sort.Search(len(a.lengths), func(i int) bool { return a.lengths[i] >= s })
+	switch {
+	case i == 0:
+		return a.params[0]
+	case i == len(a.lengths):
+		return a.params[len(a.params)-1]
+	}
+
+	span := a.lengths[i] - a.lengths[i-1]
+	if span == 0 {
+		return a.params[i]
+	}
+	u := (s - a.lengths[i-1]) / span
+	return a.params[i-1] + (a.params[i]-a.params[i-1])*u
+}
+
+func newArcLength[T Num, V curveVec[T, V]](points []V, params []T) ArcLength[T] {
+	lengths := make([]T, len(points))
+	for i := 1; i < len(points); i++ {
+		d := points[i].Minus(points[i-1])
+		lengths[i] = lengths[i-1] + T(math.Sqrt(float64(d.Dot(d))))
+	}
+	return ArcLength[T]{params, lengths}
+}
+
+/* vector operations shared by Vec2 and Vec3 */
+type curveVec[T Num, V any] interface {
+	Plus(V) V
+	Minus(V) V
+	ScaledBy(T) V
+	Dot(V) T
+}
+
+/* de Casteljau's algorithm */
+func bezierAt[T Num, V curveVec[T, V]](points []V, t T) V {
+	if len(points) == 0 {
+		panic("must have at least one point")
+	}
+
+	work := append([]V{}, points...)
+	for n := len(work) - 1; n > 0; n-- {
+		for i := 0; i < n; i++ {
+			work[i] = work[i].Plus(work[i+1].Minus(work[i]).ScaledBy(t))
+		}
+	}
+	return work[0]
+}
+
+func bezierSplit[T Num, V curveVec[T, V]](points []V, t T) ([]V, []V) {
+	if len(points) == 0 {
+		panic("must have at least one point")
+	}
+
+	n := len(points)
+	left, right := make([]V, n), make([]V, n)
+	work := append([]V{}, points...)
+	for k := 0; k < n; k++ {
+		left[k] = work[0]
+		right[n-1-k] = work[n-1-k]
+		for i := 0; i < n-1-k; i++ {
+			work[i] = work[i].Plus(work[i+1].Minus(work[i]).ScaledBy(t))
+		}
+	}
+	return left, right
+}
+
+/* Bézier curve of the derivative */
+func bezierHodograph[T Num, V curveVec[T, V]](points []V) []V {
+	if len(points) <= 1 {
+		var zero V
+		return []V{zero}
+	}
+
+	degree := T(len(points) - 1)
+	hodograph := make([]V, len(points)-1)
+	for i := range hodograph {
+		hodograph[i] = points[i+1].Minus(points[i]).ScaledBy(degree)
+	}
+	return hodograph
+}
+
+/* Parameters in (0, 1) where a quadratic or cubic 1D curve turns */
+func bezierExtrema[T Num](c []T) []T {
+	roots := []T{}
+	add := func(t float64) {
+		if t > 0 && t < 1 {
+			roots = append(roots, T(t))
+		}
+	}
+
+	switch len(c) {
+	case 3:
+		d0, d1 := float64(c[1]-c[0]), float64(c[2]-c[1])
+		if d0 != d1 {
+			add(d0 / (d0 - d1))
+		}
+
+	case 4:
+		d0, d1, d2 := float64(c[1]-c[0]), float64(c[2]-c[1]), float64(c[3]-c[2])
+		a, b, k := d0-2*d1+d2, 2*(d1-d0), d0
+		scale := math.Max(math.Abs(d0), math.Max(math.Abs(d1), math.Abs(d2)))
+		if math.Abs(a) <= 1e-12*scale {
+			if b != 0 {
+				add(-k / b)
+			}
+			break
+		}
+
+		disc := b*b - 4*a*k
+		if disc < 0 {
+			break
+		}
+		sq := math.Sqrt(disc)
+		add((-b + sq) / (2 * a))
+		add((-b - sq) / (2 * a))
+	}
+	return roots
+}
+
+/* nth derivative of a spline of equal length segments */
+func splineAt[T Num, V curveVec[T, V]](segments [][]V, t T, derivative int) V {
+	n := len(segments)
+	if n == 0 {
+		panic("not enough points")
+	}
+
+	i := int(math.Floor(float64(t * T(n))))
+	i = int(clamp(T(i), 0, T(n-1)))
+	local := t*T(n) - T(i)
+
+	points := segments[i]
+	scale := T(1)
+	for d := 0; d < derivative; d++ {
+		points = bezierHodograph(points)
+		scale *= T(n)
+	}
+	return bezierAt(points, local).ScaledBy(scale)
+}
+
+/* Segments before t ending with the first part of the segment containing t,
+ * and the rest of that segment followed by the segments after.
+ */
+func splineSplit[T Num, V curveVec[T, V]](segments [][]V, t T) ([][]V, [][]V) {
+	n := len(segments)
+	if n == 0 {
+		panic("not enough points")
+	}
+
+	i := int(math.Floor(float64(t * T(n))))
+	i = int(clamp(T(i), 0, T(n-1)))
+	first, second := bezierSplit(segments[i], t*T(n)-T(i))
+
+	left := append(append([][]V{}, segments[:i]...), first)
+	right := append([][]V{second}, segments[i+1:]...)
+	return left, right
+}
+
+/* Hermite form of the centripetal spline, with tangents from the chord lengths
+ * raised to the power of 0.5
+ */
+func catmullRomBeziers[T Num, V curveVec[T, V]](points []V) [][]V {
+	if len(points) < 2 {
+		return nil
+	}
+
+	knot := func(a, b V) T {
+		d := b.Minus(a)
+		return T(math.Pow(float64(d.Dot(d)), 0.25))
+	}
+
+	n := len(points)
+	segments := make([][]V, n-1)
+	for i := range segments {
+		p1, p2 := points[i], points[i+1]
+		p0 := p1.Plus(p1.Minus(p2))
+		if i > 0 {
+			p0 = points[i-1]
+		}
+		p3 := p2.Plus(p2.Minus(p1))
+		if i+2 < n {
+			p3 = points[i+2]
+		}
+
+		d0, d1, d2 := knot(p0, p1), knot(p1, p2), knot(p2, p3)
+		if d1 == 0 {
+			segments[i] = []V{p1, p1, p1, p1}
+			continue
+		}
+		if d0 == 0 {
+			d0 = d1
+		}
+		if d2 == 0 {
+			d2 = d1
+		}
+
+		m1 := p1.Minus(p0).ScaledBy(d1 / d0).Minus(p2.Minus(p0).ScaledBy(d1 / (d0 + d1))).Plus(p2.Minus(p1))
+		m2 := p3.Minus(p2).ScaledBy(d1 / d2).Minus(p3.Minus(p1).ScaledBy(d1 / (d1 + d2))).Plus(p2.Minus(p1))
+		segments[i] = []V{p1, p1.Plus(m1.ScaledBy(1.0 / 3)), p2.Minus(m2.ScaledBy(1.0 / 3)), p2}
+	}
+	return segments
+}
+
+func bSplineBeziers[T Num, V curveVec[T, V]](points []V) [][]V {
+	if len(points) < 4 {
+		return nil
+	}
+
+	segments := make([][]V, len(points)-3)
+	for i := range segments {
+		p0, p1, p2, p3 := points[i], points[i+1], points[i+2], points[i+3]
+		third1 := p1.Plus(p2.Minus(p1).ScaledBy(1.0 / 3))
+		third2 := p1.Plus(p2.Minus(p1).ScaledBy(2.0 / 3))
+		segments[i] = []V{
+			p0.Plus(p1.ScaledBy(4)).Plus(p2).ScaledBy(1.0 / 6),
+			third1,
+			third2,
+			p1.Plus(p2.ScaledBy(4)).Plus(p3).ScaledBy(1.0 / 6),
+		}
+	}
+	return segments
+}
+
+func bezier2s[T Num](segments [][]Vec2[T]) []Bezier2[T] {
+	beziers := make([]Bezier2[T], len(segments))
+	for i, b := range segments {
+		beziers[i] = b
+	}
+	return beziers
+}
+
+func bezier3s[T Num](segments [][]Vec3[T]) []Bezier3[T] {
+	beziers := make([]Bezier3[T], len(segments))
+	for i, b := range segments {
+		beziers[i] = b
+	}
+	return beziers
+}
+
+func bezier2Bounds[T Num](beziers []Bezier2[T]) Rect[T] {
+	if len(beziers) == 0 {
+		panic("not enough points")
+	}
+
+	r := beziers[0].Bounds()
+	for _, b := range beziers[1:] {
+		r = r.Union(b.Bounds())
+	}
+	return r
+}
+
+func bezier3Bounds[T Num](beziers []Bezier3[T]) Cuboid[T] {
+	if len(beziers) == 0 {
+		panic("not enough points")
+	}
+
+	c := beziers[0].Bounds()
+	for _, b := range beziers[1:] {
+		c = c.Union(b.Bounds())
+	}
+	return c
+}
+
+/* Points of the segments subdivided until each piece is within tolerance, with
+ * the spline parameter of each point. Uniform also subdivides until the
+ * parameter moves along each piece at a near constant speed.
+ */
+func flattenBeziers[T Num, V curveVec[T, V]](segments [][]V, tolerance T, uniform bool) ([]V, []T) {
+	if len(segments) == 0 || len(segments[0]) == 0 {
+		panic("not enough points")
+	}
+
+	points := []V{segments[0][0]}
+	params := []T{0}
+	n := T(len(segments))
+
+	var subdivide func(b []V, t0, t1 T, depth int)
+	subdivide = func(b []V, t0, t1 T, depth int) {
+		if depth >= curveFlattenDepth || (bezierFlat(b, tolerance) && (!uniform || bezierUniform(b, tolerance))) {
+			points = append(points, b[len(b)-1])
+			params = append(params, t1)
+			return
+		}
+
+		left, right := bezierSplit(b, 0.5)
+		mid := (t0 + t1) / 2
+		subdivide(left, t0, mid, depth+1)
+		subdivide(right, mid, t1, depth+1)
+	}
+
+	for i, b := range segments {
+		subdivide(b, T(i)/n, T(i+1)/n, 0)
+	}
+	return points, params
+}
+
+/* The curve lies within the hull of its points, so it is within tolerance of
+ * the chord if every point is. A single point is a degenerate curve.
+ */
+func bezierFlat[T Num, V curveVec[T, V]](points []V, tolerance T) bool {
+	if len(points) < 3 {
+		return true
+	}
+
+	a, b := points[0], points[len(points)-1]
+	chord := b.Minus(a)
+	l2 := chord.Dot(chord)
+
+	for _, p := range points[1 : len(points)-1] {
+		closest := a
+		if l2 > 0 {
+			closest = a.Plus(chord.ScaledBy(clamp(p.Minus(a).Dot(chord)/l2, 0, 1)))
+		}
+		if d := p.Minus(closest); d.Dot(d) > tolerance*tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+/* The middle of the curve is within tolerance of the middle of the chord */
+func bezierUniform[T Num, V curveVec[T, V]](points []V, tolerance T) bool {
+	a, b := points[0], points[len(points)-1]
+	d := bezierAt(points, 0.5).Minus(a.Plus(b.Minus(a).ScaledBy(0.5)))
+	return d.Dot(d) <= tolerance*tolerance
+}
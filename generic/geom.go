@@ -4,6 +4,11 @@ type Num interface {
 	~float32 | ~float64
 }
 
+/* True when T has float32 precision, including types defined on float32 */
+func isFloat32[T Num]() bool {
+	return T(1)+T(1e-9) == T(1)
+}
+
 /*
 Conventions:
 Right-Handed coordinate system
@@ -27,3 +32,21 @@ Column-Major
 
     a.Product(b) -> a is performed first
 */
+
+func min[T Num](a, b T) T {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max[T Num](a, b T) T {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func clamp[T Num](v, lo, hi T) T {
+	return max(lo, min(hi, v))
+}
@@ -0,0 +1,291 @@
+package geom
+
+import (
+	"math"
+	"sort"
+)
+
+/* Andrew's monotone chain. Returns the hull clockwise (positive Area) without
+ * collinear or duplicate verts. Fewer than three distinct points, or points
+ * which are all collinear, produce a hull of one or two verts.
+ */
+func PolyConvexHull[T Num](points []Vec2[T]) Poly[T] {
+	sorted := append([]Vec2[T]{}, points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+
+	unique := sorted[:0]
+	for i := range sorted {
+		if i == 0 || sorted[i] != sorted[i-1] {
+			unique = append(unique, sorted[i])
+		}
+	}
+	if len(unique) < 3 {
+		return Poly[T](unique)
+	}
+
+	hull := Poly[T]{}
+	for _, p := range unique { // lower chain
+		for len(hull) >= 2 && hull[len(hull)-1].Minus(hull[len(hull)-2]).Cross(p.Minus(hull[len(hull)-1])) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	lower := len(hull) + 1
+	for i := len(unique) - 2; i >= 0; i-- { // upper chain
+		p := unique[i]
+		for len(hull) >= lower && hull[len(hull)-1].Minus(hull[len(hull)-2]).Cross(p.Minus(hull[len(hull)-1])) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+
+	return hull[:len(hull)-1]
+}
+
+/* Quickhull in 3D. Returns triangle faces indexing into points, each wound
+ * anti-clockwise when viewed from outside so (b-a).Cross(c-a) points outwards.
+ * Coplanar points on the hull are not used as verts. Returns no faces if all
+ * points are coplanar.
+ */
+func Vec3ConvexHull[T Num](points []Vec3[T]) [][3]int {
+	h := hull3{
+		points: make([]Vec3[float64], len(points)),
+		edges:  map[[2]int]int{},
+	}
+
+	scale := 0.0
+	for i, p := range points {
+		h.points[i] = Vec3[float64]{float64(p.X), float64(p.Y), float64(p.Z)}
+		scale = math.Max(scale, math.Abs(h.points[i].X)+math.Abs(h.points[i].Y)+math.Abs(h.points[i].Z))
+	}
+	h.eps = 1e-12 * scale
+	if isFloat32[T]() {
+		h.eps = 1e-6 * scale
+	}
+
+	if !h.initialSimplex() {
+		return [][3]int{}
+	}
+
+	for {
+		face := -1
+		for i := range h.faces {
+			if h.faces[i].alive && len(h.faces[i].outside) > 0 {
+				face = i
+				break
+			}
+		}
+		if face < 0 {
+			break
+		}
+		h.addPoint(face)
+	}
+
+	faces := [][3]int{}
+	for _, f := range h.faces {
+		if f.alive {
+			faces = append(faces, f.verts)
+		}
+	}
+	return faces
+}
+
+type hull3Face struct {
+	verts   [3]int
+	normal  Vec3[float64]
+	offset  float64
+	outside []int
+	alive   bool
+}
+
+type hull3 struct {
+	points []Vec3[float64]
+	faces  []hull3Face
+	edges  map[[2]int]int // directed edge -> face
+	eps    float64
+}
+
+func (h *hull3) distance(face int, p int) float64 {
+	return h.faces[face].normal.Dot(h.points[p]) - h.faces[face].offset
+}
+
+func (h *hull3) addFace(a, b, c int) int {
+	pa, pb, pc := h.points[a], h.points[b], h.points[c]
+	n := pb.Minus(pa).Cross(pc.Minus(pa)).Normal()
+
+	h.faces = append(h.faces, hull3Face{
+		verts:  [3]int{a, b, c},
+		normal: n,
+		offset: n.Dot(pa),
+		alive:  true,
+	})
+
+	i := len(h.faces) - 1
+	h.edges[[2]int{a, b}] = i
+	h.edges[[2]int{b, c}] = i
+	h.edges[[2]int{c, a}] = i
+	return i
+}
+
+func (h *hull3) removeFace(face int) {
+	f := &h.faces[face]
+	f.alive = false
+	for j := 0; j < 3; j++ {
+		e := [2]int{f.verts[j], f.verts[(j+1)%3]}
+		if h.edges[e] == face {
+			delete(h.edges, e)
+		}
+	}
+}
+
+func (h *hull3) initialSimplex() bool {
+	if len(h.points) < 4 {
+		return false
+	}
+
+	// two most distant of the axis extremes
+	extremes := []int{}
+	for axis := 0; axis < 3; axis++ {
+		lo, hi := 0, 0
+		for i, p := range h.points {
+			if vec3Axis(p, axis) < vec3Axis(h.points[lo], axis) {
+				lo = i
+			}
+			if vec3Axis(p, axis) > vec3Axis(h.points[hi], axis) {
+				hi = i
+			}
+		}
+		extremes = append(extremes, lo, hi)
+	}
+
+	a, b, best := 0, 0, 0.0
+	for _, i := range extremes {
+		for _, j := range extremes {
+			if d := h.points[i].Minus(h.points[j]).Len2(); d > best {
+				a, b, best = i, j, d
+			}
+		}
+	}
+	if math.Sqrt(best) <= h.eps {
+		return false
+	}
+
+	// furthest from the line ab
+	ab := h.points[b].Minus(h.points[a])
+	c, best := -1, 0.0
+	for i, p := range h.points {
+		if d := ab.Cross(p.Minus(h.points[a])).Len() / ab.Len(); d > best {
+			c, best = i, d
+		}
+	}
+	if c < 0 || best <= h.eps {
+		return false
+	}
+
+	// furthest from the plane abc
+	n := ab.Cross(h.points[c].Minus(h.points[a])).Normal()
+	d, best := -1, 0.0
+	for i, p := range h.points {
+		if dist := math.Abs(n.Dot(p.Minus(h.points[a]))); dist > best {
+			d, best = i, dist
+		}
+	}
+	if d < 0 || best <= h.eps {
+		return false
+	}
+
+	if n.Dot(h.points[d].Minus(h.points[a])) > 0 {
+		b, c = c, b
+	}
+	h.addFace(a, b, c)
+	h.addFace(a, d, b)
+	h.addFace(b, d, c)
+	h.addFace(c, d, a)
+
+	for i := range h.points {
+		if i != a && i != b && i != c && i != d {
+			h.assign(i, []int{0, 1, 2, 3})
+		}
+	}
+	return true
+}
+
+/* puts p in the outside set of the first face it is above */
+func (h *hull3) assign(p int, faces []int) {
+	for _, f := range faces {
+		if h.distance(f, p) > h.eps {
+			h.faces[f].outside = append(h.faces[f].outside, p)
+			return
+		}
+	}
+}
+
+func (h *hull3) addPoint(face int) {
+	eye, best := -1, 0.0
+	for _, p := range h.faces[face].outside {
+		if d := h.distance(face, p); eye < 0 || d > best {
+			eye, best = p, d
+		}
+	}
+
+	// flood fill the faces visible from eye, collecting the horizon
+	visible := []int{face}
+	seen := map[int]bool{face: true}
+	horizon := [][2]int{}
+
+	var visit func(f int)
+	visit = func(f int) {
+		for j := 0; j < 3; j++ {
+			a, b := h.faces[f].verts[j], h.faces[f].verts[(j+1)%3]
+			n, ok := h.edges[[2]int{b, a}]
+			if !ok {
+				continue
+			}
+			if seen[n] {
+				if !h.isVisible(n, eye) {
+					horizon = append(horizon, [2]int{a, b})
+				}
+				continue
+			}
+			if h.isVisible(n, eye) {
+				seen[n] = true
+				visible = append(visible, n)
+				visit(n)
+			} else {
+				seen[n] = true
+				horizon = append(horizon, [2]int{a, b})
+			}
+		}
+	}
+	visit(face)
+
+	orphans := []int{}
+	for _, f := range visible {
+		for _, p := range h.faces[f].outside {
+			if p != eye {
+				orphans = append(orphans, p)
+			}
+		}
+		h.faces[f].outside = nil
+		h.removeFace(f)
+	}
+
+	created := []int{}
+	for _, e := range horizon {
+		created = append(created, h.addFace(e[0], e[1], eye))
+	}
+
+	for _, p := range orphans {
+		h.assign(p, created)
+	}
+}
+
+func (h *hull3) isVisible(face int, p int) bool {
+	return h.faces[face].alive && h.distance(face, p) > h.eps
+}
@@ -0,0 +1,243 @@
+package geom
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+/* Static KD-tree over 2D points. Query results are indices into the slice
+ * given to NewKDTree2.
+ */
+type KDTree2[T Num] struct {
+	tree kdTree[T]
+}
+
+/* Static KD-tree over 3D points. Query results are indices into the slice
+ * given to NewKDTree3.
+ */
+type KDTree3[T Num] struct {
+	tree kdTree[T]
+}
+
+func NewKDTree2[T Num](points []Vec2[T]) *KDTree2[T] {
+	coords := make([]T, 0, 2*len(points))
+	for _, p := range points {
+		coords = append(coords, p.X, p.Y)
+	}
+	return &KDTree2[T]{newKDTree(coords, 2)}
+}
+
+func NewKDTree3[T Num](points []Vec3[T]) *KDTree3[T] {
+	coords := make([]T, 0, 3*len(points))
+	for _, p := range points {
+		coords = append(coords, p.X, p.Y, p.Z)
+	}
+	return &KDTree3[T]{newKDTree(coords, 3)}
+}
+
+func (k *KDTree2[T]) Len() int {
+	return len(k.tree.order)
+}
+
+/* Index of the closest point to v, false if the tree is empty */
+func (k *KDTree2[T]) Nearest(v Vec2[T]) (int, bool) {
+	return k.tree.nearest([]T{v.X, v.Y})
+}
+
+/* Indices of the n closest points to v, nearest first */
+func (k *KDTree2[T]) KNearest(v Vec2[T], n int) []int {
+	return k.tree.kNearest([]T{v.X, v.Y}, n)
+}
+
+/* Indices of the points no further than radius from v, in no particular order */
+func (k *KDTree2[T]) WithinRadius(v Vec2[T], radius T) []int {
+	return k.tree.withinRadius([]T{v.X, v.Y}, radius)
+}
+
+func (k *KDTree3[T]) Len() int {
+	return len(k.tree.order)
+}
+
+/* Index of the closest point to v, false if the tree is empty */
+func (k *KDTree3[T]) Nearest(v Vec3[T]) (int, bool) {
+	return k.tree.nearest([]T{v.X, v.Y, v.Z})
+}
+
+/* Indices of the n closest points to v, nearest first */
+func (k *KDTree3[T]) KNearest(v Vec3[T], n int) []int {
+	return k.tree.kNearest([]T{v.X, v.Y, v.Z}, n)
+}
+
+/* Indices of the points no further than radius from v, in no particular order */
+func (k *KDTree3[T]) WithinRadius(v Vec3[T], radius T) []int {
+	return k.tree.withinRadius([]T{v.X, v.Y, v.Z}, radius)
+}
+
+/* Implicit balanced tree of any dimension. order[lo:hi] is a subtree whose
+ * root is the median at mid, splitting on axes[mid], with the left subtree in
+ * order[lo:mid] and the right in order[mid+1:hi].
+ */
+type kdTree[T Num] struct {
+	dims   int
+	coords []T   // point i is coords[i*dims : (i+1)*dims]
+	order  []int // point indices
+	axes   []int
+}
+
+func newKDTree[T Num](coords []T, dims int) kdTree[T] {
+	n := len(coords) / dims
+	k := kdTree[T]{
+		dims:   dims,
+		coords: coords,
+		order:  make([]int, n),
+		axes:   make([]int, n),
+	}
+	for i := range k.order {
+		k.order[i] = i
+	}
+	k.build(0, n)
+	return k
+}
+
+func (k *kdTree[T]) coord(point, axis int) T {
+	return k.coords[point*k.dims+axis]
+}
+
+func (k *kdTree[T]) dist2(point int, v []T) T {
+	var d2 T
+	for axis, x := range v {
+		d := k.coord(point, axis) - x
+		d2 += d * d
+	}
+	return d2
+}
+
+func (k *kdTree[T]) build(lo, hi int) {
+	if hi-lo <= 0 {
+		return
+	}
+
+	// split on the axis with the greatest spread
+	axis, spread := 0, T(-1)
+	for a := 0; a < k.dims; a++ {
+		first := k.coord(k.order[lo], a)
+		low, high := first, first
+		for _, i := range k.order[lo:hi] {
+			low = min(low, k.coord(i, a))
+			high = max(high, k.coord(i, a))
+		}
+		if high-low > spread {
+			axis, spread = a, high-low
+		}
+	}
+
+	points := k.order[lo:hi]
+	sort.Slice(points, func(i, j int) bool {
+		return k.coord(points[i], axis) < k.coord(points[j], axis)
+	})
+
+	mid := (lo + hi) / 2
+	k.axes[mid] = axis
+	k.build(lo, mid)
+	k.build(mid+1, hi)
+}
+
+/* Visits the side of each split containing v first, calling fn for each
+ * point. bound returns the current squared search radius, subtrees further
+ * than it are skipped.
+ */
+func (k *kdTree[T]) search(lo, hi int, v []T, bound func() T, fn func(point int, d2 T)) {
+	if hi-lo <= 0 {
+		return
+	}
+
+	mid := (lo + hi) / 2
+	point := k.order[mid]
+	fn(point, k.dist2(point, v))
+
+	d := v[k.axes[mid]] - k.coord(point, k.axes[mid])
+	if d < 0 {
+		k.search(lo, mid, v, bound, fn)
+		if d*d <= bound() {
+			k.search(mid+1, hi, v, bound, fn)
+		}
+	} else {
+		k.search(mid+1, hi, v, bound, fn)
+		if d*d <= bound() {
+			k.search(lo, mid, v, bound, fn)
+		}
+	}
+}
+
+func (k *kdTree[T]) nearest(v []T) (int, bool) {
+	best, bestD2 := -1, T(math.Inf(1))
+	k.search(0, len(k.order), v, func() T {
+		return bestD2
+	}, func(point int, d2 T) {
+		if best < 0 || d2 < bestD2 {
+			best, bestD2 = point, d2
+		}
+	})
+	return best, best >= 0
+}
+
+func (k *kdTree[T]) kNearest(v []T, n int) []int {
+	if n <= 0 {
+		return []int{}
+	}
+
+	found := &kdHeap[T]{}
+	k.search(0, len(k.order), v, func() T {
+		if found.Len() < n {
+			return T(math.Inf(1))
+		}
+		return (*found)[0].d2
+	}, func(point int, d2 T) {
+		switch {
+		case found.Len() < n:
+			heap.Push(found, kdHeapItem[T]{point, d2})
+		case d2 < (*found)[0].d2:
+			(*found)[0] = kdHeapItem[T]{point, d2}
+			heap.Fix(found, 0)
+		}
+	})
+
+	indices := make([]int, found.Len())
+	for i := len(indices) - 1; i >= 0; i-- {
+		indices[i] = heap.Pop(found).(kdHeapItem[T]).point
+	}
+	return indices
+}
+
+func (k *kdTree[T]) withinRadius(v []T, radius T) []int {
+	indices := []int{}
+	r2 := radius * radius
+	k.search(0, len(k.order), v, func() T {
+		return r2
+	}, func(point int, d2 T) {
+		if d2 <= r2 {
+			indices = append(indices, point)
+		}
+	})
+	return indices
+}
+
+type kdHeapItem[T Num] struct {
+	point int
+	d2    T
+}
+
+/* max-heap on d2 */
+type kdHeap[T Num] []kdHeapItem[T]
+
+func (h kdHeap[T]) Len() int           { return len(h) }
+func (h kdHeap[T]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h kdHeap[T]) Less(i, j int) bool { return h[i].d2 > h[j].d2 }
+func (h *kdHeap[T]) Push(x any)        { *h = append(*h, x.(kdHeapItem[T])) }
+func (h *kdHeap[T]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
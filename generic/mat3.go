@@ -76,3 +76,90 @@ func Mat3Camera2D[T Num](camera, display Rect[T]) Mat3[T] {
 		0, 0, 1,
 	}
 }
+
+func (m Mat3[T]) Transpose() Mat3[T] {
+	return Mat3[T]{
+		m[0], m[3], m[6],
+		m[1], m[4], m[7],
+		m[2], m[5], m[8],
+	}
+}
+
+func (m Mat3[T]) Determinant() T {
+	return m[0]*(m[4]*m[8]-m[5]*m[7]) -
+		m[1]*(m[3]*m[8]-m[5]*m[6]) +
+		m[2]*(m[3]*m[7]-m[4]*m[6])
+}
+
+/* General inverse using the cofactor expansion.
+ * Returns false if the matrix is singular.
+ */
+func (m Mat3[T]) Inverse() (Mat3[T], bool) {
+	c0 := m[4]*m[8] - m[5]*m[7]
+	c1 := m[5]*m[6] - m[3]*m[8]
+	c2 := m[3]*m[7] - m[4]*m[6]
+
+	det := m[0]*c0 + m[1]*c1 + m[2]*c2
+	if det == 0 || math.IsNaN(float64(det)) || math.IsInf(float64(det), 0) {
+		return Mat3[T]{}, false
+	}
+
+	inv := 1 / det
+	return Mat3[T]{
+		c0 * inv,
+		(m[2]*m[7] - m[1]*m[8]) * inv,
+		(m[1]*m[5] - m[2]*m[4]) * inv,
+
+		c1 * inv,
+		(m[0]*m[8] - m[2]*m[6]) * inv,
+		(m[2]*m[3] - m[0]*m[5]) * inv,
+
+		c2 * inv,
+		(m[1]*m[6] - m[0]*m[7]) * inv,
+		(m[0]*m[4] - m[1]*m[3]) * inv,
+	}, true
+}
+
+/* Translation times rotation times scale, so the scale is applied first.
+ * Rebuilds a matrix from Decompose.
+ */
+func Mat3Compose[T Num](translation Vec2[T], theta T, scale Vec2[T]) Mat3[T] {
+	c := T(math.Cos(float64(theta)))
+	s := T(math.Sin(float64(theta)))
+	return Mat3[T]{
+		c * scale.X, -s * scale.Y, translation.X,
+		s * scale.X, c * scale.Y, translation.Y,
+		0, 0, 1,
+	}
+}
+
+/* Splits an affine matrix into the arguments of Mat3Compose. A reflection is
+ * returned as a negative scale.Y. Returns false if the matrix is not affine, is
+ * singular, or has shear which cannot be represented.
+ */
+func (m Mat3[T]) Decompose() (translation Vec2[T], theta T, scale Vec2[T], ok bool) {
+	if m[6] != 0 || m[7] != 0 || m[8] != 1 {
+		return
+	}
+
+	x := Vec2[T]{m[0], m[3]}
+	y := Vec2[T]{m[1], m[4]}
+	sx, sy := x.Len(), y.Len()
+	if sx == 0 || sy == 0 {
+		return
+	}
+
+	eps := T(1e-9)
+	if isFloat32[T]() {
+		eps = 1e-5
+	}
+	if d := x.Dot(y) / (sx * sy); d > eps || d < -eps { // columns not orthogonal
+		return
+	}
+
+	if x.Cross(y) < 0 {
+		sy = -sy
+	}
+
+	return Vec2[T]{m[2], m[5]}, x.Theta(), Vec2[T]{sx, sy}, true
+}
@@ -37,6 +37,80 @@ func Mat4Perspective[T Num](r, l, t, b, n, f T) Mat4[T] {
 	}
 }
 
+/* Symmetric frustum of vertical field of view fovY, aspect is width / height.
+ * Same conventions as Mat4Perspective.
+ */
+func Mat4PerspectiveFov[T Num](fovY, aspect, n, f T) Mat4[T] {
+	t := n * T(math.Tan(float64(fovY)/2))
+	r := t * aspect
+	return Mat4Perspective(r, -r, t, -t, n, f)
+}
+
+/* Mat4PerspectiveFov with the far plane at infinity */
+func Mat4PerspectiveFovInfinite[T Num](fovY, aspect, n T) Mat4[T] {
+	cot := T(1 / math.Tan(float64(fovY)/2))
+	return Mat4[T]{
+		cot / aspect, 0, 0, 0,
+		0, cot, 0, 0,
+		0, 0, -1, -2 * n,
+		0, 0, -1, 0,
+	}
+}
+
+/* Reverse-Z projection, maps the near plane to depth 1 and the far plane to 0.
+ * Intended for a [0, 1] depth range with a floating point depth buffer.
+ */
+func Mat4PerspectiveFovReverseZ[T Num](fovY, aspect, n, f T) Mat4[T] {
+	cot := T(1 / math.Tan(float64(fovY)/2))
+	return Mat4[T]{
+		cot / aspect, 0, 0, 0,
+		0, cot, 0, 0,
+		0, 0, n / (f - n), f * n / (f - n),
+		0, 0, -1, 0,
+	}
+}
+
+/* Mat4PerspectiveFovReverseZ with the far plane at infinity */
+func Mat4PerspectiveFovReverseZInfinite[T Num](fovY, aspect, n T) Mat4[T] {
+	cot := T(1 / math.Tan(float64(fovY)/2))
+	return Mat4[T]{
+		cot / aspect, 0, 0, 0,
+		0, cot, 0, 0,
+		0, 0, 0, n,
+		0, 0, -1, 0,
+	}
+}
+
+/* Maps the box between the near and far planes onto [-1, 1] in each axis.
+ * Looks down -Z like Mat4Perspective, n and f are distances.
+ */
+func Mat4Orthographic[T Num](r, l, t, b, n, f T) Mat4[T] {
+	return Mat4[T]{
+		2 / (r - l), 0, 0, -(r + l) / (r - l),
+		0, 2 / (t - b), 0, -(t + b) / (t - b),
+		0, 0, -2 / (f - n), -(f + n) / (f - n),
+		0, 0, 0, 1,
+	}
+}
+
+/* View matrix for a camera at eye facing target, for use with Mat4Perspective.
+ * The camera looks down -Z with up along +Y, the result is a rigid transform so
+ * handedness is preserved. With the Y-down convention up is usually {0, -1, 0}.
+ * up must not be parallel to target - eye.
+ */
+func Mat4LookAt[T Num](eye, target, up Vec3[T]) Mat4[T] {
+	f := target.Minus(eye).Normal()
+	s := f.Cross(up).Normal()
+	u := s.Cross(f)
+
+	return Mat4[T]{
+		s.X, s.Y, s.Z, -s.Dot(eye),
+		u.X, u.Y, u.Z, -u.Dot(eye),
+		-f.X, -f.Y, -f.Z, f.Dot(eye),
+		0, 0, 0, 1,
+	}
+}
+
 func Mat4Translation[T Num](v Vec3[T]) Mat4[T] {
 	return Mat4[T]{
 		1, 0, 0, v.X,
@@ -108,3 +182,110 @@ func (a Mat4[T]) Product(b Mat4[T]) (m Mat4[T]) {
 
 	return
 }
+
+func (m Mat4[T]) Transpose() Mat4[T] {
+	return Mat4[T]{
+		m[0], m[4], m[8], m[12],
+		m[1], m[5], m[9], m[13],
+		m[2], m[6], m[10], m[14],
+		m[3], m[7], m[11], m[15],
+	}
+}
+
+func (m Mat4[T]) Determinant() T {
+	s0 := m[0]*m[5] - m[1]*m[4]
+	s1 := m[0]*m[6] - m[2]*m[4]
+	s2 := m[0]*m[7] - m[3]*m[4]
+	s3 := m[1]*m[6] - m[2]*m[5]
+	s4 := m[1]*m[7] - m[3]*m[5]
+	s5 := m[2]*m[7] - m[3]*m[6]
+
+	c5 := m[10]*m[15] - m[11]*m[14]
+	c4 := m[9]*m[15] - m[11]*m[13]
+	c3 := m[9]*m[14] - m[10]*m[13]
+	c2 := m[8]*m[15] - m[11]*m[12]
+	c1 := m[8]*m[14] - m[10]*m[12]
+	c0 := m[8]*m[13] - m[9]*m[12]
+
+	return s0*c5 - s1*c4 + s2*c3 + s3*c2 - s4*c1 + s5*c0
+}
+
+/* General inverse using the cofactor expansion.
+ * Returns false if the matrix is singular.
+ */
+func (m Mat4[T]) Inverse() (Mat4[T], bool) {
+	s0 := m[0]*m[5] - m[1]*m[4]
+	s1 := m[0]*m[6] - m[2]*m[4]
+	s2 := m[0]*m[7] - m[3]*m[4]
+	s3 := m[1]*m[6] - m[2]*m[5]
+	s4 := m[1]*m[7] - m[3]*m[5]
+	s5 := m[2]*m[7] - m[3]*m[6]
+
+	c5 := m[10]*m[15] - m[11]*m[14]
+	c4 := m[9]*m[15] - m[11]*m[13]
+	c3 := m[9]*m[14] - m[10]*m[13]
+	c2 := m[8]*m[15] - m[11]*m[12]
+	c1 := m[8]*m[14] - m[10]*m[12]
+	c0 := m[8]*m[13] - m[9]*m[12]
+
+	det := s0*c5 - s1*c4 + s2*c3 + s3*c2 - s4*c1 + s5*c0
+	if det == 0 || math.IsNaN(float64(det)) || math.IsInf(float64(det), 0) {
+		return Mat4[T]{}, false
+	}
+
+	inv := 1 / det
+	return Mat4[T]{
+		(m[5]*c5 - m[6]*c4 + m[7]*c3) * inv,
+		(-m[1]*c5 + m[2]*c4 - m[3]*c3) * inv,
+		(m[13]*s5 - m[14]*s4 + m[15]*s3) * inv,
+		(-m[9]*s5 + m[10]*s4 - m[11]*s3) * inv,
+
+		(-m[4]*c5 + m[6]*c2 - m[7]*c1) * inv,
+		(m[0]*c5 - m[2]*c2 + m[3]*c1) * inv,
+		(-m[12]*s5 + m[14]*s2 - m[15]*s1) * inv,
+		(m[8]*s5 - m[10]*s2 + m[11]*s1) * inv,
+
+		(m[4]*c4 - m[5]*c2 + m[7]*c0) * inv,
+		(-m[0]*c4 + m[1]*c2 - m[3]*c0) * inv,
+		(m[12]*s4 - m[13]*s2 + m[15]*s0) * inv,
+		(-m[8]*s4 + m[9]*s2 - m[11]*s0) * inv,
+
+		(-m[4]*c3 + m[5]*c1 - m[6]*c0) * inv,
+		(m[0]*c3 - m[1]*c1 + m[2]*c0) * inv,
+		(-m[12]*s3 + m[13]*s1 - m[14]*s0) * inv,
+		(m[8]*s3 - m[9]*s1 + m[10]*s0) * inv,
+	}, true
+}
+
+/* Inverse of an affine matrix whose bottom row is | 0 0 0 1 |, such as those
+ * built from Mat4Translation, Mat4RotationX/Y/Z and Mat4Scalar.
+ * Only the upper 3x3 is inverted. Returns false if it is singular.
+ */
+func (m Mat4[T]) InverseAffine() (Mat4[T], bool) {
+	c0 := m[5]*m[10] - m[6]*m[9]
+	c1 := m[6]*m[8] - m[4]*m[10]
+	c2 := m[4]*m[9] - m[5]*m[8]
+
+	det := m[0]*c0 + m[1]*c1 + m[2]*c2
+	if det == 0 || math.IsNaN(float64(det)) || math.IsInf(float64(det), 0) {
+		return Mat4[T]{}, false
+	}
+
+	inv := 1 / det
+	r0 := c0 * inv
+	r1 := (m[2]*m[9] - m[1]*m[10]) * inv
+	r2 := (m[1]*m[6] - m[2]*m[5]) * inv
+	r4 := c1 * inv
+	r5 := (m[0]*m[10] - m[2]*m[8]) * inv
+	r6 := (m[2]*m[4] - m[0]*m[6]) * inv
+	r8 := c2 * inv
+	r9 := (m[1]*m[8] - m[0]*m[9]) * inv
+	r10 := (m[0]*m[5] - m[1]*m[4]) * inv
+
+	return Mat4[T]{
+		r0, r1, r2, -(r0*m[3] + r1*m[7] + r2*m[11]),
+		r4, r5, r6, -(r4*m[3] + r5*m[7] + r6*m[11]),
+		r8, r9, r10, -(r8*m[3] + r9*m[7] + r10*m[11]),
+		0, 0, 0, 1,
+	}, true
+}
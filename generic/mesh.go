@@ -0,0 +1,257 @@
+package geom
+
+import "math"
+
+/* Indexed triangle mesh. Normals and UVs are either empty or hold one entry
+ * per position. Triangles face the direction of (b-a)×(c-a).
+ */
+type Mesh3[T Num] struct {
+	Positions []Vec3[T]
+	Normals   []Vec3[T]
+	UVs       []Vec2[T]
+	Indices   [][3]int
+}
+
+func Mesh3Copy[T Num](m Mesh3[T]) Mesh3[T] {
+	return Mesh3[T]{
+		Positions: append([]Vec3[T]{}, m.Positions...),
+		Normals:   append([]Vec3[T]{}, m.Normals...),
+		UVs:       append([]Vec2[T]{}, m.UVs...),
+		Indices:   append([][3]int{}, m.Indices...),
+	}
+}
+
+/* Concatenates meshes. Normals and UVs are kept if any mesh has them, with
+ * zeroes for the meshes which don't.
+ */
+func Mesh3Merge[T Num](meshes ...Mesh3[T]) Mesh3[T] {
+	hasNormals, hasUVs := false, false
+	for _, m := range meshes {
+		hasNormals = hasNormals || len(m.Normals) > 0
+		hasUVs = hasUVs || len(m.UVs) > 0
+	}
+
+	merged := Mesh3[T]{Positions: []Vec3[T]{}, Indices: [][3]int{}}
+	for _, m := range meshes {
+		offset := len(merged.Positions)
+		merged.Positions = append(merged.Positions, m.Positions...)
+
+		if hasNormals {
+			normals := m.Normals
+			if len(normals) == 0 {
+				normals = make([]Vec3[T], len(m.Positions))
+			}
+			merged.Normals = append(merged.Normals, normals...)
+		}
+		if hasUVs {
+			uvs := m.UVs
+			if len(uvs) == 0 {
+				uvs = make([]Vec2[T], len(m.Positions))
+			}
+			merged.UVs = append(merged.UVs, uvs...)
+		}
+
+		for _, tri := range m.Indices {
+			merged.Indices = append(merged.Indices, [3]int{tri[0] + offset, tri[1] + offset, tri[2] + offset})
+		}
+	}
+	return merged
+}
+
+func (m Mesh3[T]) Bounds() Cuboid[T] {
+	return CuboidBounds(m.Positions)
+}
+
+/* Unit normal of each triangle, zero for degenerate triangles */
+func (m Mesh3[T]) FaceNormals() []Vec3[T] {
+	normals := make([]Vec3[T], len(m.Indices))
+	for i, tri := range m.Indices {
+		normals[i] = m.triangleCross(tri).Normal()
+	}
+	return normals
+}
+
+/* Unit normal of each position, the average of the triangles using it weighted
+ * by their area.
+ */
+func (m Mesh3[T]) VertexNormals() []Vec3[T] {
+	normals := make([]Vec3[T], len(m.Positions))
+	for _, tri := range m.Indices {
+		cross := m.triangleCross(tri) // length is twice the area
+		for _, i := range tri {
+			normals[i].PlusEquals(cross)
+		}
+	}
+	for i := range normals {
+		normals[i] = normals[i].Normal()
+	}
+	return normals
+}
+
+/* Copy with smooth normals from VertexNormals */
+func (m Mesh3[T]) Smooth() Mesh3[T] {
+	smooth := Mesh3Copy(m)
+	smooth.Normals = m.VertexNormals()
+	return smooth
+}
+
+/* Copy where each triangle has its own positions with the face normal */
+func (m Mesh3[T]) Flat() Mesh3[T] {
+	flat := Mesh3[T]{
+		Positions: make([]Vec3[T], 0, 3*len(m.Indices)),
+		Normals:   make([]Vec3[T], 0, 3*len(m.Indices)),
+		Indices:   make([][3]int, len(m.Indices)),
+	}
+
+	faceNormals := m.FaceNormals()
+	for i, tri := range m.Indices {
+		for j, v := range tri {
+			flat.Positions = append(flat.Positions, m.Positions[v])
+			flat.Normals = append(flat.Normals, faceNormals[i])
+			if len(m.UVs) > 0 {
+				flat.UVs = append(flat.UVs, m.UVs[v])
+			}
+			flat.Indices[i][j] = 3*i + j
+		}
+	}
+	return flat
+}
+
+/* Copy with positions transformed by mat and normals by its inverse-transpose.
+ * Triangles are reversed when mat mirrors so they keep facing outwards.
+ */
+func (m Mesh3[T]) Transformed(mat Mat4[T]) Mesh3[T] {
+	transformed := Mesh3Copy(m)
+	for i, p := range m.Positions {
+		transformed.Positions[i] = mat.TransformVec3(p, 1)
+	}
+
+	// the cofactor matrix is the inverse-transpose scaled by the determinant
+	r0 := Vec3[T]{mat[0], mat[1], mat[2]}
+	r1 := Vec3[T]{mat[4], mat[5], mat[6]}
+	r2 := Vec3[T]{mat[8], mat[9], mat[10]}
+	c0, c1, c2 := r1.Cross(r2), r2.Cross(r0), r0.Cross(r1)
+	det := r0.Dot(c0)
+
+	sign := T(1)
+	if det < 0 {
+		sign = -1
+		for i, tri := range transformed.Indices {
+			transformed.Indices[i] = [3]int{tri[0], tri[2], tri[1]}
+		}
+	}
+	for i, n := range m.Normals {
+		transformed.Normals[i] = Vec3[T]{c0.Dot(n), c1.Dot(n), c2.Dot(n)}.ScaledBy(sign).Normal()
+	}
+	return transformed
+}
+
+/* Merges positions within tolerance of each other whose normals and UVs are
+ * also within tolerance. The first of each group is kept and triangles left
+ * with repeated positions are removed.
+ */
+func (m Mesh3[T]) Weld(tolerance T) Mesh3[T] {
+	tol := math.Max(float64(tolerance), 0)
+
+	// cells are large enough for keys of the furthest position to fit an int64
+	bounds := m.Bounds()
+	furthest := 0.0
+	for _, v := range []Vec3[T]{bounds.Min, bounds.Max} {
+		furthest = math.Max(furthest, math.Max(math.Abs(float64(v.X)), math.Max(math.Abs(float64(v.Y)), math.Abs(float64(v.Z)))))
+	}
+	cell := math.Max(tol, furthest/(1<<50))
+	key := func(p Vec3[T]) [3]int64 {
+		return [3]int64{
+			int64(math.Floor(float64(p.X) / cell)),
+			int64(math.Floor(float64(p.Y) / cell)),
+			int64(math.Floor(float64(p.Z) / cell)),
+		}
+	}
+
+	near := func(a, b int) bool {
+		if float64(m.Positions[a].Minus(m.Positions[b]).Len()) > tol {
+			return false
+		}
+		if len(m.Normals) > 0 && float64(m.Normals[a].Minus(m.Normals[b]).Len()) > tol {
+			return false
+		}
+		return len(m.UVs) == 0 || float64(m.UVs[a].Minus(m.UVs[b]).Len()) <= tol
+	}
+
+	welded := Mesh3[T]{Positions: []Vec3[T]{}, Indices: [][3]int{}}
+	remap := make([]int, len(m.Positions))
+	kept := []int{} // original index of each welded position
+	grid := map[[3]int64][]int{}
+
+	// a tolerance of 0 only merges equal values, found without the grid
+	type vertex struct {
+		position, normal Vec3[T]
+		uv               Vec2[T]
+	}
+	exact := map[vertex]int{}
+	vertexOf := func(i int) vertex {
+		v := vertex{position: m.Positions[i]}
+		if len(m.Normals) > 0 {
+			v.normal = m.Normals[i]
+		}
+		if len(m.UVs) > 0 {
+			v.uv = m.UVs[i]
+		}
+		return v
+	}
+
+	for i, p := range m.Positions {
+		var k [3]int64
+		remap[i] = -1
+		if tol == 0 {
+			if w, ok := exact[vertexOf(i)]; ok {
+				remap[i] = w
+			}
+		} else {
+			k = key(p)
+		search:
+			for dx := int64(-1); dx <= 1; dx++ {
+				for dy := int64(-1); dy <= 1; dy++ {
+					for dz := int64(-1); dz <= 1; dz++ {
+						for _, w := range grid[[3]int64{k[0] + dx, k[1] + dy, k[2] + dz}] {
+							if near(kept[w], i) {
+								remap[i] = w
+								break search
+							}
+						}
+					}
+				}
+			}
+		}
+
+		if remap[i] < 0 {
+			remap[i] = len(kept)
+			if tol == 0 {
+				exact[vertexOf(i)] = len(kept)
+			} else {
+				grid[k] = append(grid[k], len(kept))
+			}
+			kept = append(kept, i)
+			welded.Positions = append(welded.Positions, p)
+			if len(m.Normals) > 0 {
+				welded.Normals = append(welded.Normals, m.Normals[i])
+			}
+			if len(m.UVs) > 0 {
+				welded.UVs = append(welded.UVs, m.UVs[i])
+			}
+		}
+	}
+
+	for _, tri := range m.Indices {
+		a, b, c := remap[tri[0]], remap[tri[1]], remap[tri[2]]
+		if a != b && b != c && c != a {
+			welded.Indices = append(welded.Indices, [3]int{a, b, c})
+		}
+	}
+	return welded
+}
+
+func (m Mesh3[T]) triangleCross(tri [3]int) Vec3[T] {
+	a, b, c := m.Positions[tri[0]], m.Positions[tri[1]], m.Positions[tri[2]]
+	return b.Minus(a).Cross(c.Minus(a))
+}
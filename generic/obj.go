@@ -0,0 +1,338 @@
+package geom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+/* Part of an OBJ file started by an o or g statement. Faces are triangulated
+ * into Mesh and l statements become Lines.
+ */
+type ObjObject[T Num] struct {
+	Name  string
+	Mesh  Mesh3[T]
+	Lines [][]Vec3[T]
+}
+
+/* Reads v, vt, vn, f, l, o and g statements, other statements are ignored.
+ * Each distinct combination of position, UV and normal in the faces of an
+ * object becomes a mesh position. Objects without faces or lines are dropped.
+ * Errors are *ParseError.
+ */
+func ReadObj[T Num](r io.Reader) ([]ObjObject[T], error) {
+	p := objParser[T]{}
+	p.start("")
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+	for scanner.Scan() {
+		p.line++
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := p.statement(fields[0], fields[1:]); err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		p.line++ // the line that failed to read
+		return nil, p.errorf("%v", err)
+	}
+
+	p.finish()
+	return p.objects, nil
+}
+
+/* Writes each object with its own positions, UVs and normals. Every object
+ * starts with an o statement, a bare o when it has no name, so unnamed objects
+ * read back separately. Floats are written with the fewest digits that read
+ * back exactly.
+ */
+func WriteObj[T Num](w io.Writer, objects []ObjObject[T]) error {
+	bw := bufio.NewWriter(w)
+	num := formatNum[T]
+
+	// positions, UVs and normals are numbered separately from 1
+	position, uv, normal := 1, 1, 1
+	for _, o := range objects {
+		if o.Name != "" {
+			fmt.Fprintf(bw, "o %s\n", o.Name)
+		} else {
+			bw.WriteString("o\n")
+		}
+
+		m := o.Mesh
+		for _, v := range m.Positions {
+			fmt.Fprintf(bw, "v %s %s %s\n", num(v.X), num(v.Y), num(v.Z))
+		}
+		for _, t := range m.UVs {
+			fmt.Fprintf(bw, "vt %s %s\n", num(t.X), num(t.Y))
+		}
+		for _, n := range m.Normals {
+			fmt.Fprintf(bw, "vn %s %s %s\n", num(n.X), num(n.Y), num(n.Z))
+		}
+
+		for _, tri := range m.Indices {
+			bw.WriteString("f")
+			for _, i := range tri {
+				switch {
+				case len(m.UVs) > 0 && len(m.Normals) > 0:
+					fmt.Fprintf(bw, " %d/%d/%d", position+i, uv+i, normal+i)
+				case len(m.UVs) > 0:
+					fmt.Fprintf(bw, " %d/%d", position+i, uv+i)
+				case len(m.Normals) > 0:
+					fmt.Fprintf(bw, " %d//%d", position+i, normal+i)
+				default:
+					fmt.Fprintf(bw, " %d", position+i)
+				}
+			}
+			bw.WriteString("\n")
+		}
+		position += len(m.Positions)
+		uv += len(m.UVs)
+		normal += len(m.Normals)
+
+		for _, line := range o.Lines {
+			for _, v := range line {
+				fmt.Fprintf(bw, "v %s %s %s\n", num(v.X), num(v.Y), num(v.Z))
+			}
+			bw.WriteString("l")
+			for i := range line {
+				fmt.Fprintf(bw, " %d", position+i)
+			}
+			bw.WriteString("\n")
+			position += len(line)
+		}
+	}
+	return bw.Flush()
+}
+
+type objParser[T Num] struct {
+	line      int
+	positions []Vec3[T]
+	uvs       []Vec2[T]
+	normals   []Vec3[T]
+	objects   []ObjObject[T]
+
+	// current object
+	object  ObjObject[T]
+	corners map[[3]int]int // position, uv and normal indices to mesh position
+	corner  [][3]int       // of each mesh position
+	hasUV   bool
+	hasNorm bool
+}
+
+func (p *objParser[T]) errorf(format string, args ...any) error {
+	return &ParseError{"obj", p.line, fmt.Sprintf(format, args...)}
+}
+
+func (p *objParser[T]) start(name string) {
+	p.finish()
+	p.object = ObjObject[T]{Name: name}
+	p.corners = map[[3]int]int{}
+	p.corner = nil
+	p.hasUV, p.hasNorm = false, false
+}
+
+func (p *objParser[T]) finish() {
+	o := p.object
+	if len(o.Mesh.Indices) == 0 && len(o.Lines) == 0 {
+		return
+	}
+
+	o.Mesh.Positions = make([]Vec3[T], len(p.corner))
+	if p.hasUV {
+		o.Mesh.UVs = make([]Vec2[T], len(p.corner))
+	}
+	if p.hasNorm {
+		o.Mesh.Normals = make([]Vec3[T], len(p.corner))
+	}
+	for i, c := range p.corner {
+		o.Mesh.Positions[i] = p.positions[c[0]]
+		if c[1] >= 0 && p.hasUV {
+			o.Mesh.UVs[i] = p.uvs[c[1]]
+		}
+		if c[2] >= 0 && p.hasNorm {
+			o.Mesh.Normals[i] = p.normals[c[2]]
+		}
+	}
+
+	p.objects = append(p.objects, o)
+	p.object = ObjObject[T]{}
+}
+
+func (p *objParser[T]) statement(keyword string, args []string) error {
+	switch keyword {
+	case "v":
+		v, err := p.floats(args, 3, 4)
+		if err != nil {
+			return err
+		}
+		p.positions = append(p.positions, Vec3[T]{v[0], v[1], v[2]})
+
+	case "vt":
+		v, err := p.floats(args, 1, 3)
+		if err != nil {
+			return err
+		}
+		v = append(v, 0)
+		p.uvs = append(p.uvs, Vec2[T]{v[0], v[1]})
+
+	case "vn":
+		v, err := p.floats(args, 3, 3)
+		if err != nil {
+			return err
+		}
+		p.normals = append(p.normals, Vec3[T]{v[0], v[1], v[2]})
+
+	case "f":
+		return p.face(args)
+
+	case "l":
+		if len(args) < 2 {
+			return p.errorf("line needs at least 2 verts, got %d", len(args))
+		}
+		line := make([]Vec3[T], len(args))
+		for i, arg := range args {
+			c, err := p.reference(arg)
+			if err != nil {
+				return err
+			}
+			line[i] = p.positions[c[0]]
+		}
+		p.object.Lines = append(p.object.Lines, line)
+
+	case "o", "g":
+		p.start(strings.Join(args, " "))
+	}
+	return nil
+}
+
+func (p *objParser[T]) floats(args []string, least, most int) ([]T, error) {
+	if len(args) < least || len(args) > most {
+		if least == most {
+			return nil, p.errorf("expected %d numbers, got %d", least, len(args))
+		}
+		return nil, p.errorf("expected %d to %d numbers, got %d", least, most, len(args))
+	}
+
+	v := make([]T, len(args))
+	for i, arg := range args {
+		f, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return nil, p.errorf("invalid number %q", arg)
+		}
+		v[i] = T(f)
+	}
+	return v, nil
+}
+
+/* position, uv and normal of a face or line vert, -1 when absent */
+func (p *objParser[T]) reference(arg string) ([3]int, error) {
+	parts := strings.Split(arg, "/")
+	if len(parts) > 3 || parts[0] == "" {
+		return [3]int{}, p.errorf("invalid vert %q", arg)
+	}
+
+	c := [3]int{-1, -1, -1}
+	counts := [3]int{len(p.positions), len(p.uvs), len(p.normals)}
+	names := [3]string{"position", "uv", "normal"}
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+		index, err := strconv.Atoi(part)
+		switch {
+		case err != nil:
+			return c, p.errorf("invalid index %q", part)
+		case index < 0:
+			index += counts[i] // relative to the end
+		case index > 0:
+			index--
+		default:
+			return c, p.errorf("%s index must not be 0", names[i])
+		}
+		if index < 0 || index >= counts[i] {
+			return c, p.errorf("%s index %s out of range, there are %d", names[i], part, counts[i])
+		}
+		c[i] = index
+	}
+	return c, nil
+}
+
+func (p *objParser[T]) face(args []string) error {
+	if len(args) < 3 {
+		return p.errorf("face needs at least 3 verts, got %d", len(args))
+	}
+
+	verts := make([]int, len(args))
+	ring := make([]Vec3[T], len(args))
+	for i, arg := range args {
+		c, err := p.reference(arg)
+		if err != nil {
+			return err
+		}
+
+		v, ok := p.corners[c]
+		if !ok {
+			v = len(p.corner)
+			p.corners[c] = v
+			p.corner = append(p.corner, c)
+		}
+		p.hasUV = p.hasUV || c[1] >= 0
+		p.hasNorm = p.hasNorm || c[2] >= 0
+		verts[i] = v
+		ring[i] = p.positions[c[0]]
+	}
+
+	for _, tri := range triangulateFace(ring) {
+		p.object.Mesh.Indices = append(p.object.Mesh.Indices, [3]int{verts[tri[0]], verts[tri[1]], verts[tri[2]]})
+	}
+	return nil
+}
+
+/* Triangles of a planar polygon in 3D, facing the same way as the polygon */
+func triangulateFace[T Num](ring []Vec3[T]) [][3]int {
+	if len(ring) == 3 {
+		return [][3]int{{0, 1, 2}}
+	}
+
+	// Newell's method
+	var normal Vec3[T]
+	for i, a := range ring {
+		b := ring[(i+1)%len(ring)]
+		normal.PlusEquals(a.Cross(b))
+	}
+
+	// project away the largest axis of the normal
+	x, y := 0, 1
+	abs := Vec3[T]{max(normal.X, -normal.X), max(normal.Y, -normal.Y), max(normal.Z, -normal.Z)}
+	switch {
+	case abs.X >= abs.Y && abs.X >= abs.Z:
+		x, y = 1, 2
+	case abs.Y >= abs.Z:
+		x, y = 2, 0
+	}
+
+	poly := make(Poly[T], len(ring))
+	for i, v := range ring {
+		poly[i] = Vec2[T]{vec3Axis(v, x), vec3Axis(v, y)}
+	}
+
+	tris := poly.Triangulate()
+	for i, tri := range tris {
+		a, b, c := ring[tri[0]], ring[tri[1]], ring[tri[2]]
+		if b.Minus(a).Cross(c.Minus(a)).Dot(normal) < 0 {
+			tris[i] = [3]int{tri[0], tri[2], tri[1]}
+		}
+	}
+	return tris
+}
@@ -0,0 +1,38 @@
+package geom
+
+import "math"
+
+/* Corners are shaped by Join. MiterLimit is the furthest a miter may reach as a
+ * multiple of the offset, further corners are bevelled, zero uses the SVG
+ * default of 4. Zero Tolerance uses a fiftieth of the offset.
+ */
+type OffsetStyle[T Num] struct {
+	Join       StrokeJoin
+	MiterLimit T
+	Tolerance  T // furthest round corners may be from a true arc
+}
+
+/* Grows the region by delta, or shrinks it when delta is negative. The region is
+ * a set of rings filled with the even-odd rule as in PolyBoolean, so holes
+ * shrink as the region grows. Returns rings as PolyBoolean does, the region may
+ * split or merge into any number of rings.
+ */
+func PolyOffset[T Num](region []Poly[T], delta T, style OffsetStyle[T]) []Poly[T] {
+	s := newStroker(math.Abs(float64(delta)), style.Join, float64(style.MiterLimit), float64(style.Tolerance))
+	if s.half > 0 {
+		for _, ring := range region {
+			s.line(PolyConvert[T, float64](ring), true, StrokeCapButt)
+		}
+	}
+
+	// the boundary stroke covers everything within delta of an edge
+	border := []Poly[T]{}
+	for _, ring := range polyUnionAll(s.pieces) {
+		border = append(border, PolyConvert[float64, T](ring))
+	}
+
+	if delta < 0 {
+		return PolyDifference(region, border)
+	}
+	return PolyUnion(region, border)
+}
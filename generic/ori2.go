@@ -52,3 +52,71 @@ func (o Ori2[T]) Mat3Transform() Mat3[T] {
 		0, 0, 1,
 	}
 }
+
+/* Pose b applied in the frame of a.
+ * a.Compose(b).Mat3Transform() is a.Mat3Transform().Product(b.Mat3Transform())
+ */
+func (a Ori2[T]) Compose(b Ori2[T]) Ori2[T] {
+	p := b.Vec2().RotatedBy(a.Theta)
+	return Ori2[T]{a.X + p.X, a.Y + p.Y, a.Theta + b.Theta}
+}
+
+func (o Ori2[T]) Inverse() Ori2[T] {
+	p := o.Vec2().RotatedBy(-o.Theta)
+	return Ori2[T]{-p.X, -p.Y, -o.Theta}
+}
+
+/* Pose of b relative to a, so that a.Compose(a.Between(b)) is b */
+func (a Ori2[T]) Between(b Ori2[T]) Ori2[T] {
+	return a.Inverse().Compose(b)
+}
+
+/* Transforms v from the pose frame into the world frame */
+func (o Ori2[T]) TransformVec2(v Vec2[T]) Vec2[T] {
+	return v.RotatedBy(o.Theta).Plus(o.Vec2())
+}
+
+/* Transforms v from the world frame into the pose frame */
+func (o Ori2[T]) InverseTransformVec2(v Vec2[T]) Vec2[T] {
+	return v.Minus(o.Vec2()).RotatedBy(-o.Theta)
+}
+
+/* SE(2) exponential map. Integrates the body-frame velocity twist {vx, vy, w}
+ * over unit time, following an arc rather than a straight line.
+ */
+func Ori2Exp[T Num](twist Ori2[T]) Ori2[T] {
+	a, b := se2Coefficients(float64(twist.Theta))
+	return Ori2[T]{
+		T(a*float64(twist.X) - b*float64(twist.Y)),
+		T(b*float64(twist.X) + a*float64(twist.Y)),
+		twist.Theta,
+	}
+}
+
+/* SE(2) logarithm map, the inverse of Ori2Exp. Theta is wrapped into (-pi, pi]
+ * so the shortest arc is returned.
+ */
+func (o Ori2[T]) Log() Ori2[T] {
+	theta := math.Remainder(float64(o.Theta), 2*math.Pi)
+	if theta == -math.Pi {
+		theta = math.Pi
+	}
+
+	a, b := se2Coefficients(theta)
+	det := a*a + b*b
+	x, y := float64(o.X), float64(o.Y)
+	return Ori2[T]{
+		T((a*x + b*y) / det),
+		T((a*y - b*x) / det),
+		T(theta),
+	}
+}
+
+/* sin(t)/t and (1-cos(t))/t, using series expansions near zero */
+func se2Coefficients(theta float64) (float64, float64) {
+	if math.Abs(theta) < 1e-4 {
+		t2 := theta * theta
+		return 1 - t2/6, theta/2 - theta*t2/24
+	}
+	return math.Sin(theta) / theta, (1 - math.Cos(theta)) / theta
+}
@@ -0,0 +1,29 @@
+package geom
+
+import (
+	"fmt"
+	"strconv"
+)
+
+/* Error reading a file format. Line is from 1, or 0 for binary data. */
+type ParseError struct {
+	Format string
+	Line   int
+	Msg    string
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s: line %d: %s", e.Format, e.Line, e.Msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Format, e.Msg)
+}
+
+/* Fewest digits that read back exactly as T */
+func formatNum[T Num](f T) string {
+	bits := 64
+	if isFloat32[T]() {
+		bits = 32
+	}
+	return strconv.FormatFloat(float64(f), 'g', -1, bits)
+}
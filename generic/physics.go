@@ -0,0 +1,381 @@
+package geom
+
+import "math"
+
+const physicsBaumgarte = 0.2 // fraction of penetration corrected each step
+
+/* Rigid body with a convex shape. Pose is the position of the centre of mass
+ * and the rotation, Velocity is the linear and angular velocity.
+ */
+type Body2[T Num] struct {
+	Shape       Poly[T] // body space, centred on the centre of mass
+	Pose        Ori2[T]
+	Velocity    Ori2[T]
+	Mass        T // zero for static bodies
+	Inertia     T // about the centre of mass, zero for static bodies
+	Friction    T
+	Restitution T
+}
+
+/* Body of uniform density whose shape is placed by pose. The shape must be
+ * convex and may have either winding, it is moved so the centre of mass is at
+ * the origin of body space and pose is moved to match. Friction is 0.5 and
+ * Restitution 0.
+ */
+func NewBody2[T Num](shape Poly[T], density T, pose Ori2[T]) *Body2[T] {
+	b := NewStaticBody2(shape, pose)
+	b.Mass = density * b.Shape.Area()
+	b.Inertia = density * b.Shape.MomentOfInertia()
+	return b
+}
+
+/* Body of infinite mass which is never moved by the world */
+func NewStaticBody2[T Num](shape Poly[T], pose Ori2[T]) *Body2[T] {
+	shape = PolyCopy(shape)
+	if shape.Area() < 0 {
+		for i, j := 0, len(shape)-1; i < j; i, j = i+1, j-1 {
+			shape[i], shape[j] = shape[j], shape[i]
+		}
+	}
+
+	centroid := shape.Centroid()
+	for i := range shape {
+		shape[i] = shape[i].Minus(centroid)
+	}
+
+	offset := centroid.RotatedBy(pose.Theta)
+	return &Body2[T]{
+		Shape:    shape,
+		Pose:     Ori2[T]{pose.X + offset.X, pose.Y + offset.Y, pose.Theta},
+		Friction: 0.5,
+	}
+}
+
+func (b *Body2[T]) Static() bool {
+	return b.Mass == 0
+}
+
+/* Shape transformed by Pose */
+func (b *Body2[T]) WorldShape() Poly[T] {
+	shape := make(Poly[T], len(b.Shape))
+	for i, v := range b.Shape {
+		shape[i] = b.Pose.TransformVec2(v)
+	}
+	return shape
+}
+
+/* Velocity of the body at a world point */
+func (b *Body2[T]) VelocityAt(point Vec2[T]) Vec2[T] {
+	r := point.Minus(b.Pose.Vec2())
+	return b.Velocity.Vec2().Plus(r.Perpendicular().ScaledBy(b.Velocity.Theta))
+}
+
+/* Applies impulse at a world point, changing the linear and angular velocity.
+ * Has no effect on static bodies.
+ */
+func (b *Body2[T]) ApplyImpulse(impulse, point Vec2[T]) {
+	if b.Static() {
+		return
+	}
+	r := point.Minus(b.Pose.Vec2())
+	b.Velocity.PlusEquals(Ori2[T]{
+		impulse.X / b.Mass,
+		impulse.Y / b.Mass,
+		r.Cross(impulse) / b.Inertia,
+	})
+}
+
+func (b *Body2[T]) invMass() (T, T) {
+	if b.Static() {
+		return 0, 0
+	}
+	return 1 / b.Mass, 1 / b.Inertia
+}
+
+/* Steps bodies with a sequential impulse solver at a fixed timestep. Bodies are
+ * processed in the order they were added, so the same inputs always produce
+ * the same results. Impulses are carried between steps to keep stacks stable
+ * and penetration is corrected without adding energy to the velocities.
+ */
+type World2[T Num] struct {
+	Bodies     []*Body2[T]
+	Gravity    Vec2[T]
+	Timestep   T
+	Iterations int // solver iterations per step
+	Slop       T   // penetration allowed before position correction
+
+	accumulator T
+	cache       map[[2]*Body2[T]][]cachedPoint2[T]
+}
+
+/* World with 10 solver iterations and a Slop of 0.01 */
+func NewWorld2[T Num](gravity Vec2[T], timestep T) *World2[T] {
+	return &World2[T]{
+		Gravity:    gravity,
+		Timestep:   timestep,
+		Iterations: 10,
+		Slop:       0.01,
+		cache:      map[[2]*Body2[T]][]cachedPoint2[T]{},
+	}
+}
+
+/* Panics if the shape of b has no area */
+func (w *World2[T]) Add(b *Body2[T]) {
+	if len(b.Shape) < 3 || b.Shape.Area() == 0 {
+		panic("World2.Add: shape has no area")
+	}
+	w.Bodies = append(w.Bodies, b)
+}
+
+/* Advances by elapsed time, running as many fixed steps as fit and carrying
+ * the remainder to the next call. Returns the number of steps run. Panics if
+ * Timestep is not positive.
+ */
+func (w *World2[T]) Update(elapsed T) int {
+	if w.Timestep <= 0 {
+		panic("World2.Update: Timestep must be positive")
+	}
+	w.accumulator += elapsed
+	steps := 0
+	for w.accumulator >= w.Timestep {
+		w.Step()
+		w.accumulator -= w.Timestep
+		steps++
+	}
+	return steps
+}
+
+/* Advances by one Timestep */
+func (w *World2[T]) Step() {
+	dt := w.Timestep
+
+	for _, b := range w.Bodies {
+		if !b.Static() {
+			b.Velocity.PlusEquals(w.Gravity.ScaledBy(dt).Ori2())
+		}
+	}
+
+	// position correction velocities, discarded after the step
+	pseudo := make([]Ori2[T], len(w.Bodies))
+
+	contacts := w.contacts(pseudo)
+	for i := range contacts {
+		contacts[i].prepare(dt, w.Slop, 2*w.Gravity.Len()*dt, w.cache[contacts[i].key()])
+	}
+	for iter := 0; iter < w.Iterations; iter++ {
+		for i := range contacts {
+			contacts[i].solve()
+		}
+	}
+	for iter := 0; iter < w.Iterations; iter++ {
+		for i := range contacts {
+			contacts[i].solvePosition()
+		}
+	}
+
+	cache := make(map[[2]*Body2[T]][]cachedPoint2[T], len(contacts))
+	for i := range contacts {
+		cache[contacts[i].key()] = contacts[i].cached()
+	}
+	w.cache = cache
+
+	for i, b := range w.Bodies {
+		if !b.Static() {
+			v := b.Velocity
+			v.PlusEquals(pseudo[i])
+			b.Pose.PlusEquals(v.ScaledBy(dt))
+		}
+	}
+}
+
+type contact2[T Num] struct {
+	a, b        *Body2[T]
+	pseudoA     *Ori2[T]
+	pseudoB     *Ori2[T]
+	manifold    Manifold2[T]
+	friction    T
+	restitution T
+	points      [2]contactPoint2[T]
+}
+
+type contactPoint2[T Num] struct {
+	ra, rb      Vec2[T] // from each centre of mass
+	normalMass  T
+	tangentMass T
+	bias        T // restitution velocity
+	depthBias   T // position correction velocity
+	normal      T // accumulated impulses
+	tangent     T
+	pseudo      T
+}
+
+/* accumulated impulses of a contact point, used to warm start the next step */
+type cachedPoint2[T Num] struct {
+	local           Vec2[T] // in the body space of a
+	normal, tangent T
+}
+
+func (w *World2[T]) contacts(pseudo []Ori2[T]) []contact2[T] {
+	shapes := make([]Poly[T], len(w.Bodies))
+	bounds := make([]Rect[T], len(w.Bodies))
+	for i, b := range w.Bodies {
+		shapes[i] = b.WorldShape()
+		bounds[i] = shapes[i].Bounds()
+	}
+
+	contacts := []contact2[T]{}
+	for i, a := range w.Bodies {
+		for j := i + 1; j < len(w.Bodies); j++ {
+			b := w.Bodies[j]
+			if (a.Static() && b.Static()) || !bounds[i].Intersects(bounds[j]) {
+				continue
+			}
+
+			m, ok := CollidePolys(shapes[i], shapes[j])
+			if !ok {
+				continue
+			}
+
+			contacts = append(contacts, contact2[T]{
+				a:           a,
+				b:           b,
+				pseudoA:     &pseudo[i],
+				pseudoB:     &pseudo[j],
+				manifold:    m,
+				friction:    T(math.Sqrt(float64(a.Friction * b.Friction))),
+				restitution: max(a.Restitution, b.Restitution),
+			})
+		}
+	}
+	return contacts
+}
+
+func (c *contact2[T]) key() [2]*Body2[T] {
+	return [2]*Body2[T]{c.a, c.b}
+}
+
+func (c *contact2[T]) cached() []cachedPoint2[T] {
+	points := make([]cachedPoint2[T], c.manifold.NumPoints)
+	for i := range points {
+		points[i] = cachedPoint2[T]{
+			c.a.Pose.InverseTransformVec2(c.manifold.Points[i]),
+			c.points[i].normal,
+			c.points[i].tangent,
+		}
+	}
+	return points
+}
+
+/* Computes the effective masses and biases of each point and applies the
+ * impulses of matching points from the previous step.
+ */
+func (c *contact2[T]) prepare(dt, slop, restThreshold T, previous []cachedPoint2[T]) {
+	invMassA, invInertiaA := c.a.invMass()
+	invMassB, invInertiaB := c.b.invMass()
+	n := c.manifold.Normal
+	tangent := n.Perpendicular()
+
+	size := c.a.Shape.Bounds().Size()
+	matchDist := 0.1 * max(size.X, size.Y)
+
+	for i := 0; i < c.manifold.NumPoints; i++ {
+		p := &c.points[i]
+		point := c.manifold.Points[i]
+		p.ra = point.Minus(c.a.Pose.Vec2())
+		p.rb = point.Minus(c.b.Pose.Vec2())
+
+		mass := func(d Vec2[T]) T {
+			ca, cb := p.ra.Cross(d), p.rb.Cross(d)
+			k := invMassA + invMassB + invInertiaA*ca*ca + invInertiaB*cb*cb
+			if k == 0 {
+				return 0
+			}
+			return 1 / k
+		}
+		p.normalMass = mass(n)
+		p.tangentMass = mass(tangent)
+
+		p.depthBias = physicsBaumgarte / dt * max(c.manifold.Depth-slop, 0)
+		if vn := c.relativeVelocity(p).Dot(n); vn < -restThreshold {
+			p.bias = -c.restitution * vn
+		}
+
+		// warm start from the closest point of the previous step
+		local := c.a.Pose.InverseTransformVec2(point)
+		closest := matchDist
+		for _, prev := range previous {
+			if d := prev.local.Minus(local).Len(); d < closest {
+				closest = d
+				p.normal, p.tangent = prev.normal, prev.tangent
+			}
+		}
+		c.apply(p, n.ScaledBy(p.normal).Plus(tangent.ScaledBy(p.tangent)))
+	}
+}
+
+func (c *contact2[T]) relativeVelocity(p *contactPoint2[T]) Vec2[T] {
+	return contactVelocity(c.b.Velocity, p.rb).Minus(contactVelocity(c.a.Velocity, p.ra))
+}
+
+/* velocity of the point r from the centre of mass */
+func contactVelocity[T Num](v Ori2[T], r Vec2[T]) Vec2[T] {
+	return v.Vec2().Plus(r.Perpendicular().ScaledBy(v.Theta))
+}
+
+func (c *contact2[T]) apply(p *contactPoint2[T], impulse Vec2[T]) {
+	contactApply(c.a, &c.a.Velocity, c.b, &c.b.Velocity, p, impulse)
+}
+
+/* applies impulse to the velocities va and vb of the bodies a and b */
+func contactApply[T Num](a *Body2[T], va *Ori2[T], b *Body2[T], vb *Ori2[T], p *contactPoint2[T], impulse Vec2[T]) {
+	invMassA, invInertiaA := a.invMass()
+	invMassB, invInertiaB := b.invMass()
+
+	va.PlusEquals(Ori2[T]{
+		-impulse.X * invMassA,
+		-impulse.Y * invMassA,
+		-p.ra.Cross(impulse) * invInertiaA,
+	})
+	vb.PlusEquals(Ori2[T]{
+		impulse.X * invMassB,
+		impulse.Y * invMassB,
+		p.rb.Cross(impulse) * invInertiaB,
+	})
+}
+
+/* one iteration of normal then friction impulses, clamping the accumulated
+ * totals so contacts only push and friction stays within the cone
+ */
+func (c *contact2[T]) solve() {
+	n := c.manifold.Normal
+	tangent := n.Perpendicular()
+
+	for i := 0; i < c.manifold.NumPoints; i++ {
+		p := &c.points[i]
+
+		vn := c.relativeVelocity(p).Dot(n)
+		total := max(p.normal+p.normalMass*(p.bias-vn), 0)
+		c.apply(p, n.ScaledBy(total-p.normal))
+		p.normal = total
+
+		vt := c.relativeVelocity(p).Dot(tangent)
+		limit := c.friction * p.normal
+		total = clamp(p.tangent-p.tangentMass*vt, -limit, limit)
+		c.apply(p, tangent.ScaledBy(total-p.tangent))
+		p.tangent = total
+	}
+}
+
+/* one iteration of position correction on the pseudo velocities */
+func (c *contact2[T]) solvePosition() {
+	n := c.manifold.Normal
+
+	for i := 0; i < c.manifold.NumPoints; i++ {
+		p := &c.points[i]
+
+		vn := contactVelocity(*c.pseudoB, p.rb).Minus(contactVelocity(*c.pseudoA, p.ra)).Dot(n)
+		total := max(p.pseudo+p.normalMass*(p.depthBias-vn), 0)
+		contactApply(c.a, c.pseudoA, c.b, c.pseudoB, p, n.ScaledBy(total-p.pseudo))
+		p.pseudo = total
+	}
+}
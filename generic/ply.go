@@ -0,0 +1,522 @@
+package geom
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/* Mesh stored in a PLY file. Colours are RGBA and either empty or hold one
+ * entry per position.
+ */
+type PlyMesh[T Num] struct {
+	Mesh    Mesh3[T]
+	Colours [][4]uint8
+}
+
+type PlyFormat int
+
+const (
+	PlyAscii PlyFormat = iota
+	PlyBinaryLittleEndian
+	PlyBinaryBigEndian
+)
+
+type plyType int
+
+const (
+	plyNone plyType = iota
+	plyChar
+	plyUchar
+	plyShort
+	plyUshort
+	plyInt
+	plyUint
+	plyFloat
+	plyDouble
+)
+
+var plyTypes = map[string]plyType{
+	"char": plyChar, "uchar": plyUchar, "short": plyShort, "ushort": plyUshort,
+	"int": plyInt, "uint": plyUint, "float": plyFloat, "double": plyDouble,
+	"int8": plyChar, "uint8": plyUchar, "int16": plyShort, "uint16": plyUshort,
+	"int32": plyInt, "uint32": plyUint, "float32": plyFloat, "float64": plyDouble,
+}
+
+var (
+	plyTypeNames   = [...]string{"", "char", "uchar", "short", "ushort", "int", "uint", "float", "double"}
+	plyTypeSizes   = [...]int{0, 1, 1, 2, 2, 4, 4, 4, 8}
+	plyFormatNames = [...]string{"ascii", "binary_little_endian", "binary_big_endian"}
+)
+
+type plyProperty struct {
+	name  string
+	kind  plyType
+	count plyType // type of the list length, plyNone for single values
+}
+
+type plyElement struct {
+	name       string
+	count      int
+	properties []plyProperty
+}
+
+func (e plyElement) property(names ...string) int {
+	for i, prop := range e.properties {
+		for _, name := range names {
+			if prop.name == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+/* Reads ASCII and binary PLY files. Faces are triangulated, other elements and
+ * properties are skipped. Colours stored as floats are scaled from 0-1 and
+ * alpha defaults to 255. Errors are *ParseError.
+ */
+func ReadPly[T Num](r io.Reader) (PlyMesh[T], error) {
+	p := plyReader{br: bufio.NewReader(r)}
+	elements, err := p.header()
+	if err != nil {
+		return PlyMesh[T]{}, err
+	}
+	if p.format != PlyAscii {
+		p.line = 0 // line numbers only mean something in text
+	}
+
+	vertexCount := 0
+	for _, e := range elements {
+		if e.name == "vertex" {
+			vertexCount = e.count
+		}
+	}
+
+	m := PlyMesh[T]{}
+	haveVertices := false
+	faces := [][]int{} // only kept when faces come before the vertices
+	ring := []Vec3[T]{}
+	addFace := func(face []int) {
+		ring = ring[:0]
+		for _, v := range face {
+			ring = append(ring, m.Mesh.Positions[v])
+		}
+		for _, tri := range triangulateFace(ring) {
+			m.Mesh.Indices = append(m.Mesh.Indices, [3]int{face[tri[0]], face[tri[1]], face[tri[2]]})
+		}
+	}
+	for _, e := range elements {
+		values := make([]float64, len(e.properties))
+		lists := make([][]float64, len(e.properties))
+
+		switch e.name {
+		case "vertex":
+			x, y, z := e.property("x"), e.property("y"), e.property("z")
+			nx, ny, nz := e.property("nx"), e.property("ny"), e.property("nz")
+			u, v := e.property("u", "s", "texture_u"), e.property("v", "t", "texture_v")
+			red, green, blue, alpha := e.property("red"), e.property("green"), e.property("blue"), e.property("alpha")
+			if x < 0 || y < 0 || z < 0 {
+				return PlyMesh[T]{}, &ParseError{"ply", 0, "vertex element has no x, y and z properties"}
+			}
+
+			colour := func(i int) uint8 {
+				if i < 0 {
+					return 255
+				}
+				c := values[i]
+				if kind := e.properties[i].kind; kind == plyFloat || kind == plyDouble {
+					c *= 255
+				}
+				return uint8(math.Round(math.Max(0, math.Min(255, c))))
+			}
+
+			for i := 0; i < e.count; i++ {
+				if err := p.item(e, i, values, lists); err != nil {
+					return PlyMesh[T]{}, err
+				}
+				m.Mesh.Positions = append(m.Mesh.Positions, Vec3[T]{T(values[x]), T(values[y]), T(values[z])})
+				if nx >= 0 && ny >= 0 && nz >= 0 {
+					m.Mesh.Normals = append(m.Mesh.Normals, Vec3[T]{T(values[nx]), T(values[ny]), T(values[nz])})
+				}
+				if u >= 0 && v >= 0 {
+					m.Mesh.UVs = append(m.Mesh.UVs, Vec2[T]{T(values[u]), T(values[v])})
+				}
+				if red >= 0 && green >= 0 && blue >= 0 {
+					m.Colours = append(m.Colours, [4]uint8{colour(red), colour(green), colour(blue), colour(alpha)})
+				}
+			}
+			haveVertices = true
+
+		case "face":
+			indices := e.property("vertex_indices", "vertex_index")
+			if indices < 0 || e.properties[indices].count == plyNone {
+				return PlyMesh[T]{}, &ParseError{"ply", 0, "face element has no vertex_indices list"}
+			}
+
+			face := []int{}
+			for i := 0; i < e.count; i++ {
+				if err := p.item(e, i, values, lists); err != nil {
+					return PlyMesh[T]{}, err
+				}
+				list := lists[indices]
+				if len(list) < 3 {
+					return PlyMesh[T]{}, p.errorf("face %d has %d vertices, expected at least 3", i, len(list))
+				}
+				face = face[:0]
+				for _, f := range list {
+					if f < 0 || f >= float64(vertexCount) || f != math.Trunc(f) {
+						return PlyMesh[T]{}, p.errorf("face %d vertex index %v out of range, there are %d", i, f, vertexCount)
+					}
+					face = append(face, int(f))
+				}
+				if haveVertices {
+					addFace(face)
+				} else {
+					faces = append(faces, append([]int{}, face...))
+				}
+			}
+
+		default:
+			for i := 0; i < e.count; i++ {
+				if err := p.item(e, i, values, lists); err != nil {
+					return PlyMesh[T]{}, err
+				}
+			}
+		}
+	}
+
+	for _, face := range faces {
+		addFace(face)
+	}
+	return m, nil
+}
+
+/* Writes positions with any normals, UVs and colours, and the triangles as
+ * faces. Values are float or double matching T. Panics if the normals, UVs or
+ * colours don't have one entry per position.
+ */
+func WritePly[T Num](w io.Writer, m PlyMesh[T], format PlyFormat) error {
+	mesh := m.Mesh
+	n := len(mesh.Positions)
+	if (len(mesh.Normals) > 0 && len(mesh.Normals) != n) || (len(mesh.UVs) > 0 && len(mesh.UVs) != n) ||
+		(len(m.Colours) > 0 && len(m.Colours) != n) {
+		panic("WritePly: normals, UVs and colours must have one entry per position")
+	}
+
+	scalar := plyDouble
+	if isFloat32[T]() {
+		scalar = plyFloat
+	}
+	names := []string{"x", "y", "z"}
+	if len(mesh.Normals) > 0 {
+		names = append(names, "nx", "ny", "nz")
+	}
+	if len(mesh.UVs) > 0 {
+		names = append(names, "s", "t")
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "ply\nformat %s 1.0\nelement vertex %d\n", plyFormatNames[format], n)
+	for _, name := range names {
+		fmt.Fprintf(bw, "property %s %s\n", plyTypeNames[scalar], name)
+	}
+	if len(m.Colours) > 0 {
+		bw.WriteString("property uchar red\nproperty uchar green\nproperty uchar blue\nproperty uchar alpha\n")
+	}
+	fmt.Fprintf(bw, "element face %d\nproperty list uchar int vertex_indices\nend_header\n", len(mesh.Indices))
+
+	p := plyWriter{bw: bw, format: format, order: binary.LittleEndian}
+	if format == PlyBinaryBigEndian {
+		p.order = binary.BigEndian
+	}
+
+	for i, v := range mesh.Positions {
+		p.value(scalar, float64(v.X))
+		p.value(scalar, float64(v.Y))
+		p.value(scalar, float64(v.Z))
+		if len(mesh.Normals) > 0 {
+			p.value(scalar, float64(mesh.Normals[i].X))
+			p.value(scalar, float64(mesh.Normals[i].Y))
+			p.value(scalar, float64(mesh.Normals[i].Z))
+		}
+		if len(mesh.UVs) > 0 {
+			p.value(scalar, float64(mesh.UVs[i].X))
+			p.value(scalar, float64(mesh.UVs[i].Y))
+		}
+		if len(m.Colours) > 0 {
+			for _, c := range m.Colours[i] {
+				p.value(plyUchar, float64(c))
+			}
+		}
+		p.end()
+	}
+
+	for _, tri := range mesh.Indices {
+		p.value(plyUchar, 3)
+		for _, v := range tri {
+			p.value(plyInt, float64(v))
+		}
+		p.end()
+	}
+	return bw.Flush()
+}
+
+var errPlyEnd = errors.New("ply: unexpected end")
+
+type plyReader struct {
+	br     *bufio.Reader
+	format PlyFormat
+	order  binary.ByteOrder
+	line   int
+	fields []string // remaining in the current ASCII line
+	buf    [8]byte
+}
+
+func (p *plyReader) errorf(format string, args ...any) error {
+	return &ParseError{"ply", p.line, fmt.Sprintf(format, args...)}
+}
+
+func (p *plyReader) readLine() (string, error) {
+	text, err := p.br.ReadString('\n')
+	if err == io.EOF && text != "" {
+		err = nil
+	}
+	if err == io.EOF {
+		return "", errPlyEnd
+	}
+	p.line++
+	if err != nil {
+		return "", p.errorf("%v", err)
+	}
+	return strings.TrimRight(text, "\r\n"), nil
+}
+
+func (p *plyReader) header() ([]plyElement, error) {
+	line, err := p.readLine()
+	if err != nil && err != errPlyEnd {
+		return nil, err
+	}
+	if err != nil || strings.TrimSpace(line) != "ply" {
+		return nil, &ParseError{"ply", 1, `expected "ply"`}
+	}
+
+	elements := []plyElement{}
+	hasFormat := false
+	for {
+		line, err := p.readLine()
+		if err == errPlyEnd {
+			return nil, p.errorf("missing end_header")
+		} else if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "comment", "obj_info":
+
+		case "format":
+			if len(fields) != 3 {
+				return nil, p.errorf("expected format and version")
+			}
+			switch fields[1] {
+			case "ascii":
+				p.format = PlyAscii
+			case "binary_little_endian":
+				p.format, p.order = PlyBinaryLittleEndian, binary.LittleEndian
+			case "binary_big_endian":
+				p.format, p.order = PlyBinaryBigEndian, binary.BigEndian
+			default:
+				return nil, p.errorf("unknown format %q", fields[1])
+			}
+			hasFormat = true
+
+		case "element":
+			if len(fields) != 3 {
+				return nil, p.errorf("expected element name and count")
+			}
+			count, err := strconv.Atoi(fields[2])
+			if err != nil || count < 0 {
+				return nil, p.errorf("invalid element count %q", fields[2])
+			}
+			elements = append(elements, plyElement{name: fields[1], count: count})
+
+		case "property":
+			if len(elements) == 0 {
+				return nil, p.errorf("property before element")
+			}
+			prop := plyProperty{}
+			kinds := fields[1:2]
+			switch {
+			case len(fields) == 5 && fields[1] == "list":
+				kinds, prop.name = fields[2:4], fields[4]
+			case len(fields) == 3:
+				prop.name = fields[2]
+			default:
+				return nil, p.errorf("invalid property")
+			}
+			for _, kind := range kinds {
+				if _, ok := plyTypes[kind]; !ok {
+					return nil, p.errorf("unknown type %q", kind)
+				}
+			}
+			prop.kind = plyTypes[kinds[len(kinds)-1]]
+			if len(kinds) == 2 {
+				prop.count = plyTypes[kinds[0]]
+			}
+			e := &elements[len(elements)-1]
+			e.properties = append(e.properties, prop)
+
+		case "end_header":
+			if !hasFormat {
+				return nil, p.errorf("missing format")
+			}
+			return elements, nil
+
+		default:
+			return nil, p.errorf("unexpected %q", fields[0])
+		}
+	}
+}
+
+/* Reads item i of an element with single values into values and lists into
+ * lists, both indexed by property.
+ */
+func (p *plyReader) item(e plyElement, i int, values []float64, lists [][]float64) error {
+	err := func() error {
+		for j, prop := range e.properties {
+			if prop.count == plyNone {
+				v, err := p.value(prop.kind)
+				if err != nil {
+					return err
+				}
+				values[j] = v
+				continue
+			}
+
+			n, err := p.value(prop.count)
+			if err != nil {
+				return err
+			}
+			if n < 0 || n != math.Trunc(n) {
+				return p.errorf("%s %d has invalid list length %v", e.name, i, n)
+			}
+			lists[j] = lists[j][:0]
+			for k := 0; k < int(n); k++ {
+				v, err := p.value(prop.kind)
+				if err != nil {
+					return err
+				}
+				lists[j] = append(lists[j], v)
+			}
+		}
+		if len(p.fields) > 0 {
+			return p.errorf("%s %d has too many values", e.name, i)
+		}
+		return nil
+	}()
+
+	if err == errPlyEnd {
+		return p.errorf("data ends in %s %d of %d", e.name, i, e.count)
+	}
+	return err
+}
+
+func (p *plyReader) value(kind plyType) (float64, error) {
+	if p.format == PlyAscii {
+		for len(p.fields) == 0 {
+			line, err := p.readLine()
+			if err != nil {
+				return 0, err
+			}
+			p.fields = strings.Fields(line)
+		}
+		field := p.fields[0]
+		p.fields = p.fields[1:]
+		v, err := strconv.ParseFloat(field, 64)
+		if err != nil {
+			return 0, p.errorf("invalid number %q", field)
+		}
+		return v, nil
+	}
+
+	b := p.buf[:plyTypeSizes[kind]]
+	if _, err := io.ReadFull(p.br, b); err == io.EOF || err == io.ErrUnexpectedEOF {
+		return 0, errPlyEnd
+	} else if err != nil {
+		return 0, p.errorf("%v", err)
+	}
+	switch kind {
+	case plyChar:
+		return float64(int8(b[0])), nil
+	case plyUchar:
+		return float64(b[0]), nil
+	case plyShort:
+		return float64(int16(p.order.Uint16(b))), nil
+	case plyUshort:
+		return float64(p.order.Uint16(b)), nil
+	case plyInt:
+		return float64(int32(p.order.Uint32(b))), nil
+	case plyUint:
+		return float64(p.order.Uint32(b)), nil
+	case plyFloat:
+		return float64(math.Float32frombits(p.order.Uint32(b))), nil
+	}
+	return math.Float64frombits(p.order.Uint64(b)), nil
+}
+
+type plyWriter struct {
+	bw        *bufio.Writer
+	format    PlyFormat
+	order     binary.ByteOrder
+	buf       [8]byte
+	separator bool // ASCII values after the first on a line
+}
+
+func (p *plyWriter) value(kind plyType, v float64) {
+	if p.format == PlyAscii {
+		if p.separator {
+			p.bw.WriteByte(' ')
+		}
+		p.separator = true
+		switch kind {
+		case plyFloat:
+			p.bw.WriteString(strconv.FormatFloat(v, 'g', -1, 32))
+		case plyDouble:
+			p.bw.WriteString(strconv.FormatFloat(v, 'g', -1, 64))
+		default:
+			p.bw.WriteString(strconv.FormatInt(int64(v), 10))
+		}
+		return
+	}
+
+	b := p.buf[:plyTypeSizes[kind]]
+	switch kind {
+	case plyChar, plyUchar:
+		b[0] = byte(int64(v))
+	case plyShort, plyUshort:
+		p.order.PutUint16(b, uint16(int64(v)))
+	case plyInt, plyUint:
+		p.order.PutUint32(b, uint32(int64(v)))
+	case plyFloat:
+		p.order.PutUint32(b, math.Float32bits(float32(v)))
+	case plyDouble:
+		p.order.PutUint64(b, math.Float64bits(v))
+	}
+	p.bw.Write(b)
+}
+
+func (p *plyWriter) end() {
+	if p.format == PlyAscii {
+		p.bw.WriteByte('\n')
+	}
+	p.separator = false
+}
@@ -100,3 +100,7 @@ func (poly Poly[T]) MomentOfInertia() T {
 
 	return (mass * numerator) / (6 * denominator)
 }
+
+func (poly Poly[T]) Bounds() Rect[T] {
+	return RectBounds(poly)
+}
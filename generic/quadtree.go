@@ -0,0 +1,286 @@
+package geom
+
+import "container/heap"
+
+const (
+	quadtreeMaxItems = 8 // items held by a leaf before it splits
+	quadtreeMaxDepth = 12
+)
+
+/* Spatial index of rects, each carrying a payload. Items are identified by the
+ * id returned from Insert, which stays valid until the item is removed and may
+ * then be reused. Items outside the bounds given to NewQuadtree are allowed but
+ * are not subdivided.
+ */
+type Quadtree[T Num, P any] struct {
+	root    *quadNode[T]
+	entries []quadEntry[T, P]
+	free    []int
+	count   int
+}
+
+type quadEntry[T Num, P any] struct {
+	bounds  Rect[T]
+	payload P
+	node    *quadNode[T]
+	alive   bool
+}
+
+type quadNode[T Num] struct {
+	bounds   Rect[T]
+	parent   *quadNode[T]
+	children []*quadNode[T] // none or four
+	items    []int
+	count    int // items in this node and below
+	depth    int
+}
+
+func NewQuadtree[T Num, P any](bounds Rect[T]) *Quadtree[T, P] {
+	return &Quadtree[T, P]{root: &quadNode[T]{bounds: bounds}}
+}
+
+func (q *Quadtree[T, P]) Len() int {
+	return q.count
+}
+
+func (q *Quadtree[T, P]) Insert(bounds Rect[T], payload P) int {
+	id := len(q.entries)
+	if len(q.free) > 0 {
+		id = q.free[len(q.free)-1]
+		q.free = q.free[:len(q.free)-1]
+	} else {
+		q.entries = append(q.entries, quadEntry[T, P]{})
+	}
+
+	q.entries[id] = quadEntry[T, P]{bounds: bounds, payload: payload, alive: true}
+	q.count++
+	q.place(id, q.root)
+	return id
+}
+
+/* Returns false if id is not in the tree */
+func (q *Quadtree[T, P]) Remove(id int) bool {
+	if !q.valid(id) {
+		return false
+	}
+
+	q.detach(id)
+	q.entries[id] = quadEntry[T, P]{}
+	q.free = append(q.free, id)
+	q.count--
+	return true
+}
+
+/* Changes the bounds of an item, returns false if id is not in the tree */
+func (q *Quadtree[T, P]) Move(id int, bounds Rect[T]) bool {
+	if !q.valid(id) {
+		return false
+	}
+
+	e := &q.entries[id]
+	e.bounds = bounds
+	if n := e.node; (n == q.root || n.bounds.containsRect(bounds)) && n.childFor(bounds) == nil {
+		return true // still belongs in the same node
+	}
+
+	q.detach(id)
+	q.place(id, q.root)
+	return true
+}
+
+/* Replaces the payload of an item, returns false if id is not in the tree */
+func (q *Quadtree[T, P]) Update(id int, payload P) bool {
+	if !q.valid(id) {
+		return false
+	}
+	q.entries[id].payload = payload
+	return true
+}
+
+func (q *Quadtree[T, P]) Get(id int) (bounds Rect[T], payload P, ok bool) {
+	if !q.valid(id) {
+		return
+	}
+	e := q.entries[id]
+	return e.bounds, e.payload, true
+}
+
+/* Calls fn for each item whose bounds intersect r, touching edges included.
+ * Stops early if fn returns false.
+ */
+func (q *Quadtree[T, P]) Query(r Rect[T], fn func(id int, payload P) bool) {
+	q.query(q.root, r, fn)
+}
+
+/* Calls fn for each item whose bounds contain v.
+ * Stops early if fn returns false.
+ */
+func (q *Quadtree[T, P]) QueryPoint(v Vec2[T], fn func(id int, payload P) bool) {
+	q.query(q.root, Rect[T]{v, v}, fn)
+}
+
+/* Ids of the k items closest to v, nearest first. The distance to an item is
+ * from v to the nearest point of its bounds, zero if v is inside.
+ */
+func (q *Quadtree[T, P]) Nearest(v Vec2[T], k int) []int {
+	ids := []int{}
+	if k <= 0 {
+		return ids
+	}
+
+	queue := &quadQueue[T]{{dist: 0, node: q.root, id: -1}}
+	for queue.Len() > 0 && len(ids) < k {
+		next := heap.Pop(queue).(quadQueueItem[T])
+		if next.node == nil {
+			ids = append(ids, next.id)
+			continue
+		}
+
+		for _, id := range next.node.items {
+			heap.Push(queue, quadQueueItem[T]{dist: q.entries[id].bounds.dist2(v), id: id})
+		}
+		for _, c := range next.node.children {
+			if c.count > 0 {
+				heap.Push(queue, quadQueueItem[T]{dist: c.bounds.dist2(v), node: c, id: -1})
+			}
+		}
+	}
+
+	return ids
+}
+
+func (q *Quadtree[T, P]) valid(id int) bool {
+	return id >= 0 && id < len(q.entries) && q.entries[id].alive
+}
+
+/* adds id to the deepest node below n which contains its bounds */
+func (q *Quadtree[T, P]) place(id int, n *quadNode[T]) {
+	bounds := q.entries[id].bounds
+	for {
+		n.count++
+		c := n.childFor(bounds)
+		if c == nil {
+			break
+		}
+		n = c
+	}
+
+	n.items = append(n.items, id)
+	q.entries[id].node = n
+
+	if n.children == nil && len(n.items) > quadtreeMaxItems && n.depth < quadtreeMaxDepth {
+		q.split(n)
+	}
+}
+
+/* removes id from its node, collapsing nodes which have become sparse */
+func (q *Quadtree[T, P]) detach(id int) {
+	n := q.entries[id].node
+	for i, item := range n.items {
+		if item == id {
+			n.items[i] = n.items[len(n.items)-1]
+			n.items = n.items[:len(n.items)-1]
+			break
+		}
+	}
+
+	var collapse *quadNode[T]
+	for p := n; p != nil; p = p.parent {
+		p.count--
+		if p.children != nil && p.count <= quadtreeMaxItems {
+			collapse = p
+		}
+	}
+	if collapse != nil {
+		q.collapse(collapse)
+	}
+}
+
+func (q *Quadtree[T, P]) split(n *quadNode[T]) {
+	c := n.bounds.Centre()
+	n.children = []*quadNode[T]{
+		{bounds: Rect[T]{n.bounds.Min, c}},
+		{bounds: Rect[T]{Vec2[T]{c.X, n.bounds.Min.Y}, Vec2[T]{n.bounds.Max.X, c.Y}}},
+		{bounds: Rect[T]{Vec2[T]{n.bounds.Min.X, c.Y}, Vec2[T]{c.X, n.bounds.Max.Y}}},
+		{bounds: Rect[T]{c, n.bounds.Max}},
+	}
+	for _, child := range n.children {
+		child.parent = n
+		child.depth = n.depth + 1
+	}
+
+	items := n.items
+	n.items = nil
+	n.count -= len(items)
+	for _, id := range items {
+		q.place(id, n)
+	}
+}
+
+/* moves every item below n into n */
+func (q *Quadtree[T, P]) collapse(n *quadNode[T]) {
+	var gather func(c *quadNode[T])
+	gather = func(c *quadNode[T]) {
+		for _, id := range c.items {
+			n.items = append(n.items, id)
+			q.entries[id].node = n
+		}
+		for _, child := range c.children {
+			gather(child)
+		}
+	}
+
+	for _, child := range n.children {
+		gather(child)
+	}
+	n.children = nil
+}
+
+func (q *Quadtree[T, P]) query(n *quadNode[T], r Rect[T], fn func(id int, payload P) bool) bool {
+	for _, id := range n.items {
+		if e := q.entries[id]; e.bounds.Intersects(r) && !fn(id, e.payload) {
+			return false
+		}
+	}
+	for _, c := range n.children {
+		if c.count > 0 && c.bounds.Intersects(r) && !q.query(c, r, fn) {
+			return false
+		}
+	}
+	return true
+}
+
+/* the child of n wholly containing r, nil if there is none */
+func (n *quadNode[T]) childFor(r Rect[T]) *quadNode[T] {
+	for _, c := range n.children {
+		if c.bounds.containsRect(r) {
+			return c
+		}
+	}
+	return nil
+}
+
+type quadQueueItem[T Num] struct {
+	dist T
+	node *quadNode[T] // nil for an item
+	id   int
+}
+
+/* min-heap on dist, items before nodes at equal distance */
+type quadQueue[T Num] []quadQueueItem[T]
+
+func (h quadQueue[T]) Len() int      { return len(h) }
+func (h quadQueue[T]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h quadQueue[T]) Less(i, j int) bool {
+	if h[i].dist != h[j].dist {
+		return h[i].dist < h[j].dist
+	}
+	return h[i].node == nil && h[j].node != nil
+}
+func (h *quadQueue[T]) Push(x any) { *h = append(*h, x.(quadQueueItem[T])) }
+func (h *quadQueue[T]) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
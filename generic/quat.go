@@ -0,0 +1,179 @@
+package geom
+
+import "math"
+
+/* Unit quaternions represent rotations, W is the scalar part.
+ * a.Product(b) matches a.Mat4().Product(b.Mat4()).
+ */
+type Quat[T Num] struct {
+	X, Y, Z, W T
+}
+
+func QuatIdentity[T Num]() Quat[T] {
+	return Quat[T]{0, 0, 0, 1}
+}
+
+func QuatConvert[A, B Num](q Quat[A]) Quat[B] {
+	return Quat[B]{B(q.X), B(q.Y), B(q.Z), B(q.W)}
+}
+
+/* Rotation of rad around axis, fingers curled around thumb */
+func QuatAxisAngle[T Num](axis Vec3[T], rad T) Quat[T] {
+	n := axis.Normal()
+	s := T(math.Sin(float64(rad) / 2))
+	c := T(math.Cos(float64(rad) / 2))
+	return Quat[T]{n.X * s, n.Y * s, n.Z * s, c}
+}
+
+func QuatRotationX[T Num](rad T) Quat[T] {
+	return QuatAxisAngle(Vec3[T]{1, 0, 0}, rad)
+}
+
+func QuatRotationY[T Num](rad T) Quat[T] {
+	return QuatAxisAngle(Vec3[T]{0, 1, 0}, rad)
+}
+
+func QuatRotationZ[T Num](rad T) Quat[T] {
+	return QuatAxisAngle(Vec3[T]{0, 0, 1}, rad)
+}
+
+/* Equivalent to Mat4RollPitchYaw */
+func QuatRollPitchYaw[T Num](r, p, y T) Quat[T] {
+	return QuatRotationY(y).Product(QuatRotationX(p)).Product(QuatRotationZ(r))
+}
+
+/* m must be a pure rotation, any translation is ignored */
+func QuatFromMat4[T Num](m Mat4[T]) Quat[T] {
+	trace := float64(m[0] + m[5] + m[10])
+
+	var q Quat[T]
+	switch {
+	case trace > 0:
+		s := T(math.Sqrt(trace+1) * 2)
+		q = Quat[T]{(m[9] - m[6]) / s, (m[2] - m[8]) / s, (m[4] - m[1]) / s, s / 4}
+	case m[0] > m[5] && m[0] > m[10]:
+		s := T(math.Sqrt(float64(1+m[0]-m[5]-m[10])) * 2)
+		q = Quat[T]{s / 4, (m[1] + m[4]) / s, (m[2] + m[8]) / s, (m[9] - m[6]) / s}
+	case m[5] > m[10]:
+		s := T(math.Sqrt(float64(1+m[5]-m[0]-m[10])) * 2)
+		q = Quat[T]{(m[1] + m[4]) / s, s / 4, (m[6] + m[9]) / s, (m[2] - m[8]) / s}
+	default:
+		s := T(math.Sqrt(float64(1+m[10]-m[0]-m[5])) * 2)
+		q = Quat[T]{(m[2] + m[8]) / s, (m[6] + m[9]) / s, s / 4, (m[4] - m[1]) / s}
+	}
+
+	return q.Normal()
+}
+
+func (a Quat[T]) Product(b Quat[T]) Quat[T] {
+	return Quat[T]{
+		a.W*b.X + a.X*b.W + a.Y*b.Z - a.Z*b.Y,
+		a.W*b.Y - a.X*b.Z + a.Y*b.W + a.Z*b.X,
+		a.W*b.Z + a.X*b.Y - a.Y*b.X + a.Z*b.W,
+		a.W*b.W - a.X*b.X - a.Y*b.Y - a.Z*b.Z,
+	}
+}
+
+func (a Quat[T]) Plus(b Quat[T]) Quat[T] {
+	return Quat[T]{a.X + b.X, a.Y + b.Y, a.Z + b.Z, a.W + b.W}
+}
+
+func (q Quat[T]) ScaledBy(f T) Quat[T] {
+	return Quat[T]{q.X * f, q.Y * f, q.Z * f, q.W * f}
+}
+
+func (a Quat[T]) Dot(b Quat[T]) T {
+	return a.X*b.X + a.Y*b.Y + a.Z*b.Z + a.W*b.W
+}
+
+func (q Quat[T]) Len2() T {
+	return q.Dot(q)
+}
+
+func (q Quat[T]) Len() T {
+	return T(math.Sqrt(float64(q.Len2())))
+}
+
+func (q Quat[T]) Normal() Quat[T] {
+	l := q.Len()
+	if l == 0 {
+		return Quat[T]{}
+	}
+	return q.ScaledBy(1 / l)
+}
+
+func (q Quat[T]) Conjugate() Quat[T] {
+	return Quat[T]{-q.X, -q.Y, -q.Z, q.W}
+}
+
+/* Returns the zero quaternion if q has zero length */
+func (q Quat[T]) Inverse() Quat[T] {
+	l2 := q.Len2()
+	if l2 == 0 {
+		return Quat[T]{}
+	}
+	return q.Conjugate().ScaledBy(1 / l2)
+}
+
+/* q must be normalised */
+func (q Quat[T]) RotateVec3(v Vec3[T]) Vec3[T] {
+	u := Vec3[T]{q.X, q.Y, q.Z}
+	t := u.Cross(v).ScaledBy(2)
+	return v.Plus(t.ScaledBy(q.W)).Plus(u.Cross(t))
+}
+
+/* Returns the unit rotation axis and angle in [0, 2pi].
+ * The identity rotation returns the X axis.
+ */
+func (q Quat[T]) AxisAngle() (Vec3[T], T) {
+	n := q.Normal()
+	w := math.Max(-1, math.Min(1, float64(n.W)))
+	s := math.Sqrt(1 - w*w)
+	if s < 1e-9 {
+		return Vec3[T]{1, 0, 0}, 0
+	}
+	return Vec3[T]{n.X, n.Y, n.Z}.ScaledBy(T(1 / s)), T(2 * math.Acos(w))
+}
+
+/* q must be normalised */
+func (q Quat[T]) Mat4() Mat4[T] {
+	xx, yy, zz := q.X*q.X, q.Y*q.Y, q.Z*q.Z
+	xy, xz, yz := q.X*q.Y, q.X*q.Z, q.Y*q.Z
+	wx, wy, wz := q.W*q.X, q.W*q.Y, q.W*q.Z
+
+	return Mat4[T]{
+		1 - 2*(yy+zz), 2 * (xy - wz), 2 * (xz + wy), 0,
+		2 * (xy + wz), 1 - 2*(xx+zz), 2 * (yz - wx), 0,
+		2 * (xz - wy), 2 * (yz + wx), 1 - 2*(xx+yy), 0,
+		0, 0, 0, 1,
+	}
+}
+
+/* Normalised linear interpolation, takes the shortest path */
+func (a Quat[T]) Nlerp(b Quat[T], t T) Quat[T] {
+	if a.Dot(b) < 0 {
+		b = b.ScaledBy(-1)
+	}
+	return a.ScaledBy(1 - t).Plus(b.ScaledBy(t)).Normal()
+}
+
+/* Spherical linear interpolation, takes the shortest path.
+ * a and b must be normalised.
+ */
+func (a Quat[T]) Slerp(b Quat[T], t T) Quat[T] {
+	dot := float64(a.Dot(b))
+	if dot < 0 {
+		b = b.ScaledBy(-1)
+		dot = -dot
+	}
+
+	if dot > 0.9995 { // nearly parallel, sin(theta) would be unstable
+		return a.Nlerp(b, t)
+	}
+
+	theta := math.Acos(dot)
+	sin := math.Sin(theta)
+	wa := T(math.Sin((1-float64(t))*theta) / sin)
+	wb := T(math.Sin(float64(t)*theta) / sin)
+	return a.ScaledBy(wa).Plus(b.ScaledBy(wb))
+}
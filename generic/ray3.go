@@ -0,0 +1,187 @@
+package geom
+
+import "math"
+
+/* Dir need not be normalised, distances are in units of Dir */
+type Ray3[T Num] struct {
+	Origin, Dir Vec3[T]
+}
+
+type Ray3Hit[T Num] struct {
+	Dist   T
+	Point  Vec3[T]
+	Normal Vec3[T] // unit length
+}
+
+/* Ray through normalised device coordinates ndc, from the near plane towards
+ * the far plane. viewProj is the projection matrix times the view matrix, as
+ * used with TransformVec3. Returns false if viewProj is singular.
+ */
+func Ray3Unproject[T Num](viewProj Mat4[T], ndc Vec2[T]) (Ray3[T], bool) {
+	inv, ok := viewProj.Inverse()
+	if !ok {
+		return Ray3[T]{}, false
+	}
+
+	near := inv.TransformVec3(Vec3[T]{ndc.X, ndc.Y, -1}, 1)
+	far := inv.TransformVec3(Vec3[T]{ndc.X, ndc.Y, 1}, 1)
+	return Ray3[T]{near, far.Minus(near).Normal()}, true
+}
+
+func (r Ray3[T]) At(t T) Vec3[T] {
+	return r.Origin.Plus(r.Dir.ScaledBy(t))
+}
+
+func (r Ray3[T]) hit(t T, normal Vec3[T]) Ray3Hit[T] {
+	return Ray3Hit[T]{t, r.At(t), normal}
+}
+
+/* Möller–Trumbore, hits either side of the triangle. The normal follows the
+ * winding, (b-a).Cross(c-a). u and v are the barycentric weights of b and c.
+ */
+func (r Ray3[T]) IntersectTriangle(a, b, c Vec3[T]) (hit Ray3Hit[T], u, v T, ok bool) {
+	e1 := b.Minus(a)
+	e2 := c.Minus(a)
+
+	p := r.Dir.Cross(e2)
+	det := e1.Dot(p)
+	if det == 0 { // parallel or degenerate
+		return
+	}
+
+	inv := 1 / det
+	s := r.Origin.Minus(a)
+	u = s.Dot(p) * inv
+	if u < 0 || u > 1 {
+		return hit, 0, 0, false
+	}
+
+	q := s.Cross(e1)
+	v = r.Dir.Dot(q) * inv
+	if v < 0 || u+v > 1 {
+		return hit, 0, 0, false
+	}
+
+	t := e2.Dot(q) * inv
+	if t < 0 {
+		return hit, 0, 0, false
+	}
+
+	return r.hit(t, e1.Cross(e2).Normal()), u, v, true
+}
+
+/* Plane through point with normal, hits either side. The normal is returned as
+ * given, normalised.
+ */
+func (r Ray3[T]) IntersectPlane(point, normal Vec3[T]) (Ray3Hit[T], bool) {
+	denom := normal.Dot(r.Dir)
+	if denom == 0 {
+		return Ray3Hit[T]{}, false
+	}
+
+	t := normal.Dot(point.Minus(r.Origin)) / denom
+	if t < 0 {
+		return Ray3Hit[T]{}, false
+	}
+	return r.hit(t, normal.Normal()), true
+}
+
+/* Nearest hit in front of the origin, the exit if the origin is inside.
+ * The normal points outwards.
+ */
+func (r Ray3[T]) IntersectSphere(centre Vec3[T], radius T) (Ray3Hit[T], bool) {
+	m := r.Origin.Minus(centre)
+	a := r.Dir.Len2()
+	b := m.Dot(r.Dir)
+	c := m.Len2() - radius*radius
+
+	if a == 0 {
+		return Ray3Hit[T]{}, false
+	}
+
+	disc := b*b - a*c
+	if disc < 0 {
+		return Ray3Hit[T]{}, false
+	}
+
+	sqrt := T(math.Sqrt(float64(disc)))
+	t := (-b - sqrt) / a
+	if t < 0 {
+		t = (-b + sqrt) / a
+	}
+	if t < 0 {
+		return Ray3Hit[T]{}, false
+	}
+
+	p := r.At(t)
+	return Ray3Hit[T]{t, p, p.Minus(centre).Normal()}, true
+}
+
+/* Slab test. The hit is the entry into c with the outward normal of the entry
+ * face, or the exit if the origin is inside. exit is the distance at which the
+ * ray leaves c.
+ */
+func (r Ray3[T]) IntersectCuboid(c Cuboid[T]) (hit Ray3Hit[T], exit T, ok bool) {
+	entry, exit, entryNormal, exitNormal, ok := r.slab(c)
+	switch {
+	case !ok || exit < 0:
+		return hit, 0, false
+	case entry < 0:
+		return r.hit(exit, exitNormal), exit, true
+	}
+	return r.hit(entry, entryNormal), exit, true
+}
+
+/* distance at which r enters c, zero if the origin is inside */
+func (r Ray3[T]) entry(c Cuboid[T]) (T, bool) {
+	entry, exit, _, _, ok := r.slab(c)
+	if !ok || exit < 0 {
+		return 0, false
+	}
+	return max(entry, 0), true
+}
+
+/* distances along the infinite line at which r enters and leaves c */
+func (r Ray3[T]) slab(c Cuboid[T]) (entry, exit T, entryNormal, exitNormal Vec3[T], ok bool) {
+	if r.Dir.Len2() == 0 {
+		return
+	}
+
+	entry = T(math.Inf(-1))
+	exit = T(math.Inf(1))
+
+	for axis := 0; axis < 3; axis++ {
+		o := vec3Axis(r.Origin, axis)
+		d := vec3Axis(r.Dir, axis)
+		lo := vec3Axis(c.Min, axis)
+		hi := vec3Axis(c.Max, axis)
+
+		if d == 0 {
+			if o < lo || o > hi {
+				return entry, exit, entryNormal, exitNormal, false
+			}
+			continue
+		}
+
+		t0 := (lo - o) / d
+		t1 := (hi - o) / d
+		n0 := vec3Unit[T](axis, -1)
+		n1 := vec3Unit[T](axis, 1)
+		if t0 > t1 {
+			t0, t1 = t1, t0
+			n0, n1 = n1, n0
+		}
+
+		if t0 > entry {
+			entry, entryNormal = t0, n0
+		}
+		if t1 < exit {
+			exit, exitNormal = t1, n1
+		}
+		if entry > exit {
+			return entry, exit, entryNormal, exitNormal, false
+		}
+	}
+
+	return entry, exit, entryNormal, exitNormal, true
+}
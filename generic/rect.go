@@ -70,3 +70,86 @@ func (r Rect[T]) Verts() [4]Vec2[T] {
 		{r.Min.X, r.Max.Y},
 	}
 }
+
+/* Bounding rect of points, the zero Rect if there are none */
+func RectBounds[T Num](points []Vec2[T]) Rect[T] {
+	if len(points) == 0 {
+		return Rect[T]{}
+	}
+
+	r := Rect[T]{points[0], points[0]}
+	for _, v := range points[1:] {
+		r = r.ExpandToInclude(v)
+	}
+	return r
+}
+
+func (r Rect[T]) Centre() Vec2[T] {
+	return r.Min.Plus(r.Max).ScaledBy(0.5)
+}
+
+/* Touching edges count as intersecting */
+func (a Rect[T]) Intersects(b Rect[T]) bool {
+	return a.Min.X <= b.Max.X &&
+		a.Max.X >= b.Min.X &&
+		a.Min.Y <= b.Max.Y &&
+		a.Max.Y >= b.Min.Y
+}
+
+/* b lies wholly inside a, touching edges included */
+func (a Rect[T]) containsRect(b Rect[T]) bool {
+	return b.Min.X >= a.Min.X && b.Max.X <= a.Max.X &&
+		b.Min.Y >= a.Min.Y && b.Max.Y <= a.Max.Y
+}
+
+/* Returns false if a and b do not intersect */
+func (a Rect[T]) Intersection(b Rect[T]) (Rect[T], bool) {
+	if !a.Intersects(b) {
+		return Rect[T]{}, false
+	}
+	return Rect[T]{
+		Vec2[T]{max(a.Min.X, b.Min.X), max(a.Min.Y, b.Min.Y)},
+		Vec2[T]{min(a.Max.X, b.Max.X), min(a.Max.Y, b.Max.Y)},
+	}, true
+}
+
+/* Smallest rect containing a and b */
+func (a Rect[T]) Union(b Rect[T]) Rect[T] {
+	return Rect[T]{
+		Vec2[T]{min(a.Min.X, b.Min.X), min(a.Min.Y, b.Min.Y)},
+		Vec2[T]{max(a.Max.X, b.Max.X), max(a.Max.Y, b.Max.Y)},
+	}
+}
+
+func (r Rect[T]) ExpandToInclude(v Vec2[T]) Rect[T] {
+	return Rect[T]{
+		Vec2[T]{min(r.Min.X, v.X), min(r.Min.Y, v.Y)},
+		Vec2[T]{max(r.Max.X, v.X), max(r.Max.Y, v.Y)},
+	}
+}
+
+/* Moves each edge inwards by margin, collapsing to the centre if too small */
+func (r Rect[T]) Inset(margin T) Rect[T] {
+	c := r.Centre()
+	return Rect[T]{
+		Vec2[T]{min(r.Min.X+margin, c.X), min(r.Min.Y+margin, c.Y)},
+		Vec2[T]{max(r.Max.X-margin, c.X), max(r.Max.Y-margin, c.Y)},
+	}
+}
+
+func (r Rect[T]) Outset(margin T) Rect[T] {
+	return r.Inset(-margin)
+}
+
+/* Closest point in r to v */
+func (r Rect[T]) Clamp(v Vec2[T]) Vec2[T] {
+	return Vec2[T]{
+		clamp(v.X, r.Min.X, r.Max.X),
+		clamp(v.Y, r.Min.Y, r.Max.Y),
+	}
+}
+
+/* squared distance from v to the closest point in r */
+func (r Rect[T]) dist2(v Vec2[T]) T {
+	return r.Clamp(v).Minus(v).Len2()
+}
@@ -0,0 +1,224 @@
+package geom
+
+type Segment2[T Num] struct {
+	A, B Vec2[T]
+}
+
+/* Infinite in the direction of Dir, which need not be normalised */
+type Ray2[T Num] struct {
+	Origin, Dir Vec2[T]
+}
+
+/* Passes through P in the direction of Dir, which need not be normalised */
+type Line2[T Num] struct {
+	P, Dir Vec2[T]
+}
+
+type IntersectKind int
+
+const (
+	IntersectNone     IntersectKind = iota
+	IntersectPoint                  // single point of intersection
+	IntersectParallel               // parallel, or collinear without touching
+	IntersectOverlap                // collinear and sharing more than a point
+)
+
+func Line2Through[T Num](a, b Vec2[T]) Line2[T] {
+	return Line2[T]{a, b.Minus(a)}
+}
+
+func (s Segment2[T]) Vec() Vec2[T] {
+	return s.B.Minus(s.A)
+}
+
+func (s Segment2[T]) Len() T {
+	return s.Vec().Len()
+}
+
+func (s Segment2[T]) Len2() T {
+	return s.Vec().Len2()
+}
+
+func (s Segment2[T]) Line() Line2[T] {
+	return Line2[T]{s.A, s.Vec()}
+}
+
+/* A at t=0 and B at t=1 */
+func (s Segment2[T]) At(t T) Vec2[T] {
+	return s.A.Plus(s.Vec().ScaledBy(t))
+}
+
+/* Parameter of the projection of v onto the segment's line, A at 0 and B at 1 */
+func (s Segment2[T]) Project(v Vec2[T]) T {
+	d := s.Vec()
+	l2 := d.Len2()
+	if l2 == 0 {
+		return 0
+	}
+	return v.Minus(s.A).Dot(d) / l2
+}
+
+func (s Segment2[T]) ClosestPoint(v Vec2[T]) Vec2[T] {
+	return s.At(clamp(s.Project(v), 0, 1))
+}
+
+func (s Segment2[T]) Distance(v Vec2[T]) T {
+	return v.Minus(s.ClosestPoint(v)).Len()
+}
+
+/* For IntersectPoint the returned segment has A == B at the intersection.
+ * For IntersectOverlap it is the shared part, in the direction of s.
+ */
+func (s Segment2[T]) Intersect(b Segment2[T]) (Segment2[T], IntersectKind) {
+	d1, d2 := s.Vec(), b.Vec()
+	w := b.A.Minus(s.A)
+
+	switch {
+	case d1.Len2() == 0 && d2.Len2() == 0:
+		if s.A == b.A {
+			return Segment2[T]{s.A, s.A}, IntersectPoint
+		}
+		return Segment2[T]{}, IntersectNone
+	case d1.Len2() == 0:
+		if b.Distance(s.A) == 0 {
+			return Segment2[T]{s.A, s.A}, IntersectPoint
+		}
+		return Segment2[T]{}, IntersectNone
+	case d2.Len2() == 0:
+		if s.Distance(b.A) == 0 {
+			return Segment2[T]{b.A, b.A}, IntersectPoint
+		}
+		return Segment2[T]{}, IntersectNone
+	}
+
+	denom := d1.Cross(d2)
+	if denom == 0 {
+		if w.Cross(d1) != 0 {
+			return Segment2[T]{}, IntersectParallel
+		}
+		t0, t1 := s.Project(b.A), s.Project(b.B)
+		return s.overlap(max(0, min(t0, t1)), min(1, max(t0, t1)))
+	}
+
+	t := w.Cross(d2) / denom
+	u := w.Cross(d1) / denom
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return Segment2[T]{}, IntersectNone
+	}
+
+	p := s.At(t)
+	return Segment2[T]{p, p}, IntersectPoint
+}
+
+/* part of s between collinear parameters start and end */
+func (s Segment2[T]) overlap(start, end T) (Segment2[T], IntersectKind) {
+	switch {
+	case start > end:
+		return Segment2[T]{}, IntersectParallel
+	case start == end:
+		p := s.At(start)
+		return Segment2[T]{p, p}, IntersectPoint
+	}
+	return Segment2[T]{s.At(start), s.At(end)}, IntersectOverlap
+}
+
+func (r Ray2[T]) At(t T) Vec2[T] {
+	return r.Origin.Plus(r.Dir.ScaledBy(t))
+}
+
+/* Parameter of the projection of v onto the ray's line in units of Dir */
+func (r Ray2[T]) Project(v Vec2[T]) T {
+	l2 := r.Dir.Len2()
+	if l2 == 0 {
+		return 0
+	}
+	return v.Minus(r.Origin).Dot(r.Dir) / l2
+}
+
+func (r Ray2[T]) ClosestPoint(v Vec2[T]) Vec2[T] {
+	return r.At(max(0, r.Project(v)))
+}
+
+func (r Ray2[T]) Distance(v Vec2[T]) T {
+	return v.Minus(r.ClosestPoint(v)).Len()
+}
+
+/* As Segment2.Intersect, an overlap is returned in the direction of the ray.
+ * Use Project on the result for the distance along the ray.
+ */
+func (r Ray2[T]) IntersectSegment(s Segment2[T]) (Segment2[T], IntersectKind) {
+	d := s.Vec()
+	if r.Dir.Len2() == 0 {
+		return Segment2[T]{}, IntersectNone
+	}
+	if d.Len2() == 0 {
+		if r.Distance(s.A) == 0 {
+			return Segment2[T]{s.A, s.A}, IntersectPoint
+		}
+		return Segment2[T]{}, IntersectNone
+	}
+
+	w := s.A.Minus(r.Origin)
+	denom := r.Dir.Cross(d)
+	if denom == 0 {
+		if w.Cross(r.Dir) != 0 {
+			return Segment2[T]{}, IntersectParallel
+		}
+
+		t0, t1 := r.Project(s.A), r.Project(s.B)
+		start, end := max(0, min(t0, t1)), max(t0, t1)
+		switch {
+		case start > end:
+			return Segment2[T]{}, IntersectParallel
+		case start == end:
+			p := r.At(start)
+			return Segment2[T]{p, p}, IntersectPoint
+		}
+		return Segment2[T]{r.At(start), r.At(end)}, IntersectOverlap
+	}
+
+	t := w.Cross(d) / denom
+	u := w.Cross(r.Dir) / denom
+	if t < 0 || u < 0 || u > 1 {
+		return Segment2[T]{}, IntersectNone
+	}
+
+	p := r.At(t)
+	return Segment2[T]{p, p}, IntersectPoint
+}
+
+func (l Line2[T]) At(t T) Vec2[T] {
+	return l.P.Plus(l.Dir.ScaledBy(t))
+}
+
+/* Parameter of the projection of v onto the line in units of Dir */
+func (l Line2[T]) Project(v Vec2[T]) T {
+	l2 := l.Dir.Len2()
+	if l2 == 0 {
+		return 0
+	}
+	return v.Minus(l.P).Dot(l.Dir) / l2
+}
+
+func (l Line2[T]) ClosestPoint(v Vec2[T]) Vec2[T] {
+	return l.At(l.Project(v))
+}
+
+func (l Line2[T]) Distance(v Vec2[T]) T {
+	return v.Minus(l.ClosestPoint(v)).Len()
+}
+
+/* IntersectOverlap is returned for coincident lines, with no point */
+func (a Line2[T]) Intersect(b Line2[T]) (Vec2[T], IntersectKind) {
+	denom := a.Dir.Cross(b.Dir)
+	w := b.P.Minus(a.P)
+
+	if denom == 0 {
+		if w.Cross(a.Dir) == 0 {
+			return Vec2[T]{}, IntersectOverlap
+		}
+		return Vec2[T]{}, IntersectParallel
+	}
+
+	return a.At(w.Cross(b.Dir) / denom), IntersectPoint
+}
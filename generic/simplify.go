@@ -0,0 +1,289 @@
+package geom
+
+import (
+	"container/heap"
+	"math"
+)
+
+type SimplifyMethod int
+
+const (
+	SimplifyDouglasPeucker SimplifyMethod = iota // Tolerance is a distance
+	SimplifyVisvalingam                          // Tolerance is an area
+)
+
+/* Closed treats the poly as a ring, otherwise the ends are always kept. With
+ * PreserveTopology the result does not self-intersect, as long as the input
+ * does not.
+ */
+type SimplifyStyle[T Num] struct {
+	Method           SimplifyMethod
+	Tolerance        T
+	Closed           bool
+	PreserveTopology bool
+}
+
+/* Removes verts which make little difference to the shape. Lines keep at least
+ * 2 verts and rings at least 3.
+ */
+func (poly Poly[T]) Simplify(style SimplifyStyle[T]) Poly[T] {
+	points := make(Poly[float64], 0, len(poly))
+	for _, v := range poly {
+		v64 := Vec2Convert[T, float64](v)
+		if len(points) == 0 || v64 != points[len(points)-1] {
+			points = append(points, v64)
+		}
+	}
+	if style.Closed && len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+
+	minVerts := 2
+	if style.Closed {
+		minVerts = 3
+	}
+	if len(points) <= minVerts {
+		return PolyConvert[float64, T](points)
+	}
+
+	var keep []bool
+	switch style.Method {
+	case SimplifyDouglasPeucker:
+		keep = simplifyDouglasPeucker(points, float64(style.Tolerance), style.Closed, style.PreserveTopology)
+	case SimplifyVisvalingam:
+		keep = simplifyVisvalingam(points, float64(style.Tolerance), style.Closed, style.PreserveTopology)
+	default:
+		panic("invalid SimplifyMethod")
+	}
+
+	simplified := Poly[T]{}
+	for i, k := range keep {
+		if k {
+			simplified = append(simplified, Vec2Convert[float64, T](points[i]))
+		}
+	}
+	return simplified
+}
+
+/* Keeps the furthest vert from each chord while it is beyond tolerance. With
+ * topology preserved, chords which cross are split until none do.
+ */
+func simplifyDouglasPeucker(points Poly[float64], tolerance float64, closed, topology bool) []bool {
+	n := len(points)
+	keep := make([]bool, n)
+
+	// furthest vert strictly between a and b, indices wrap for rings
+	furthest := func(a, b int) (int, float64) {
+		chord := Segment2[float64]{points[a%n], points[b%n]}
+		best, bestDist := -1, -1.0
+		for i := a + 1; i < b; i++ {
+			if d := chord.Distance(points[i%n]); d > bestDist {
+				best, bestDist = i, d
+			}
+		}
+		return best, bestDist
+	}
+
+	var split func(a, b int)
+	split = func(a, b int) {
+		if i, d := furthest(a, b); i >= 0 && d > tolerance {
+			keep[i%n] = true
+			split(a, i)
+			split(i, b)
+		}
+	}
+
+	keep[0] = true
+	if closed {
+		// the vert furthest from the first divides the ring in two
+		far := 1
+		for i := range points {
+			if points[i].Minus(points[0]).Len2() > points[far].Minus(points[0]).Len2() {
+				far = i
+			}
+		}
+		keep[far] = true
+		split(0, far)
+		split(far, n)
+
+		// a ring needs a third vert
+		if len(keptIndices(keep)) < 3 {
+			i, di := furthest(0, far)
+			if j, dj := furthest(far, n); dj > di {
+				i = j
+			}
+			keep[i%n] = true
+		}
+	} else {
+		keep[n-1] = true
+		split(0, n-1)
+	}
+
+	if !topology {
+		return keep
+	}
+
+	for {
+		kept := keptIndices(keep)
+		crossing := simplifyCrossing(points, kept, closed)
+		if len(crossing) == 0 {
+			return keep
+		}
+
+		added := false
+		for _, c := range crossing {
+			a, b := kept[c], kept[(c+1)%len(kept)]
+			if b <= a {
+				b += n
+			}
+			if i, _ := furthest(a, b); i >= 0 {
+				keep[i%n] = true
+				added = true
+			}
+		}
+		if !added {
+			return keep // the input crosses itself
+		}
+	}
+}
+
+/* Repeatedly removes the vert whose triangle with its neighbours has the least
+ * area while it is below tolerance. With topology preserved, verts whose
+ * removal would make the line cross itself are left in.
+ */
+func simplifyVisvalingam(points Poly[float64], tolerance float64, closed, topology bool) []bool {
+	n := len(points)
+	keep := make([]bool, n)
+	prev, next := make([]int, n), make([]int, n)
+	version := make([]int, n)
+	for i := range points {
+		keep[i] = true
+		prev[i], next[i] = (i+n-1)%n, (i+1)%n
+	}
+
+	removable := func(i int) bool {
+		return closed || (i != 0 && i != n-1)
+	}
+	area := func(i int) float64 {
+		a, b, c := points[prev[i]], points[i], points[next[i]]
+		return math.Abs(b.Minus(a).Cross(c.Minus(a))) / 2
+	}
+
+	queue := &visvalingamQueue{}
+	for i := range points {
+		if removable(i) {
+			heap.Push(queue, visvalingamItem{area(i), i, 0})
+		}
+	}
+
+	remaining := n
+	minVerts := 2
+	if closed {
+		minVerts = 3
+	}
+
+	for queue.Len() > 0 && remaining > minVerts {
+		item := heap.Pop(queue).(visvalingamItem)
+		i := item.index
+		if !keep[i] || item.version != version[i] {
+			continue
+		}
+		if item.area >= tolerance {
+			break
+		}
+		if topology && visvalingamCrosses(points, prev, next, i, closed) {
+			continue // retried if a neighbour is removed
+		}
+
+		keep[i] = false
+		remaining--
+		p, q := prev[i], next[i]
+		next[p], prev[q] = q, p
+
+		for _, j := range []int{p, q} {
+			if removable(j) {
+				version[j]++
+				heap.Push(queue, visvalingamItem{area(j), j, version[j]})
+			}
+		}
+	}
+	return keep
+}
+
+/* whether joining the neighbours of i would cross another edge */
+func visvalingamCrosses(points Poly[float64], prev, next []int, i int, closed bool) bool {
+	p, q := prev[i], next[i]
+	chord := Segment2[float64]{points[p], points[q]}
+
+	// edges from prev[j] to j, lines have no edge back to the start
+	for j := next[q]; ; j = next[j] {
+		if closed || j != 0 {
+			_, kind := chord.Intersect(Segment2[float64]{points[prev[j]], points[j]})
+			shared := prev[j] == q || j == p
+			if kind == IntersectOverlap || (kind == IntersectPoint && !shared) {
+				return true
+			}
+		}
+		if j == p {
+			return false
+		}
+	}
+}
+
+/* indices into kept of edges which cross another edge */
+func simplifyCrossing(points Poly[float64], kept []int, closed bool) []int {
+	m := len(kept) - 1
+	if closed {
+		m = len(kept)
+	}
+	edge := func(i int) Segment2[float64] {
+		return Segment2[float64]{points[kept[i]], points[kept[(i+1)%len(kept)]]}
+	}
+
+	crossing := []int{}
+	for i := 0; i < m; i++ {
+		for j := i + 1; j < m; j++ {
+			adjacent := j == i+1 || (closed && i == 0 && j == m-1)
+			_, kind := edge(i).Intersect(edge(j))
+			if kind == IntersectOverlap || (kind == IntersectPoint && !adjacent) {
+				crossing = append(crossing, i, j)
+			}
+		}
+	}
+	return crossing
+}
+
+func keptIndices(keep []bool) []int {
+	kept := []int{}
+	for i, k := range keep {
+		if k {
+			kept = append(kept, i)
+		}
+	}
+	return kept
+}
+
+type visvalingamItem struct {
+	area    float64
+	index   int
+	version int // stale once the area of the vert changes
+}
+
+/* min-heap on area, then index */
+type visvalingamQueue []visvalingamItem
+
+func (h visvalingamQueue) Len() int      { return len(h) }
+func (h visvalingamQueue) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h visvalingamQueue) Less(i, j int) bool {
+	if h[i].area != h[j].area {
+		return h[i].area < h[j].area
+	}
+	return h[i].index < h[j].index
+}
+func (h *visvalingamQueue) Push(x any) { *h = append(*h, x.(visvalingamItem)) }
+func (h *visvalingamQueue) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
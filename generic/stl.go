@@ -0,0 +1,225 @@
+package geom
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/* Reads an ASCII or binary STL file. Each facet gets its own three positions
+ * with the facet normal, use Weld to share them. Facets without a normal use
+ * the normal of the triangle. Errors are *ParseError.
+ */
+func ReadStl[T Num](r io.Reader) (Mesh3[T], error) {
+	br := bufio.NewReader(r)
+	if stlIsAscii(br) {
+		return readStlAscii[T](br)
+	}
+	return readStlBinary[T](br)
+}
+
+/* Binary files may also start with "solid" so the start must look like text,
+ * which has no control characters.
+ */
+func stlIsAscii(br *bufio.Reader) bool {
+	start, _ := br.Peek(512)
+	if !bytes.HasPrefix(bytes.ToLower(start), []byte("solid")) {
+		return false
+	}
+	for _, c := range start {
+		if c < ' ' && c != '\t' && c != '\n' && c != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+func readStlBinary[T Num](br *bufio.Reader) (Mesh3[T], error) {
+	var header [84]byte
+	if _, err := io.ReadFull(br, header[:]); err != nil {
+		return Mesh3[T]{}, &ParseError{"stl", 0, "missing header"}
+	}
+	count := int(binary.LittleEndian.Uint32(header[80:]))
+
+	// the count may be wrong so don't trust it for large allocations
+	reserve := count
+	if reserve > 1<<16 {
+		reserve = 1 << 16
+	}
+	m := Mesh3[T]{
+		Positions: make([]Vec3[T], 0, 3*reserve),
+		Normals:   make([]Vec3[T], 0, 3*reserve),
+		Indices:   make([][3]int, 0, reserve),
+	}
+
+	var facet [50]byte
+	vec := func(b []byte) Vec3[T] {
+		return Vec3[T]{
+			T(math.Float32frombits(binary.LittleEndian.Uint32(b[0:]))),
+			T(math.Float32frombits(binary.LittleEndian.Uint32(b[4:]))),
+			T(math.Float32frombits(binary.LittleEndian.Uint32(b[8:]))),
+		}
+	}
+	for i := 0; i < count; i++ {
+		if _, err := io.ReadFull(br, facet[:]); err != nil {
+			return Mesh3[T]{}, &ParseError{"stl", 0, fmt.Sprintf("data ends after %d of %d triangles", i, count)}
+		}
+		m.addFacet(vec(facet[0:]), [3]Vec3[T]{vec(facet[12:]), vec(facet[24:]), vec(facet[36:])})
+	}
+	return m, nil
+}
+
+func readStlAscii[T Num](br *bufio.Reader) (Mesh3[T], error) {
+	m := Mesh3[T]{Positions: []Vec3[T]{}, Normals: []Vec3[T]{}, Indices: [][3]int{}}
+
+	line := 0
+	errorf := func(format string, args ...any) error {
+		return &ParseError{"stl", line, fmt.Sprintf(format, args...)}
+	}
+	vec := func(args []string) (Vec3[T], error) {
+		if len(args) != 3 {
+			return Vec3[T]{}, errorf("expected 3 numbers, got %d", len(args))
+		}
+		var v [3]T
+		for i, arg := range args {
+			f, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return Vec3[T]{}, errorf("invalid number %q", arg)
+			}
+			v[i] = T(f)
+		}
+		return Vec3[T]{v[0], v[1], v[2]}, nil
+	}
+
+	inFacet := false
+	var normal Vec3[T]
+	var verts [3]Vec3[T]
+	count := 0
+
+	scanner := bufio.NewScanner(br)
+	scanner.Buffer(nil, 1<<24)
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(fields[0]) {
+		case "solid", "endsolid", "endloop":
+
+		case "outer":
+			if !inFacet {
+				return Mesh3[T]{}, errorf("loop outside of a facet")
+			}
+
+		case "facet":
+			if inFacet {
+				return Mesh3[T]{}, errorf("facet inside of a facet")
+			}
+			if len(fields) < 2 || strings.ToLower(fields[1]) != "normal" {
+				return Mesh3[T]{}, errorf("expected facet normal")
+			}
+			if normal, err = vec(fields[2:]); err != nil {
+				return Mesh3[T]{}, err
+			}
+			inFacet, count = true, 0
+
+		case "vertex":
+			if !inFacet {
+				return Mesh3[T]{}, errorf("vertex outside of a facet")
+			}
+			if count == 3 {
+				return Mesh3[T]{}, errorf("facet has more than 3 vertices")
+			}
+			if verts[count], err = vec(fields[1:]); err != nil {
+				return Mesh3[T]{}, err
+			}
+			count++
+
+		case "endfacet":
+			if !inFacet || count != 3 {
+				return Mesh3[T]{}, errorf("facet has %d vertices, expected 3", count)
+			}
+			m.addFacet(normal, verts)
+			inFacet = false
+
+		default:
+			return Mesh3[T]{}, errorf("unexpected %q", fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		line++ // the line that failed to read
+		return Mesh3[T]{}, errorf("%v", err)
+	}
+	if inFacet {
+		return Mesh3[T]{}, errorf("missing endfacet")
+	}
+	return m, nil
+}
+
+func (m *Mesh3[T]) addFacet(normal Vec3[T], verts [3]Vec3[T]) {
+	if normal == (Vec3[T]{}) {
+		normal = verts[1].Minus(verts[0]).Cross(verts[2].Minus(verts[0])).Normal()
+	}
+	i := len(m.Positions)
+	m.Positions = append(m.Positions, verts[:]...)
+	m.Normals = append(m.Normals, normal, normal, normal)
+	m.Indices = append(m.Indices, [3]int{i, i + 1, i + 2})
+}
+
+/* Writes an ASCII STL file with the face normals of the mesh */
+func WriteStl[T Num](w io.Writer, m Mesh3[T], name string) error {
+	bw := bufio.NewWriter(w)
+	vec := func(v Vec3[T]) string {
+		return formatNum(v.X) + " " + formatNum(v.Y) + " " + formatNum(v.Z)
+	}
+
+	fmt.Fprintf(bw, "solid %s\n", name)
+	for i, n := range m.FaceNormals() {
+		tri := m.Indices[i]
+		fmt.Fprintf(bw, "facet normal %s\n outer loop\n", vec(n))
+		for _, v := range tri {
+			fmt.Fprintf(bw, "  vertex %s\n", vec(m.Positions[v]))
+		}
+		bw.WriteString(" endloop\nendfacet\n")
+	}
+	fmt.Fprintf(bw, "endsolid %s\n", name)
+	return bw.Flush()
+}
+
+/* Writes a binary STL file with the face normals of the mesh. Binary STL holds
+ * float32 values.
+ */
+func WriteStlBinary[T Num](w io.Writer, m Mesh3[T]) error {
+	bw := bufio.NewWriter(w)
+
+	var header [84]byte
+	copy(header[:], "binary STL")
+	binary.LittleEndian.PutUint32(header[80:], uint32(len(m.Indices)))
+	bw.Write(header[:])
+
+	var facet [50]byte
+	put := func(b []byte, v Vec3[T]) {
+		binary.LittleEndian.PutUint32(b[0:], math.Float32bits(float32(v.X)))
+		binary.LittleEndian.PutUint32(b[4:], math.Float32bits(float32(v.Y)))
+		binary.LittleEndian.PutUint32(b[8:], math.Float32bits(float32(v.Z)))
+	}
+	for i, n := range m.FaceNormals() {
+		tri := m.Indices[i]
+		put(facet[0:], n)
+		put(facet[12:], m.Positions[tri[0]])
+		put(facet[24:], m.Positions[tri[1]])
+		put(facet[36:], m.Positions[tri[2]])
+		if _, err := bw.Write(facet[:]); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
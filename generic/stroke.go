@@ -0,0 +1,232 @@
+package geom
+
+import "math"
+
+type StrokeJoin int
+
+const (
+	StrokeJoinMiter StrokeJoin = iota
+	StrokeJoinRound
+	StrokeJoinBevel
+	StrokeJoinSquare // cut across at half the width from the vert
+)
+
+type StrokeCap int
+
+const (
+	StrokeCapButt   StrokeCap = iota
+	StrokeCapSquare           // extends past the ends by half the width
+	StrokeCapRound
+)
+
+/* Zero Tolerance uses a hundredth of the Width. MiterLimit is the longest
+ * miter as a multiple of Width, as in SVG, longer miters are bevelled. Zero
+ * MiterLimit uses the SVG default of 4.
+ */
+type StrokeStyle[T Num] struct {
+	Width      T
+	Join       StrokeJoin
+	Cap        StrokeCap
+	MiterLimit T
+	Tolerance  T    // furthest round joins and caps may be from a true arc
+	Closed     bool // joins the last vert to the first instead of adding caps
+}
+
+/* Outline of the polyline drawn with style. Returned as PolyBoolean returns
+ * rings, outer rings clockwise and holes anti-clockwise, so overlapping parts
+ * of the line are filled once.
+ */
+func (poly Poly[T]) Stroke(style StrokeStyle[T]) []Poly[T] {
+	s := newStroker(float64(style.Width)/2, style.Join, float64(style.MiterLimit), float64(style.Tolerance))
+	if s.half > 0 {
+		s.line(PolyConvert[T, float64](poly), style.Closed, style.Cap)
+	}
+
+	rings := []Poly[T]{}
+	for _, ring := range polyUnionAll(s.pieces) {
+		rings = append(rings, PolyConvert[float64, T](ring))
+	}
+	return rings
+}
+
+/* Collects the simple pieces of a stroke, segments, joins and caps, which are
+ * unioned to make the outline.
+ */
+type stroker struct {
+	half       float64
+	join       StrokeJoin
+	miterLimit float64
+	tolerance  float64
+	pieces     [][]Poly[float64]
+}
+
+/* Zero tolerance uses a fiftieth of half the width */
+func newStroker(half float64, join StrokeJoin, miterLimit, tolerance float64) *stroker {
+	if tolerance <= 0 {
+		tolerance = half / 50
+	}
+	if miterLimit <= 0 {
+		miterLimit = 4
+	}
+	return &stroker{half: half, join: join, miterLimit: miterLimit, tolerance: tolerance}
+}
+
+func (s *stroker) line(poly Poly[float64], closed bool, c StrokeCap) {
+	line := make([]Vec2[float64], 0, len(poly))
+	for _, v := range poly {
+		if len(line) == 0 || v != line[len(line)-1] {
+			line = append(line, v)
+		}
+	}
+	if closed && len(line) > 1 && line[0] == line[len(line)-1] {
+		line = line[:len(line)-1]
+	}
+
+	switch {
+	case len(line) == 0:
+	case len(line) == 1:
+		s.dot(line[0], c)
+	case closed && len(line) > 2:
+		for i := range line {
+			s.segment(line[i], line[(i+1)%len(line)])
+			s.joint(line[(i+len(line)-1)%len(line)], line[i], line[(i+1)%len(line)])
+		}
+	default:
+		for i := 1; i < len(line); i++ {
+			s.segment(line[i-1], line[i])
+			if i+1 < len(line) {
+				s.joint(line[i-1], line[i], line[i+1])
+			}
+		}
+		s.cap(line[1], line[0], c)
+		s.cap(line[len(line)-2], line[len(line)-1], c)
+	}
+}
+
+func (s *stroker) add(piece Poly[float64]) {
+	if math.Abs(piece.Area()) > 1e-12*s.half*s.half {
+		s.pieces = append(s.pieces, []Poly[float64]{piece})
+	}
+}
+
+/* offset of the left side of a to b */
+func (s *stroker) side(a, b Vec2[float64]) Vec2[float64] {
+	return b.Minus(a).Normal().Perpendicular().ScaledBy(s.half)
+}
+
+func (s *stroker) segment(a, b Vec2[float64]) {
+	n := s.side(a, b)
+	s.add(Poly[float64]{a.Plus(n), b.Plus(n), b.Minus(n), a.Minus(n)})
+}
+
+/* fills the gap on the outside of the turn at b */
+func (s *stroker) joint(a, b, c Vec2[float64]) {
+	d0, d1 := b.Minus(a).Normal(), c.Minus(b).Normal()
+	cross, dot := d0.Cross(d1), d0.Dot(d1)
+	if math.Abs(cross) < 1e-12 && dot > 0 {
+		return // straight on
+	}
+
+	// the outside of the turn is opposite the direction of turning
+	n0, n1 := s.side(a, b), s.side(b, c)
+	if cross > 0 {
+		n0, n1 = n0.ScaledBy(-1), n1.ScaledBy(-1)
+	}
+	p0, p1 := b.Plus(n0), b.Plus(n1)
+
+	switch s.join {
+	case StrokeJoinRound:
+		piece := Poly[float64]{b}
+		s.add(append(piece, arcPoints(b, n0, n1, cross > 0, s.tolerance)...))
+		return
+
+	case StrokeJoinMiter:
+		// ratio of miter length to width is 1/cos(turn/2)
+		cosHalf := math.Sqrt(math.Max(0, (1+dot)/2))
+		if cosHalf > 0 && 1/cosHalf <= s.miterLimit {
+			tip := b.Plus(n0.Plus(n1).Normal().ScaledBy(s.half / cosHalf))
+			s.add(Poly[float64]{b, p0, tip, p1})
+			return
+		}
+
+	case StrokeJoinSquare:
+		// each side extends by half*tan(turn/4) to meet the cut
+		turn := math.Atan2(math.Abs(cross), dot)
+		extend := s.half * math.Tan(turn/4)
+		s.add(Poly[float64]{b, p0, p0.Plus(d0.ScaledBy(extend)), p1.Minus(d1.ScaledBy(extend)), p1})
+		return
+	}
+
+	s.add(Poly[float64]{b, p0, p1})
+}
+
+/* cap at b for the segment from a */
+func (s *stroker) cap(a, b Vec2[float64], c StrokeCap) {
+	n := s.side(a, b)
+	d := b.Minus(a).Normal().ScaledBy(s.half)
+
+	switch c {
+	case StrokeCapSquare:
+		s.add(Poly[float64]{b.Plus(n), b.Plus(n).Plus(d), b.Minus(n).Plus(d), b.Minus(n)})
+	case StrokeCapRound:
+		s.add(append(Poly[float64]{b}, arcPoints(b, n, n.ScaledBy(-1), false, s.tolerance)...))
+	}
+}
+
+/* a line of a single point */
+func (s *stroker) dot(v Vec2[float64], c StrokeCap) {
+	switch c {
+	case StrokeCapSquare:
+		verts := RectCentredAt(2*s.half, 2*s.half, v).Verts()
+		s.add(verts[:])
+	case StrokeCapRound:
+		right := Vec2[float64]{s.half, 0}
+		circle := arcPoints(v, right, right.ScaledBy(-1), true, s.tolerance)
+		circle = append(circle, arcPoints(v, right.ScaledBy(-1), right, true, s.tolerance)[1:]...)
+		s.add(circle[:len(circle)-1])
+	}
+}
+
+/* Points on the arc about centre from centre+from to centre+to, turning with
+ * positive angles when clockwise, within tolerance of the true arc. A half
+ * turn is taken when from and to are opposite.
+ */
+func arcPoints(centre, from, to Vec2[float64], clockwise bool, tolerance float64) []Vec2[float64] {
+	radius := from.Len()
+	sweep := math.Atan2(from.Cross(to), from.Dot(to))
+	switch {
+	case clockwise && sweep < 0:
+		sweep += 2 * math.Pi
+	case !clockwise && sweep > 0:
+		sweep -= 2 * math.Pi
+	}
+
+	step := math.Pi / 2
+	if tolerance < radius {
+		step = math.Min(step, 2*math.Acos(1-tolerance/radius))
+	}
+	n := int(math.Ceil(math.Abs(sweep) / step))
+	if n < 1 {
+		n = 1
+	}
+
+	points := make([]Vec2[float64], 0, n+1)
+	points = append(points, centre.Plus(from))
+	for i := 1; i < n; i++ {
+		points = append(points, centre.Plus(from.RotatedBy(sweep*float64(i)/float64(n))))
+	}
+	return append(points, centre.Plus(to))
+}
+
+/* union of many regions, merged in pairs to keep each overlay small */
+func polyUnionAll[T Num](regions [][]Poly[T]) []Poly[T] {
+	switch len(regions) {
+	case 0:
+		return []Poly[T]{}
+	case 1:
+		return PolyUnion(regions[0], nil)
+	}
+
+	mid := len(regions) / 2
+	return PolyUnion(polyUnionAll(regions[:mid]), polyUnionAll(regions[mid:]))
+}
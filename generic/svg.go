@@ -0,0 +1,295 @@
+package geom
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+/* Style of a shape drawn by Svg. Sizes are in pixels of the image so they
+ * don't depend on the scale of the geometry. An empty Stroke is black and an
+ * empty Fill is none, except for points which are filled with the stroke, or
+ * black when the stroke is "none". Use "none" to leave out the stroke.
+ */
+type SvgStyle struct {
+	Stroke      string // SVG colour such as "red" or "#ff0000"
+	Fill        string
+	StrokeWidth float64 // default 1
+	PointRadius float64 // default 3
+	FontSize    float64 // of the label, default 12
+	Label       string  // drawn at the centre of filled shapes, otherwise at the first point
+}
+
+/* Collects shapes and writes them as an SVG image whose viewBox fits their
+ * bounds, so coordinates in the file are the coordinates of the shapes.
+ */
+type Svg[T Num] struct {
+	Size   float64 // largest side of the image in pixels, default 512
+	Margin float64 // around the shapes in pixels, default 16
+	YUp    bool    // flip so y increases up the image, otherwise down like the library
+
+	items  []svgItem[T]
+	bounds Rect[T]
+}
+
+type svgKind int
+
+const (
+	svgPolygon svgKind = iota
+	svgPolyline
+	svgPoints
+	svgRegion
+	svgBeziers
+)
+
+type svgItem[T Num] struct {
+	kind    svgKind
+	points  []Vec2[T]
+	rings   [][]Vec2[T] // regions and curves
+	style   SvgStyle
+	labelAt Vec2[T]
+}
+
+func (s *Svg[T]) add(item svgItem[T], bounds Rect[T]) {
+	if len(s.items) == 0 {
+		s.bounds = bounds
+	} else {
+		s.bounds = s.bounds.Union(bounds)
+	}
+	s.items = append(s.items, item)
+}
+
+func (s *Svg[T]) Poly(poly Poly[T], style SvgStyle) {
+	if len(poly) == 0 {
+		return
+	}
+	s.add(svgItem[T]{kind: svgPolygon, points: PolyCopy(poly), style: style, labelAt: poly.Bounds().Centre()}, poly.Bounds())
+}
+
+/* Rings filled even-odd like the results of PolyBoolean */
+func (s *Svg[T]) Region(region []Poly[T], style SvgStyle) {
+	item := svgItem[T]{kind: svgRegion, style: style}
+	bounds := Rect[T]{}
+	for _, ring := range region {
+		if len(ring) == 0 {
+			continue
+		}
+		if len(item.rings) == 0 {
+			bounds = ring.Bounds()
+		}
+		bounds = bounds.Union(ring.Bounds())
+		item.rings = append(item.rings, PolyCopy(ring))
+	}
+	if len(item.rings) > 0 {
+		item.labelAt = bounds.Centre()
+		s.add(item, bounds)
+	}
+}
+
+/* The zero Rect is skipped like empty polys */
+func (s *Svg[T]) Rect(r Rect[T], style SvgStyle) {
+	if r == (Rect[T]{}) {
+		return
+	}
+	verts := r.Verts()
+	s.add(svgItem[T]{kind: svgPolygon, points: verts[:], style: style, labelAt: r.Centre()}, r)
+}
+
+func (s *Svg[T]) Segment(seg Segment2[T], style SvgStyle) {
+	s.Polyline([]Vec2[T]{seg.A, seg.B}, style)
+}
+
+func (s *Svg[T]) Polyline(line []Vec2[T], style SvgStyle) {
+	if len(line) == 0 {
+		return
+	}
+	points := append([]Vec2[T]{}, line...)
+	s.add(svgItem[T]{kind: svgPolyline, points: points, style: style, labelAt: line[0]}, RectBounds(line))
+}
+
+/* Circles at each point, the label is drawn beside the first */
+func (s *Svg[T]) Points(points []Vec2[T], style SvgStyle) {
+	if len(points) == 0 {
+		return
+	}
+	copied := append([]Vec2[T]{}, points...)
+	s.add(svgItem[T]{kind: svgPoints, points: copied, style: style, labelAt: points[0]}, RectBounds(points))
+}
+
+/* Curves joined into one path, such as the Beziers of a spline. Curves up to
+ * cubic are drawn exactly, higher degrees are flattened to half a pixel.
+ */
+func (s *Svg[T]) Beziers(curves []Bezier2[T], style SvgStyle) {
+	item := svgItem[T]{kind: svgBeziers, style: style}
+	bounds := Rect[T]{}
+	for _, b := range curves {
+		if len(b) == 0 {
+			continue
+		}
+		if len(item.rings) == 0 {
+			bounds = b.Bounds()
+			item.labelAt = b[0]
+		}
+		bounds = bounds.Union(b.Bounds())
+		item.rings = append(item.rings, append([]Vec2[T]{}, b...))
+	}
+	if len(item.rings) > 0 {
+		s.add(item, bounds)
+	}
+}
+
+func WriteSvg[T Num](w io.Writer, s Svg[T]) error {
+	size, margin := s.Size, s.Margin
+	if size <= 0 {
+		size = 512
+	}
+	if margin <= 0 {
+		margin = 16
+	}
+
+	// pixels per unit, shapes with no area are given a size of 1
+	b := RectConvert[T, float64](s.bounds)
+	extent := math.Max(b.Width(), b.Height())
+	if extent == 0 {
+		extent = 1
+		b = RectCentredAt(1, 1, b.Centre())
+	}
+	scale := math.Max(size-2*margin, 1) / extent
+	view := b.Outset(margin / scale)
+	flip := view.Min.Y + view.Max.Y // y to flip about the centre of the view
+
+	num := func(f float64) string {
+		return strconv.FormatFloat(f, 'g', 6, 64)
+	}
+	vec := func(v Vec2[T]) string {
+		return formatNum(v.X) + "," + formatNum(v.Y)
+	}
+	list := func(points []Vec2[T]) string {
+		parts := make([]string, len(points))
+		for i, v := range points {
+			parts[i] = vec(v)
+		}
+		return strings.Join(parts, " ")
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="%s %s %s %s">`+"\n",
+		num(view.Width()*scale), num(view.Height()*scale), num(view.Min.X), num(view.Min.Y), num(view.Width()), num(view.Height()))
+	if s.YUp {
+		fmt.Fprintf(bw, `<g transform="matrix(1 0 0 -1 0 %s)">`+"\n", num(flip))
+	}
+
+	for _, item := range s.items {
+		style := item.style
+		stroke, fill := style.Stroke, style.Fill
+		if stroke == "" {
+			stroke = "black"
+		}
+		if fill == "" {
+			fill = "none"
+		}
+		width := style.StrokeWidth
+		if width <= 0 {
+			width = 1
+		}
+		attrs := fmt.Sprintf(`stroke="%s" stroke-width="%s" fill="%s"`,
+			html.EscapeString(stroke), num(width/scale), html.EscapeString(fill))
+
+		switch item.kind {
+		case svgPolygon:
+			fmt.Fprintf(bw, `<polygon points="%s" %s/>`+"\n", list(item.points), attrs)
+
+		case svgPolyline:
+			fmt.Fprintf(bw, `<polyline points="%s" %s stroke-linejoin="round" stroke-linecap="round"/>`+"\n", list(item.points), attrs)
+
+		case svgPoints:
+			radius := style.PointRadius
+			if radius <= 0 {
+				radius = 3
+			}
+			if style.Fill == "" {
+				fill = stroke
+				if fill == "none" {
+					fill = "black"
+				}
+			}
+			for _, v := range item.points {
+				fmt.Fprintf(bw, `<circle cx="%s" cy="%s" r="%s" stroke="none" fill="%s"/>`+"\n",
+					formatNum(v.X), formatNum(v.Y), num(radius/scale), html.EscapeString(fill))
+			}
+
+		case svgRegion:
+			d := []string{}
+			for _, ring := range item.rings {
+				d = append(d, "M"+list(ring)+"Z")
+			}
+			fmt.Fprintf(bw, `<path d="%s" fill-rule="evenodd" %s/>`+"\n", strings.Join(d, " "), attrs)
+
+		case svgBeziers:
+			d := []string{}
+			for i, curve := range item.rings {
+				if i == 0 || curve[0] != item.rings[i-1][len(item.rings[i-1])-1] {
+					d = append(d, "M"+vec(curve[0]))
+				}
+				switch len(curve) {
+				case 1:
+				case 2:
+					d = append(d, "L"+vec(curve[1]))
+				case 3:
+					d = append(d, "Q"+list(curve[1:]))
+				case 4:
+					d = append(d, "C"+list(curve[1:]))
+				default:
+					flat := Bezier2[T](curve).Flatten(T(0.5 / scale))
+					d = append(d, "L"+list(flat[1:]))
+				}
+			}
+			fmt.Fprintf(bw, `<path d="%s" %s stroke-linejoin="round" stroke-linecap="round"/>`+"\n", strings.Join(d, " "), attrs)
+		}
+	}
+
+	if s.YUp {
+		bw.WriteString("</g>\n")
+	}
+
+	// labels are drawn last on top and never flipped
+	for _, item := range s.items {
+		style := item.style
+		if style.Label == "" {
+			continue
+		}
+		fontSize := style.FontSize
+		if fontSize <= 0 {
+			fontSize = 12
+		}
+		colour := style.Stroke
+		if colour == "" || colour == "none" {
+			colour = "black"
+		}
+
+		at := Vec2Convert[T, float64](item.labelAt)
+		if s.YUp {
+			at.Y = flip - at.Y
+		}
+		anchor := "middle"
+		if item.kind != svgPolygon && item.kind != svgRegion {
+			offset := 4.0 // pixels right of the first point
+			if item.kind == svgPoints && style.PointRadius > 0 {
+				offset = style.PointRadius + 2
+			} else if item.kind == svgPoints {
+				offset = 5
+			}
+			anchor = "start"
+			at.X += offset / scale
+		}
+		fmt.Fprintf(bw, `<text x="%s" y="%s" font-size="%s" font-family="sans-serif" fill="%s" text-anchor="%s" dominant-baseline="central">%s</text>`+"\n",
+			num(at.X), num(at.Y), num(fontSize/scale), html.EscapeString(colour), anchor, html.EscapeString(style.Label))
+	}
+
+	bw.WriteString("</svg>\n")
+	return bw.Flush()
+}
@@ -0,0 +1,448 @@
+package geom
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+/* Part of an SVG path started by a moveto. Closed subpaths ended with Z and
+ * don't repeat their first point.
+ */
+type SvgSubpath[T Num] struct {
+	Poly   Poly[T]
+	Closed bool
+}
+
+/* Parses SVG path data, applying transform and flattening curves and arcs to
+ * within tolerance of the transformed path. Subpaths of a single point are
+ * left out. Errors are *ParseError.
+ */
+func ParseSvgPath[T Num](d string, transform Mat3[T], tolerance T) ([]SvgSubpath[T], error) {
+	p := svgPathBuilder[T]{tolerance: float64(tolerance)}
+	for i, v := range transform {
+		p.transform[i] = float64(v)
+	}
+
+	s := svgScanner{text: d}
+	var cur, start, control Vec2[float64] // control is the last control point of a curve
+	command, previous := byte(0), byte(0)
+
+	for {
+		if s.skip(); s.done() {
+			break
+		}
+		if c := s.text[s.i]; isSvgLetter(c) {
+			command = c
+			s.i++
+		} else if command == 0 || command == 'Z' || command == 'z' {
+			return nil, s.errorf("expected a command")
+		}
+		if previous == 0 && command != 'M' && command != 'm' {
+			return nil, s.errorf("path must start with a moveto")
+		}
+
+		upper, relative := command&^0x20, command >= 'a'
+		origin := Vec2[float64]{}
+		if relative {
+			origin = cur
+		}
+		point := func() (Vec2[float64], error) {
+			x, err := s.number()
+			if err != nil {
+				return Vec2[float64]{}, err
+			}
+			y, err := s.number()
+			return origin.Plus(Vec2[float64]{x, y}), err
+		}
+
+		// the reflected control point is only used after a curve of the same kind
+		reflected := cur
+		if (upper == 'S' && (previous == 'C' || previous == 'S')) || (upper == 'T' && (previous == 'Q' || previous == 'T')) {
+			reflected = cur.ScaledBy(2).Minus(control)
+		}
+
+		var err error
+		var points [3]Vec2[float64]
+		switch upper {
+		case 'M':
+			if points[0], err = point(); err != nil {
+				return nil, err
+			}
+			p.moveTo(points[0])
+			cur, start = points[0], points[0]
+			command = 'L' | command&0x20 // further pairs are lines
+
+		case 'Z':
+			p.close()
+			cur = start
+
+		case 'L':
+			if points[0], err = point(); err != nil {
+				return nil, err
+			}
+			p.curve(cur, points[0])
+			cur = points[0]
+
+		case 'H', 'V':
+			n, err := s.number()
+			if err != nil {
+				return nil, err
+			}
+			end := cur
+			if upper == 'H' {
+				end.X = n + origin.X
+			} else {
+				end.Y = n + origin.Y
+			}
+			p.curve(cur, end)
+			cur = end
+
+		case 'C', 'S', 'Q', 'T':
+			n := 2
+			switch upper {
+			case 'C':
+				n = 3
+			case 'T':
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				if points[i], err = point(); err != nil {
+					return nil, err
+				}
+			}
+			switch upper {
+			case 'C':
+				p.curve(cur, points[0], points[1], points[2])
+				control, cur = points[1], points[2]
+			case 'S':
+				p.curve(cur, reflected, points[0], points[1])
+				control, cur = points[0], points[1]
+			case 'Q':
+				p.curve(cur, points[0], points[1])
+				control, cur = points[0], points[1]
+			case 'T':
+				p.curve(cur, reflected, points[0])
+				control, cur = reflected, points[0]
+			}
+
+		case 'A':
+			var args [3]float64
+			for i := range args {
+				if args[i], err = s.number(); err != nil {
+					return nil, err
+				}
+			}
+			large, err := s.flag()
+			if err != nil {
+				return nil, err
+			}
+			sweep, err := s.flag()
+			if err != nil {
+				return nil, err
+			}
+			if points[0], err = point(); err != nil {
+				return nil, err
+			}
+			p.arc(cur, points[0], args[0], args[1], args[2], large, sweep)
+			cur = points[0]
+
+		default:
+			return nil, s.errorf("unknown command %q", command)
+		}
+		previous = upper
+	}
+
+	p.finish(false)
+	return p.subpaths, nil
+}
+
+/* Parses an SVG transform attribute such as "translate(10 20) rotate(45)".
+ * Angles are in degrees. Errors are *ParseError.
+ */
+func ParseSvgTransform[T Num](text string) (Mat3[T], error) {
+	s := svgScanner{text: text}
+	m := Mat3Identity[float64]()
+	counts := map[string][]int{
+		"matrix": {6}, "translate": {1, 2}, "scale": {1, 2}, "rotate": {1, 3}, "skewX": {1}, "skewY": {1},
+	}
+
+	for {
+		if s.skip(); s.done() {
+			break
+		}
+		begin := s.i
+		for !s.done() && isSvgLetter(s.text[s.i]) {
+			s.i++
+		}
+		name := s.text[begin:s.i]
+		if _, ok := counts[name]; !ok {
+			return Mat3[T]{}, s.errorf("unknown transform %q", name)
+		}
+
+		if s.skip(); s.done() || s.text[s.i] != '(' {
+			return Mat3[T]{}, s.errorf("expected (")
+		}
+		s.i++
+		args := []float64{}
+		for s.skip(); !s.done() && s.text[s.i] != ')'; s.skip() {
+			n, err := s.number()
+			if err != nil {
+				return Mat3[T]{}, err
+			}
+			args = append(args, n)
+		}
+		if s.done() {
+			return Mat3[T]{}, s.errorf("expected )")
+		}
+		s.i++
+
+		valid := false
+		for _, n := range counts[name] {
+			valid = valid || len(args) == n
+		}
+		if !valid {
+			return Mat3[T]{}, s.errorf("%s has %d arguments", name, len(args))
+		}
+
+		t := Mat3Identity[float64]()
+		radians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+		switch name {
+		case "matrix":
+			t = Mat3[float64]{args[0], args[2], args[4], args[1], args[3], args[5], 0, 0, 1}
+		case "translate":
+			args = append(args, 0)
+			t = Mat3Translation(Vec2[float64]{args[0], args[1]})
+		case "scale":
+			args = append(args, args[0])
+			t = Mat3Scalar(args[0], args[1])
+		case "rotate":
+			t = Mat3Rotation(radians(args[0]))
+			if len(args) == 3 {
+				about := Vec2[float64]{args[1], args[2]}
+				t = Mat3Translation(about).Product(t).Product(Mat3Translation(about.ScaledBy(-1)))
+			}
+		case "skewX":
+			t[1] = math.Tan(radians(args[0]))
+		case "skewY":
+			t[3] = math.Tan(radians(args[0]))
+		}
+		m = m.Product(t)
+	}
+
+	result := Mat3[T]{}
+	for i, v := range m {
+		result[i] = T(v)
+	}
+	return result, nil
+}
+
+type svgPathBuilder[T Num] struct {
+	transform Mat3[float64]
+	tolerance float64
+	subpaths  []SvgSubpath[T]
+	current   []Vec2[float64] // transformed points of the open subpath
+	start     Vec2[float64]
+}
+
+func (p *svgPathBuilder[T]) moveTo(v Vec2[float64]) {
+	p.finish(false)
+	p.start = v
+	p.current = []Vec2[float64]{p.transform.TimesVec2(v, 1)}
+}
+
+/* Bézier curve from the current point, a line with two points */
+func (p *svgPathBuilder[T]) curve(points ...Vec2[float64]) {
+	if p.current == nil { // drawing after Z starts at the same point
+		p.current = []Vec2[float64]{p.transform.TimesVec2(p.start, 1)}
+	}
+
+	// affine transforms keep curves as Béziers of their transformed points
+	b := make(Bezier2[float64], len(points))
+	for i, v := range points {
+		b[i] = p.transform.TimesVec2(v, 1)
+	}
+	if len(b) == 2 {
+		p.current = append(p.current, b[1])
+		return
+	}
+	p.current = append(p.current, b.Flatten(p.tolerance)[1:]...)
+}
+
+/* Elliptical arc by the SVG endpoint parameterisation, drawn as cubic Béziers */
+func (p *svgPathBuilder[T]) arc(from, to Vec2[float64], rx, ry, degrees float64, large, sweep bool) {
+	rx, ry = math.Abs(rx), math.Abs(ry)
+	if from == to {
+		return
+	}
+	if rx == 0 || ry == 0 {
+		p.curve(from, to)
+		return
+	}
+
+	// centre from the SVG implementation notes
+	phi := degrees * math.Pi / 180
+	cos, sin := math.Cos(phi), math.Sin(phi)
+	half := from.Minus(to).ScaledBy(0.5)
+	x1 := Vec2[float64]{cos*half.X + sin*half.Y, -sin*half.X + cos*half.Y}
+
+	if lambda := x1.X*x1.X/(rx*rx) + x1.Y*x1.Y/(ry*ry); lambda > 1 {
+		rx, ry = rx*math.Sqrt(lambda), ry*math.Sqrt(lambda)
+	}
+	num := rx*rx*ry*ry - rx*rx*x1.Y*x1.Y - ry*ry*x1.X*x1.X
+	den := rx*rx*x1.Y*x1.Y + ry*ry*x1.X*x1.X
+	coef := math.Sqrt(math.Max(0, num/den))
+	if large == sweep {
+		coef = -coef
+	}
+	c1 := Vec2[float64]{coef * rx * x1.Y / ry, -coef * ry * x1.X / rx}
+	mid := from.Plus(to).ScaledBy(0.5)
+	centre := Vec2[float64]{cos*c1.X - sin*c1.Y + mid.X, sin*c1.X + cos*c1.Y + mid.Y}
+
+	u := Vec2[float64]{(x1.X - c1.X) / rx, (x1.Y - c1.Y) / ry}
+	v := Vec2[float64]{(-x1.X - c1.X) / rx, (-x1.Y - c1.Y) / ry}
+	theta := math.Atan2(u.Y, u.X)
+	delta := math.Atan2(u.Cross(v), u.Dot(v))
+	switch {
+	case sweep && delta < 0:
+		delta += 2 * math.Pi
+	case !sweep && delta > 0:
+		delta -= 2 * math.Pi
+	}
+
+	at := func(t float64) (Vec2[float64], Vec2[float64]) {
+		x, y := rx*math.Cos(t), ry*math.Sin(t)
+		dx, dy := -rx*math.Sin(t), ry*math.Cos(t)
+		return Vec2[float64]{centre.X + cos*x - sin*y, centre.Y + sin*x + cos*y},
+			Vec2[float64]{cos*dx - sin*dy, sin*dx + cos*dy}
+	}
+
+	// a quarter turn cubic is within 2.7e-4 of the radius, which falls with the
+	// sixth power of the angle
+	m := p.transform
+	scale := math.Sqrt(m[0]*m[0] + m[1]*m[1] + m[3]*m[3] + m[4]*m[4])
+	radius := math.Max(rx, ry) * scale
+	n := int(math.Ceil(math.Abs(delta) / (math.Pi / 2)))
+	for n < 1024 && radius*2.7e-4*math.Pow(math.Abs(delta)/float64(n)/(math.Pi/2), 6) > p.tolerance/2 {
+		n++
+	}
+
+	step := delta / float64(n)
+	k := 4.0 / 3 * math.Tan(step/4)
+	_, startDir := at(theta)
+	start := from
+	for i := 1; i <= n; i++ {
+		end, endDir := at(theta + step*float64(i))
+		if i == n {
+			end = to
+		}
+		p.curve(start, start.Plus(startDir.ScaledBy(k)), end.Minus(endDir.ScaledBy(k)), end)
+		start, startDir = end, endDir
+	}
+}
+
+func (p *svgPathBuilder[T]) close() {
+	p.finish(true)
+}
+
+func (p *svgPathBuilder[T]) finish(closed bool) {
+	points := p.current
+	p.current = nil
+	if closed && len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	if len(points) < 2 {
+		return
+	}
+
+	poly := make(Poly[T], len(points))
+	for i, v := range points {
+		poly[i] = Vec2[T]{T(v.X), T(v.Y)}
+	}
+	p.subpaths = append(p.subpaths, SvgSubpath[T]{poly, closed})
+}
+
+type svgScanner struct {
+	text string
+	i    int
+}
+
+func (s *svgScanner) errorf(format string, args ...any) error {
+	return &ParseError{"svg", 0, fmt.Sprintf("offset %d: %s", s.i, fmt.Sprintf(format, args...))}
+}
+
+func (s *svgScanner) done() bool {
+	return s.i >= len(s.text)
+}
+
+/* Whitespace and commas */
+func (s *svgScanner) skip() {
+	for !s.done() {
+		switch s.text[s.i] {
+		case ' ', '\t', '\n', '\r', '\f', ',':
+			s.i++
+		default:
+			return
+		}
+	}
+}
+
+/* Numbers needn't be separated when the next starts with a sign or a second
+ * decimal point, such as "1-2.5.5" which is 1, -2.5 and .5
+ */
+func (s *svgScanner) number() (float64, error) {
+	s.skip()
+	begin := s.i
+	digits := func() int {
+		n := 0
+		for ; !s.done() && s.text[s.i] >= '0' && s.text[s.i] <= '9'; s.i++ {
+			n++
+		}
+		return n
+	}
+
+	if !s.done() && (s.text[s.i] == '-' || s.text[s.i] == '+') {
+		s.i++
+	}
+	n := digits()
+	if !s.done() && s.text[s.i] == '.' {
+		s.i++
+		n += digits()
+	}
+	if n == 0 {
+		s.i = begin
+		return 0, s.errorf("expected a number")
+	}
+	if !s.done() && (s.text[s.i] == 'e' || s.text[s.i] == 'E') {
+		exponent := s.i
+		s.i++
+		if !s.done() && (s.text[s.i] == '-' || s.text[s.i] == '+') {
+			s.i++
+		}
+		if digits() == 0 {
+			s.i = exponent // an e that isn't an exponent
+		}
+	}
+
+	text := s.text[begin:s.i]
+	f, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		s.i = begin
+		return 0, s.errorf("invalid number %q", text)
+	}
+	return f, nil
+}
+
+/* Arc flags are a single 0 or 1 and needn't be separated */
+func (s *svgScanner) flag() (bool, error) {
+	s.skip()
+	if s.done() || (s.text[s.i] != '0' && s.text[s.i] != '1') {
+		return false, s.errorf("expected a flag")
+	}
+	s.i++
+	return s.text[s.i-1] == '1', nil
+}
+
+func isSvgLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
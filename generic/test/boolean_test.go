@@ -0,0 +1,261 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func square(x, y, size float64) Poly[float64] {
+	return Poly[float64]{{x, y}, {x + size, y}, {x + size, y + size}, {x, y + size}}
+}
+
+func ringsArea(rings []Poly[float64]) float64 {
+	sum := 0.0
+	for _, ring := range rings {
+		sum += ring.Area()
+	}
+	return sum
+}
+
+/* even-odd containment of a set of rings */
+func ringsContain(rings []Poly[float64], v Vec2[float64]) bool {
+	inside := false
+	for _, ring := range rings {
+		if ring.Contains(v) {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func TestPolyBooleanOverlapping(t *testing.T) {
+	a := []Poly[float64]{square(0, 0, 2)}
+	b := []Poly[float64]{square(1, 1, 2)}
+
+	cases := []struct {
+		op    PolyOp
+		area  float64
+		rings int
+	}{
+		{PolyOpUnion, 7, 1},
+		{PolyOpIntersection, 1, 1},
+		{PolyOpDifference, 3, 1},
+		{PolyOpXor, 6, 2},
+	}
+
+	for _, c := range cases {
+		rings := PolyBoolean(a, b, c.op)
+		if len(rings) != c.rings {
+			t.Errorf("op: %v, expected %v rings, got: %v", c.op, c.rings, rings)
+		}
+		if area := ringsArea(rings); !floatIdentical(c.area, area) {
+			t.Errorf("op: %v, expected area: %v, got: %v", c.op, c.area, area)
+		}
+		for _, ring := range rings {
+			if ring.Area() <= 0 {
+				t.Errorf("op: %v, expected clockwise ring, got: %v", c.op, ring)
+			}
+		}
+	}
+
+	expected := Poly[float64]{{1, 1}, {2, 1}, {2, 2}, {1, 2}}
+	actual := PolyIntersection(a, b)
+	if len(actual) != 1 || len(actual[0]) != 4 || !floatIdentical(1, actual[0].Area()) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+	for _, v := range expected {
+		if !actual[0].Contains(v) {
+			t.Errorf("expected vert: %v, got: %v", v, actual)
+		}
+	}
+}
+
+func TestPolyBooleanWinding(t *testing.T) {
+	a := []Poly[float64]{square(0, 0, 2)}
+	b := []Poly[float64]{{{1, 1}, {1, 3}, {3, 3}, {3, 1}}} // anti-clockwise
+
+	if area := ringsArea(PolyUnion(a, b)); !floatIdentical(7, area) {
+		t.Errorf("expected area: 7, got: %v", area)
+	}
+}
+
+func TestPolyBooleanCoincidentEdges(t *testing.T) {
+	a := []Poly[float64]{square(0, 0, 1)}
+	b := []Poly[float64]{square(1, 0, 1)}
+
+	union := PolyUnion(a, b)
+	if len(union) != 1 || len(union[0]) != 4 || !floatIdentical(2, union[0].Area()) {
+		t.Errorf("expected single rectangle, got: %v", union)
+	}
+
+	if rings := PolyIntersection(a, b); len(rings) != 0 {
+		t.Errorf("expected empty intersection, got: %v", rings)
+	}
+
+	// identical inputs
+	if rings := PolyUnion(a, a); len(rings) != 1 || !floatIdentical(1, ringsArea(rings)) {
+		t.Errorf("expected unit square, got: %v", rings)
+	}
+	if rings := PolyXor(a, a); len(rings) != 0 {
+		t.Errorf("expected empty xor, got: %v", rings)
+	}
+	if rings := PolyDifference(a, a); len(rings) != 0 {
+		t.Errorf("expected empty difference, got: %v", rings)
+	}
+
+	// partially overlapping edge
+	c := []Poly[float64]{square(1, 0.5, 1)}
+	union = PolyUnion(a, c)
+	if len(union) != 1 || !floatIdentical(2, ringsArea(union)) || len(union[0]) != 8 {
+		t.Errorf("expected single ring, got: %v", union)
+	}
+}
+
+func TestPolyBooleanTouchingCorner(t *testing.T) {
+	a := []Poly[float64]{square(0, 0, 1)}
+	b := []Poly[float64]{square(1, 1, 1)}
+
+	union := PolyUnion(a, b)
+	if len(union) != 2 || !floatIdentical(2, ringsArea(union)) {
+		t.Errorf("expected two separate rings, got: %v", union)
+	}
+	for _, ring := range union {
+		if len(ring) != 4 {
+			t.Errorf("expected square, got: %v", ring)
+		}
+	}
+}
+
+func TestPolyBooleanHoles(t *testing.T) {
+	frame := []Poly[float64]{square(0, 0, 4), square(1, 1, 2)}
+	if area := ringsArea(PolyUnion(frame, nil)); !floatIdentical(12, area) {
+		t.Errorf("expected area: 12, got: %v", area)
+	}
+
+	// difference punching a hole
+	rings := PolyDifference([]Poly[float64]{square(0, 0, 4)}, []Poly[float64]{square(1, 1, 2)})
+	if len(rings) != 2 || !floatIdentical(12, ringsArea(rings)) {
+		t.Errorf("expected ring and hole, got: %v", rings)
+	}
+	holes := 0
+	for _, ring := range rings {
+		if ring.Area() < 0 {
+			holes++
+		}
+	}
+	if holes != 1 {
+		t.Errorf("expected one anti-clockwise hole, got: %v", rings)
+	}
+
+	// filling the hole
+	if rings := PolyUnion(frame, []Poly[float64]{square(1, 1, 2)}); len(rings) != 1 || !floatIdentical(16, ringsArea(rings)) {
+		t.Errorf("expected filled square, got: %v", rings)
+	}
+
+	// intersection with the hole only covers the frame
+	rings = PolyIntersection(frame, []Poly[float64]{square(0.5, 0.5, 3)})
+	if !floatIdentical(9-4, ringsArea(rings)) {
+		t.Errorf("expected area: 5, got: %v", ringsArea(rings))
+	}
+	if ringsContain(rings, Vec2[float64]{2, 2}) || !ringsContain(rings, Vec2[float64]{0.75, 0.75}) {
+		t.Errorf("unexpected result: %v", rings)
+	}
+}
+
+func TestPolyBooleanConcave(t *testing.T) {
+	// U shape with a bar across the opening
+	u := []Poly[float64]{{{0, 0}, {3, 0}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}}}
+	bar := []Poly[float64]{{{-1, 2}, {4, 2}, {4, 2.5}, {-1, 2.5}}}
+
+	union := PolyUnion(u, bar)
+	if !floatIdentical(7+2.5-1, ringsArea(union)) {
+		t.Errorf("expected area: 8.5, got: %v", ringsArea(union))
+	}
+	if len(union) != 2 { // outer ring and enclosed hole
+		t.Errorf("expected 2 rings, got: %v", union)
+	}
+
+	intersection := PolyIntersection(u, bar)
+	if len(intersection) != 2 || !floatIdentical(1, ringsArea(intersection)) {
+		t.Errorf("expected 2 rings of area 1, got: %v", intersection)
+	}
+
+	difference := PolyDifference(u, bar)
+	if len(difference) != 3 || !floatIdentical(6, ringsArea(difference)) {
+		t.Errorf("expected 3 rings of area 6, got: %v", difference)
+	}
+}
+
+func TestPolyBooleanSelfIntersecting(t *testing.T) {
+	// bow tie, the even-odd fill is two triangles
+	bowtie := []Poly[float64]{{{0, 0}, {2, 2}, {2, 0}, {0, 2}}}
+
+	rings := PolyUnion(bowtie, nil)
+	if len(rings) != 2 || !floatIdentical(2, ringsArea(rings)) {
+		t.Errorf("expected two triangles, got: %v", rings)
+	}
+}
+
+func TestPolyBooleanCircles(t *testing.T) {
+	circle := func(x, y, r float64) Poly[float64] {
+		poly := Poly[float64]{}
+		for i := 0; i < 90; i++ {
+			theta := float64(i) * 2 * math.Pi / 90
+			poly = append(poly, Vec2[float64]{x + r*math.Cos(theta), y + r*math.Sin(theta)})
+		}
+		return poly
+	}
+
+	a := []Poly[float64]{circle(0, 0, 1)}
+	b := []Poly[float64]{circle(1, 0, 1)}
+
+	union := ringsArea(PolyUnion(a, b))
+	intersection := ringsArea(PolyIntersection(a, b))
+	difference := ringsArea(PolyDifference(a, b))
+	xor := ringsArea(PolyXor(a, b))
+
+	if !floatIdentical(union, a[0].Area()+b[0].Area()-intersection) {
+		t.Errorf("union: %v, intersection: %v", union, intersection)
+	}
+	if !floatIdentical(difference, a[0].Area()-intersection) {
+		t.Errorf("difference: %v, intersection: %v", difference, intersection)
+	}
+	if !floatIdentical(xor, union-intersection) {
+		t.Errorf("xor: %v, union: %v, intersection: %v", xor, union, intersection)
+	}
+
+	for _, v := range []Vec2[float64]{{0.5, 0}, {-0.5, 0.2}, {1.5, -0.3}, {3, 3}, {0.5, 0.9}} {
+		inA, inB := a[0].Contains(v), b[0].Contains(v)
+		if ringsContain(PolyXor(a, b), v) != (inA != inB) {
+			t.Errorf("xor containment mismatch at: %v", v)
+		}
+	}
+}
+
+func TestPolyBooleanEmpty(t *testing.T) {
+	a := []Poly[float64]{square(0, 0, 1)}
+
+	if rings := PolyUnion[float64](nil, nil); len(rings) != 0 {
+		t.Errorf("expected empty, got: %v", rings)
+	}
+	if rings := PolyIntersection(a, nil); len(rings) != 0 {
+		t.Errorf("expected empty, got: %v", rings)
+	}
+	if rings := PolyDifference(a, nil); len(rings) != 1 || !floatIdentical(1, ringsArea(rings)) {
+		t.Errorf("expected unit square, got: %v", rings)
+	}
+	if rings := PolyUnion([]Poly[float64]{{{0, 0}, {1, 1}}}, a); len(rings) != 1 {
+		t.Errorf("expected degenerate ring to be ignored, got: %v", rings)
+	}
+}
+
+func TestPolyBooleanFloat32(t *testing.T) {
+	a := []Poly[float32]{{{0, 0}, {2, 0}, {2, 2}, {0, 2}}}
+	b := []Poly[float32]{{{1, 1}, {3, 1}, {3, 3}, {1, 3}}}
+
+	rings := PolyUnion(a, b)
+	if len(rings) != 1 || math.Abs(float64(rings[0].Area())-7) > 1e-5 {
+		t.Errorf("expected area: 7, got: %v", rings)
+	}
+}
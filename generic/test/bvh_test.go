@@ -0,0 +1,183 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randCuboid(r *rand.Rand, extent, maxSize float64) Cuboid[float64] {
+	min := Vec3[float64]{
+		(r.Float64()*2 - 1) * extent,
+		(r.Float64()*2 - 1) * extent,
+		(r.Float64()*2 - 1) * extent,
+	}
+	size := Vec3[float64]{r.Float64() * maxSize, r.Float64() * maxSize, r.Float64() * maxSize}
+	return Cuboid[float64]{min, min.Plus(size)}
+}
+
+func randCuboids(r *rand.Rand, n int, extent, maxSize float64) []Cuboid[float64] {
+	boxes := make([]Cuboid[float64], n)
+	for i := range boxes {
+		boxes[i] = randCuboid(r, extent, maxSize)
+	}
+	return boxes
+}
+
+func bvhQuery(b *BVH[float64], box Cuboid[float64]) []int {
+	ids := []int{}
+	b.Query(box, func(i int) bool {
+		ids = append(ids, i)
+		return true
+	})
+	sort.Ints(ids)
+	return ids
+}
+
+func checkBVH(t *testing.T, r *rand.Rand, b *BVH[float64], boxes []Cuboid[float64]) {
+	bounds := boxes[0]
+	for _, box := range boxes {
+		bounds = bounds.Union(box)
+	}
+	if !cuboidIdentical(bounds, b.Bounds()) {
+		t.Errorf("expected bounds: %v, got: %v", bounds, b.Bounds())
+	}
+
+	for k := 0; k < 50; k++ {
+		query := randCuboid(r, 60, 30)
+		expected := []int{}
+		for i, box := range boxes {
+			if box.Intersects(query) {
+				expected = append(expected, i)
+			}
+		}
+		if actual := bvhQuery(b, query); !intsEqual(expected, actual) {
+			t.Fatalf("query: %v, expected: %v, got: %v", query, expected, actual)
+		}
+	}
+
+	for k := 0; k < 200; k++ {
+		ray := Ray3[float64]{
+			Vec3[float64]{(r.Float64()*2 - 1) * 80, (r.Float64()*2 - 1) * 80, (r.Float64()*2 - 1) * 80},
+			Vec3[float64]{r.Float64()*2 - 1, r.Float64()*2 - 1, r.Float64()*2 - 1},
+		}
+
+		expected, expectedDist := -1, 0.0
+		for i, box := range boxes {
+			if hit, _, ok := ray.IntersectCuboid(box); ok && (expected < 0 || hit.Dist < expectedDist) {
+				expected, expectedDist = i, hit.Dist
+			}
+		}
+
+		calls := 0
+		actual, dist, ok := b.Raycast(ray, func(i int) (float64, bool) {
+			calls++
+			hit, _, ok := ray.IntersectCuboid(boxes[i])
+			return hit.Dist, ok
+		})
+
+		if ok != (expected >= 0) || (ok && !floatIdentical(expectedDist, dist)) {
+			t.Fatalf("ray: %v, expected: %v %v, got: %v %v %v", ray, expected, expectedDist, actual, dist, ok)
+		}
+		if calls > len(boxes)/2 {
+			t.Errorf("ray: %v, expected traversal to skip boxes, called %v times", ray, calls)
+		}
+	}
+}
+
+func TestBVH(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	boxes := randCuboids(r, 1000, 50, 5)
+	b := NewBVH(boxes)
+	if b.Len() != len(boxes) {
+		t.Errorf("expected: %v, got: %v", len(boxes), b.Len())
+	}
+	checkBVH(t, r, b, boxes)
+
+	// refit after every box moves
+	for i := range boxes {
+		offset := Vec3[float64]{r.Float64()*4 - 2, r.Float64()*4 - 2, r.Float64()*4 - 2}
+		boxes[i] = Cuboid[float64]{boxes[i].Min.Plus(offset), boxes[i].Max.Plus(offset)}
+	}
+	b.Refit(boxes)
+	checkBVH(t, r, b, boxes)
+}
+
+func TestBVHDegenerate(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	same := make([]Cuboid[float64], 100)
+	for i := range same {
+		same[i] = CuboidCentred[float64](2, 2, 2)
+	}
+	b := NewBVH(same)
+	if actual := bvhQuery(b, CuboidCentred[float64](1, 1, 1)); len(actual) != len(same) {
+		t.Errorf("expected: %v, got: %v", len(same), len(actual))
+	}
+
+	points := make([]Cuboid[float64], 100)
+	for i := range points {
+		v := Vec3[float64]{r.Float64(), 0, 0}
+		points[i] = Cuboid[float64]{v, v}
+	}
+	line := NewBVH(points)
+	if actual := bvhQuery(line, CuboidOrigin[float64](1, 1, 1)); len(actual) != len(points) {
+		t.Errorf("expected: %v, got: %v", len(points), len(actual))
+	}
+
+	empty := NewBVH[float64](nil)
+	empty.Query(CuboidCentred[float64](1, 1, 1), func(int) bool {
+		t.Errorf("unexpected result")
+		return true
+	})
+	if _, _, ok := empty.Raycast(Ray3[float64]{Dir: Vec3[float64]{1, 0, 0}}, nil); ok {
+		t.Errorf("unexpected hit")
+	}
+}
+
+func TestBVHOverlaps(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	boxesA := randCuboids(r, 500, 50, 6)
+	boxesB := randCuboids(r, 300, 50, 6)
+	a, b := NewBVH(boxesA), NewBVH(boxesB)
+
+	expected := [][2]int{}
+	for i := range boxesA {
+		for j := range boxesB {
+			if boxesA[i].Intersects(boxesB[j]) {
+				expected = append(expected, [2]int{i, j})
+			}
+		}
+	}
+
+	actual := [][2]int{}
+	a.Overlaps(b, func(i, j int) bool {
+		actual = append(actual, [2]int{i, j})
+		return true
+	})
+	sort.Slice(actual, func(i, j int) bool {
+		if actual[i][0] != actual[j][0] {
+			return actual[i][0] < actual[j][0]
+		}
+		return actual[i][1] < actual[j][1]
+	})
+
+	if len(expected) == 0 || len(expected) != len(actual) {
+		t.Fatalf("expected: %v pairs, got: %v", len(expected), len(actual))
+	}
+	for k := range expected {
+		if expected[k] != actual[k] {
+			t.Fatalf("expected: %v, got: %v", expected[k], actual[k])
+		}
+	}
+
+	count := 0
+	a.Overlaps(b, func(i, j int) bool {
+		count++
+		return false
+	})
+	if count != 1 {
+		t.Errorf("expected early stop, got: %v", count)
+	}
+}
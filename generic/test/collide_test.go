@@ -0,0 +1,316 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func manifoldIdentical(a, b Manifold2[float64]) bool {
+	if !vec2Identical(a.Normal, b.Normal) || !floatIdentical(a.Depth, b.Depth) || a.NumPoints != b.NumPoints {
+		return false
+	}
+	for i := 0; i < a.NumPoints; i++ { // either order
+		if !vec2Identical(a.Points[i], b.Points[i]) && !vec2Identical(a.Points[i], b.Points[a.NumPoints-1-i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func randConvex(r *rand.Rand, centre Vec2[float64], size float64) Poly[float64] {
+	points := make([]Vec2[float64], 3+r.Intn(10))
+	for i := range points {
+		points[i] = centre.Plus(Vec2[float64]{r.Float64() - 0.5, r.Float64() - 0.5}.ScaledBy(size))
+	}
+	hull := PolyConvexHull(points)
+	if r.Intn(2) == 0 { // either winding
+		for i, j := 0, len(hull)-1; i < j; i, j = i+1, j-1 {
+			hull[i], hull[j] = hull[j], hull[i]
+		}
+	}
+	return hull
+}
+
+/* brute force minimum overlap over the edge normals of both polys */
+func satDepth(a, b Poly[float64]) (Vec2[float64], float64) {
+	bestDepth := math.Inf(1)
+	var best Vec2[float64]
+	for _, p := range []Poly[float64]{a, b} {
+		for i := range p {
+			n := p[(i+1)%len(p)].Minus(p[i]).Perpendicular().Normal()
+			for _, axis := range []Vec2[float64]{n, n.ScaledBy(-1)} {
+				maxA, minB := math.Inf(-1), math.Inf(1)
+				for _, v := range a {
+					maxA = math.Max(maxA, v.Dot(axis))
+				}
+				for _, v := range b {
+					minB = math.Min(minB, v.Dot(axis))
+				}
+				if d := maxA - minB; d < bestDepth {
+					best, bestDepth = axis, d
+				}
+			}
+		}
+	}
+	return best, bestDepth
+}
+
+func TestCollidePolys(t *testing.T) {
+	tri := Poly[float64]{{0, 0}, {2, 0}, {1, 2}}
+
+	cases := []struct {
+		a, b   Poly[float64]
+		result Manifold2[float64]
+		ok     bool
+	}{
+		{
+			square(0, 0, 2), square(1.5, 0.5, 2),
+			Manifold2[float64]{Vec2[float64]{1, 0}, 0.5, [2]Vec2[float64]{{1.75, 0.5}, {1.75, 2}}, 2}, true,
+		},
+		{
+			square(0, 0, 2), square(0.5, 1.8, 1),
+			Manifold2[float64]{Vec2[float64]{0, 1}, 0.2, [2]Vec2[float64]{{0.5, 1.9}, {1.5, 1.9}}, 2}, true,
+		},
+		{
+			square(1.5, 0.5, 2), square(0, 0, 2),
+			Manifold2[float64]{Vec2[float64]{-1, 0}, 0.5, [2]Vec2[float64]{{1.75, 0.5}, {1.75, 2}}, 2}, true,
+		},
+		{
+			square(0, 0, 2), square(2, 0, 1), // touching
+			Manifold2[float64]{Vec2[float64]{1, 0}, 0, [2]Vec2[float64]{{2, 0}, {2, 1}}, 2}, true,
+		},
+		{
+			square(0, 0, 2), Poly[float64]{{1, 1.5}, {2, 2.5}, {1, 3.5}, {0, 2.5}}, // corner into an edge
+			Manifold2[float64]{Vec2[float64]{0, 1}, 0.5, [2]Vec2[float64]{{1, 1.75}}, 1}, true,
+		},
+		{
+			square(-1, -1, 4), tri,
+			Manifold2[float64]{Vec2[float64]{0, 1}, 3, [2]Vec2[float64]{{0, 1.5}, {2, 1.5}}, 2}, true,
+		},
+		{square(0, 0, 2), square(3, 0, 1), Manifold2[float64]{}, false},
+		{tri, Poly[float64]{{2, 1.5}, {3, 1.5}, {3, 3}}, Manifold2[float64]{}, false},
+	}
+
+	for _, c := range cases {
+		actual, ok := CollidePolys(c.a, c.b)
+		if ok != c.ok || (ok && !manifoldIdentical(c.result, actual)) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.result, c.ok, actual, ok)
+		}
+	}
+}
+
+func TestCollidePolysRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	for k := 0; k < 2000; k++ {
+		a := randConvex(r, Vec2[float64]{}, 4)
+		b := randConvex(r, Vec2[float64]{r.Float64()*6 - 3, r.Float64()*6 - 3}, 4)
+
+		axis, depth := satDepth(a, b)
+		m, ok := CollidePolys(a, b)
+		if ok != (depth >= 0) {
+			t.Fatalf("a: %v, b: %v, expected: %v, got: %v", a, b, depth >= 0, ok)
+		}
+		if !ok {
+			dist, pa, pb := ConvexDistance(a, b)
+			if !floatIdentical(dist, pa.Minus(pb).Len()) || dist <= 0 || !floatIdentical(dist, -minSeparation(a, b)) {
+				t.Fatalf("a: %v, b: %v, expected distance: %v, got: %v", a, b, -minSeparation(a, b), dist)
+			}
+			continue
+		}
+
+		if !floatIdentical(depth, m.Depth) {
+			t.Fatalf("a: %v, b: %v, expected depth: %v along %v, got: %v along %v", a, b, depth, axis, m.Depth, m.Normal)
+		}
+		if m.NumPoints < 1 || m.NumPoints > 2 {
+			t.Fatalf("expected one or two points, got: %v", m.NumPoints)
+		}
+
+		// moving b by the normal and depth leaves the polys touching
+		moved := Poly[float64]{}
+		for _, v := range b {
+			moved = append(moved, v.Plus(m.Normal.ScaledBy(m.Depth)))
+		}
+		if _, d := satDepth(a, moved); math.Abs(d) > 1e-6 {
+			t.Fatalf("a: %v, b: %v, expected separation, got depth: %v", a, b, d)
+		}
+	}
+}
+
+/* brute force distance between the boundaries of a and b, negated */
+func minSeparation(a, b Poly[float64]) float64 {
+	dist := math.Inf(1)
+	for _, p := range a {
+		for i := range b {
+			dist = math.Min(dist, Segment2[float64]{b[i], b[(i+1)%len(b)]}.Distance(p))
+		}
+	}
+	for _, p := range b {
+		for i := range a {
+			dist = math.Min(dist, Segment2[float64]{a[i], a[(i+1)%len(a)]}.Distance(p))
+		}
+	}
+	return -dist
+}
+
+func TestCollidePolyCircle(t *testing.T) {
+	cases := []struct {
+		a      Poly[float64]
+		b      Circle[float64]
+		result Manifold2[float64]
+		ok     bool
+	}{
+		{
+			square(0, 0, 2), Circle[float64]{Vec2[float64]{3, 1}, 1.5},
+			Manifold2[float64]{Vec2[float64]{1, 0}, 0.5, [2]Vec2[float64]{{1.75, 1}}, 1}, true,
+		},
+		{
+			square(0, 0, 2), Circle[float64]{Vec2[float64]{1.8, 1}, 0.5}, // centre inside
+			Manifold2[float64]{Vec2[float64]{1, 0}, 0.7, [2]Vec2[float64]{{1.65, 1}}, 1}, true,
+		},
+		{
+			square(0, 0, 2), Circle[float64]{Vec2[float64]{3, 3}, math.Sqrt2}, // corner
+			Manifold2[float64]{Vec2[float64]{math.Sqrt(0.5), math.Sqrt(0.5)}, 0, [2]Vec2[float64]{{2, 2}}, 1}, true,
+		},
+		{square(0, 0, 2), Circle[float64]{Vec2[float64]{3, 3}, 1.4}, Manifold2[float64]{}, false},
+	}
+
+	for _, c := range cases {
+		actual, ok := CollidePolyCircle(c.a, c.b)
+		if ok != c.ok || (ok && !manifoldIdentical(c.result, actual)) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.result, c.ok, actual, ok)
+		}
+	}
+}
+
+func TestCollideCircles(t *testing.T) {
+	cases := []struct {
+		a, b   Circle[float64]
+		result Manifold2[float64]
+		ok     bool
+	}{
+		{
+			Circle[float64]{Vec2[float64]{0, 0}, 1}, Circle[float64]{Vec2[float64]{0, 1.5}, 1},
+			Manifold2[float64]{Vec2[float64]{0, 1}, 0.5, [2]Vec2[float64]{{0, 0.75}}, 1}, true,
+		},
+		{
+			Circle[float64]{Vec2[float64]{1, 1}, 1}, Circle[float64]{Vec2[float64]{1, 1}, 2},
+			Manifold2[float64]{Vec2[float64]{1, 0}, 3, [2]Vec2[float64]{{0.5, 1}}, 1}, true,
+		},
+		{Circle[float64]{Vec2[float64]{0, 0}, 1}, Circle[float64]{Vec2[float64]{3, 0}, 1}, Manifold2[float64]{}, false},
+	}
+
+	for _, c := range cases {
+		actual, ok := CollideCircles(c.a, c.b)
+		if ok != c.ok || (ok && !manifoldIdentical(c.result, actual)) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.result, c.ok, actual, ok)
+		}
+	}
+}
+
+func TestCollideRects(t *testing.T) {
+	cases := []struct {
+		a, b   Rect[float64]
+		result Manifold2[float64]
+		ok     bool
+	}{
+		{
+			MakeRect[float64](0, 0, 2, 2), MakeRect[float64](1.5, 0.5, 2, 2),
+			Manifold2[float64]{Vec2[float64]{1, 0}, 0.5, [2]Vec2[float64]{{1.75, 0.5}, {1.75, 2}}, 2}, true,
+		},
+		{
+			MakeRect[float64](0, 0, 4, 1), MakeRect[float64](1, -0.5, 1, 1),
+			Manifold2[float64]{Vec2[float64]{0, -1}, 0.5, [2]Vec2[float64]{{1, 0.25}, {2, 0.25}}, 2}, true,
+		},
+		{MakeRect[float64](0, 0, 1, 1), MakeRect[float64](2, 0, 1, 1), Manifold2[float64]{}, false},
+	}
+
+	for _, c := range cases {
+		actual, ok := CollideRects(c.a, c.b)
+		if ok != c.ok || (ok && !manifoldIdentical(c.result, actual)) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.result, c.ok, actual, ok)
+		}
+
+		// agrees with the general path
+		va, vb := c.a.Verts(), c.b.Verts()
+		general, ok := CollidePolys(Poly[float64](va[:]), Poly[float64](vb[:]))
+		if ok != c.ok || (ok && (!vec2Identical(actual.Normal, general.Normal) || !floatIdentical(actual.Depth, general.Depth))) {
+			t.Errorf("a: %v, b: %v, expected: %v, got: %v", c.a, c.b, actual, general)
+		}
+	}
+}
+
+func TestCollideRectShapes(t *testing.T) {
+	circleCases := []struct {
+		a      Rect[float64]
+		b      Circle[float64]
+		result Manifold2[float64]
+		ok     bool
+	}{
+		{
+			MakeRect[float64](0, 0, 2, 2), Circle[float64]{Vec2[float64]{2.5, 1}, 1},
+			Manifold2[float64]{Vec2[float64]{1, 0}, 0.5, [2]Vec2[float64]{{1.75, 1}}, 1}, true,
+		},
+		{
+			MakeRect[float64](0, 0, 2, 2), Circle[float64]{Vec2[float64]{1, -0.5}, 1},
+			Manifold2[float64]{Vec2[float64]{0, -1}, 0.5, [2]Vec2[float64]{{1, 0.25}}, 1}, true,
+		},
+		{MakeRect[float64](0, 0, 2, 2), Circle[float64]{Vec2[float64]{4, 1}, 1}, Manifold2[float64]{}, false},
+	}
+
+	for _, c := range circleCases {
+		actual, ok := CollideRectCircle(c.a, c.b)
+		if ok != c.ok || (ok && !manifoldIdentical(c.result, actual)) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.result, c.ok, actual, ok)
+		}
+	}
+
+	polyCases := []struct {
+		a      Poly[float64]
+		b      Rect[float64]
+		result Manifold2[float64]
+		ok     bool
+	}{
+		{
+			Poly[float64]{{-1, 0.5}, {0.5, 1}, {-1, 1.5}}, MakeRect[float64](0, 0, 2, 2),
+			Manifold2[float64]{Vec2[float64]{1, 0}, 0.5, [2]Vec2[float64]{{0.25, 1}}, 1}, true,
+		},
+		{
+			square(0.5, 1.5, 1), MakeRect[float64](0, 0, 2, 2),
+			Manifold2[float64]{Vec2[float64]{0, -1}, 0.5, [2]Vec2[float64]{{0.5, 1.75}, {1.5, 1.75}}, 2}, true,
+		},
+		{Poly[float64]{{-2, 0}, {-1, 0}, {-1, 1}}, MakeRect[float64](0, 0, 2, 2), Manifold2[float64]{}, false},
+	}
+
+	for _, c := range polyCases {
+		actual, ok := CollidePolyRect(c.a, c.b)
+		if ok != c.ok || (ok && !manifoldIdentical(c.result, actual)) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.result, c.ok, actual, ok)
+		}
+	}
+}
+
+func TestConvexDistance(t *testing.T) {
+	cases := []struct {
+		a, b   Poly[float64]
+		dist   float64
+		pa, pb Vec2[float64]
+		unique bool // closest points are unique
+	}{
+		{square(0, 0, 2), square(3, 4, 1), math.Sqrt(5), Vec2[float64]{2, 2}, Vec2[float64]{3, 4}, true},
+		{square(0, 0, 2), square(3, 0.5, 1), 1, Vec2[float64]{}, Vec2[float64]{}, false},
+		{square(0, 0, 2), Poly[float64]{{5, 5}}, math.Sqrt(18), Vec2[float64]{2, 2}, Vec2[float64]{5, 5}, true},
+		{square(0, 0, 2), square(1, 1, 2), 0, Vec2[float64]{}, Vec2[float64]{}, false},
+	}
+
+	for _, c := range cases {
+		dist, pa, pb := ConvexDistance(c.a, c.b)
+		if !floatIdentical(c.dist, dist) || !floatIdentical(c.dist, pa.Minus(pb).Len()) || !c.a.Contains(pa) {
+			t.Errorf("a: %v, b: %v, expected: %v, got: %v %v %v", c.a, c.b, c.dist, dist, pa, pb)
+		}
+		if c.unique && (!vec2Identical(c.pa, pa) || !vec2Identical(c.pb, pb)) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.pa, c.pb, pa, pb)
+		}
+	}
+}
@@ -44,3 +44,116 @@ func TestCuboidOrigin(t *testing.T) {
 		}
 	}
 }
+
+func TestCuboidBounds(t *testing.T) {
+	cases := []struct {
+		points []Vec3[float64]
+		result Cuboid[float64]
+	}{
+		{[]Vec3[float64]{}, Cuboid[float64]{}},
+		{[]Vec3[float64]{{1, 2, 3}}, Cuboid[float64]{Min: Vec3[float64]{1, 2, 3}, Max: Vec3[float64]{1, 2, 3}}},
+		{
+			[]Vec3[float64]{{1, 2, 3}, {-3, 4, 0}, {5, -6, 1}},
+			Cuboid[float64]{Min: Vec3[float64]{-3, -6, 0}, Max: Vec3[float64]{5, 4, 3}},
+		},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		if actual := CuboidBounds(c.points); !cuboidIdentical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func TestCuboidCentre(t *testing.T) {
+	expected := Vec3[float64]{1, 2, 3}
+	actual := Cuboid[float64]{Min: Vec3[float64]{0, 1, 2}, Max: Vec3[float64]{2, 3, 4}}.Centre()
+	if !vec3Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestCuboidIntersection(t *testing.T) {
+	cases := []struct {
+		a, b   Cuboid[float64]
+		ok     bool
+		result Cuboid[float64]
+	}{
+		{
+			CuboidOrigin[float64](2, 2, 2),
+			Cuboid[float64]{Min: Vec3[float64]{1, 1, 1}, Max: Vec3[float64]{3, 3, 3}},
+			true,
+			Cuboid[float64]{Min: Vec3[float64]{1, 1, 1}, Max: Vec3[float64]{2, 2, 2}},
+		},
+		{
+			CuboidOrigin[float64](2, 2, 2),
+			Cuboid[float64]{Min: Vec3[float64]{0, 0, 2}, Max: Vec3[float64]{1, 1, 3}},
+			true,
+			Cuboid[float64]{Min: Vec3[float64]{0, 0, 2}, Max: Vec3[float64]{1, 1, 2}},
+		},
+		{
+			CuboidOrigin[float64](2, 2, 2),
+			Cuboid[float64]{Min: Vec3[float64]{0, 0, 3}, Max: Vec3[float64]{1, 1, 4}},
+			false,
+			Cuboid[float64]{},
+		},
+	}
+
+	for _, c := range cases {
+		if actual := c.a.Intersects(c.b); actual != c.ok {
+			t.Errorf("a: %v, b: %v, expected: %v, got: %v", c.a, c.b, c.ok, actual)
+		}
+		actual, ok := c.a.Intersection(c.b)
+		if ok != c.ok || !cuboidIdentical(c.result, actual) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.result, c.ok, actual, ok)
+		}
+	}
+}
+
+func TestCuboidUnion(t *testing.T) {
+	expected := Cuboid[float64]{Min: Vec3[float64]{-1, 0, 0}, Max: Vec3[float64]{2, 5, 2}}
+	actual := CuboidOrigin[float64](2, 2, 2).Union(Cuboid[float64]{Min: Vec3[float64]{-1, 4, 1}, Max: Vec3[float64]{0, 5, 1}})
+	if !cuboidIdentical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestCuboidExpandToInclude(t *testing.T) {
+	expected := Cuboid[float64]{Min: Vec3[float64]{0, -1, 0}, Max: Vec3[float64]{2, 2, 5}}
+	actual := CuboidOrigin[float64](2, 2, 2).ExpandToInclude(Vec3[float64]{1, -1, 5})
+	if !cuboidIdentical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestCuboidInset(t *testing.T) {
+	c := CuboidOrigin[float64](4, 2, 6)
+	cases := []struct {
+		margin float64
+		result Cuboid[float64]
+	}{
+		{0.5, Cuboid[float64]{Min: Vec3[float64]{0.5, 0.5, 0.5}, Max: Vec3[float64]{3.5, 1.5, 5.5}}},
+		{1.5, Cuboid[float64]{Min: Vec3[float64]{1.5, 1, 1.5}, Max: Vec3[float64]{2.5, 1, 4.5}}},
+		{-1, Cuboid[float64]{Min: Vec3[float64]{-1, -1, -1}, Max: Vec3[float64]{5, 3, 7}}},
+	}
+
+	for _, cs := range cases {
+		expected := cs.result
+		if actual := c.Inset(cs.margin); !cuboidIdentical(expected, actual) {
+			t.Errorf("margin: %v, expected: %v, got: %v", cs.margin, expected, actual)
+		}
+		if actual := c.Outset(-cs.margin); !cuboidIdentical(expected, actual) {
+			t.Errorf("margin: %v, expected: %v, got: %v", -cs.margin, expected, actual)
+		}
+	}
+}
+
+func TestCuboidClamp(t *testing.T) {
+	c := CuboidOrigin[float64](4, 2, 6)
+	expected := Vec3[float64]{4, 1, 0}
+	actual := c.Clamp(Vec3[float64]{5, 1, -3})
+	if !vec3Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
@@ -0,0 +1,403 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func polylineDistance(line Poly[float64], v Vec2[float64]) float64 {
+	dist := math.Inf(1)
+	for i := 1; i < len(line); i++ {
+		dist = math.Min(dist, Segment2[float64]{line[i-1], line[i]}.Distance(v))
+	}
+	return dist
+}
+
+func numericDerivative(at func(float64) Vec2[float64], t float64) Vec2[float64] {
+	const h = 1e-6
+	return at(t + h).Minus(at(t - h)).ScaledBy(1 / (2 * h))
+}
+
+func expectPanic(t *testing.T, msg string, f func()) {
+	t.Helper()
+	defer func() {
+		if r := recover(); r != msg {
+			t.Errorf("expected panic: %v, got: %v", msg, r)
+		}
+	}()
+	f()
+}
+
+func TestBezier2At(t *testing.T) {
+	quad := Bezier2[float64]{{0, 0}, {1, 2}, {2, 0}}
+	cubic := Bezier2[float64]{{0, 0}, {0, 3}, {3, 3}, {3, 0}}
+
+	for _, c := range []struct {
+		curve    Bezier2[float64]
+		t        float64
+		expected Vec2[float64]
+	}{
+		{quad, 0, Vec2[float64]{0, 0}},
+		{quad, 0.5, Vec2[float64]{1, 1}},
+		{quad, 1, Vec2[float64]{2, 0}},
+		{cubic, 0.5, Vec2[float64]{1.5, 2.25}},
+		{cubic, 0.25, Vec2[float64]{0.46875, 1.6875}},
+		{cubic, 1, Vec2[float64]{3, 0}},
+	} {
+		if actual := c.curve.At(c.t); !vec2Identical(c.expected, actual) {
+			t.Errorf("%v at %v, expected: %v, got: %v", c.curve, c.t, c.expected, actual)
+		}
+	}
+}
+
+func TestBezier2Derivative(t *testing.T) {
+	cubic := Bezier2[float64]{{0, 0}, {1, 4}, {3, -2}, {5, 1}}
+	for _, tt := range []float64{0.1, 0.3, 0.5, 0.9} {
+		expected := numericDerivative(cubic.At, tt)
+		if actual := cubic.Derivative(tt); expected.Minus(actual).Len() > 1e-5 {
+			t.Errorf("derivative at %v, expected: %v, got: %v", tt, expected, actual)
+		}
+		expected = numericDerivative(cubic.Derivative, tt)
+		if actual := cubic.SecondDerivative(tt); expected.Minus(actual).Len() > 1e-5 {
+			t.Errorf("second derivative at %v, expected: %v, got: %v", tt, expected, actual)
+		}
+	}
+
+	if expected, actual := (Vec2[float64]{3, 12}), cubic.Derivative(0); !vec2Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestBezier2Split(t *testing.T) {
+	cubic := Bezier2[float64]{{0, 0}, {1, 4}, {3, -2}, {5, 1}}
+	for _, split := range []float64{0, 0.3, 0.5, 1} {
+		left, right := cubic.Split(split)
+		if len(left) != 4 || len(right) != 4 {
+			t.Fatalf("expected cubic halves, got: %v %v", left, right)
+		}
+		for _, u := range []float64{0, 0.2, 0.7, 1} {
+			if expected, actual := cubic.At(split*u), left.At(u); !vec2Identical(expected, actual) {
+				t.Errorf("split: %v, left at %v, expected: %v, got: %v", split, u, expected, actual)
+			}
+			if expected, actual := cubic.At(split+(1-split)*u), right.At(u); !vec2Identical(expected, actual) {
+				t.Errorf("split: %v, right at %v, expected: %v, got: %v", split, u, expected, actual)
+			}
+		}
+	}
+}
+
+func TestBezier2Bounds(t *testing.T) {
+	for _, c := range []struct {
+		curve    Bezier2[float64]
+		expected Rect[float64]
+	}{
+		{Bezier2[float64]{{0, 0}, {1, 2}, {2, 0}}, Rect[float64]{Vec2[float64]{0, 0}, Vec2[float64]{2, 1}}},
+		{Bezier2[float64]{{0, 0}, {0, 3}, {3, 3}, {3, 0}}, Rect[float64]{Vec2[float64]{0, 0}, Vec2[float64]{3, 2.25}}},
+		{Bezier2[float64]{{0, 0}, {3, 1}, {2, 2}}, Rect[float64]{Vec2[float64]{0, 0}, Vec2[float64]{2.25, 2}}},
+		{Bezier2[float64]{{1, 1}, {2, 2}}, Rect[float64]{Vec2[float64]{1, 1}, Vec2[float64]{2, 2}}},
+	} {
+		if actual := c.curve.Bounds(); !rectIdentical(c.expected, actual) {
+			t.Errorf("%v, expected: %v, got: %v", c.curve, c.expected, actual)
+		}
+	}
+}
+
+func TestBezier2Flatten(t *testing.T) {
+	cubic := Bezier2[float64]{{0, 0}, {10, 40}, {30, -20}, {50, 10}}
+	for _, tolerance := range []float64{1, 0.1, 0.001} {
+		line := cubic.Flatten(tolerance)
+		if !vec2Identical(cubic[0], line[0]) || !vec2Identical(cubic[3], line[len(line)-1]) {
+			t.Errorf("expected line to start and end with the curve, got: %v", line)
+		}
+		for i := 0; i <= 1000; i++ {
+			v := cubic.At(float64(i) / 1000)
+			if d := polylineDistance(line, v); d > tolerance*1.0001 {
+				t.Fatalf("tolerance: %v, point %v is %v from the line", tolerance, v, d)
+			}
+		}
+		if tolerance == 1 && len(line) > 20 {
+			t.Errorf("expected a coarse line, got %v points", len(line))
+		}
+	}
+}
+
+func TestBezier2ArcLength(t *testing.T) {
+	// uneven speed along a straight line
+	line := Bezier2[float64]{{0, 0}, {9, 0}, {10, 0}}
+	arc := line.ArcLength(1e-3)
+	if !floatIdentical(10, arc.Length()) {
+		t.Errorf("expected length: %v, got: %v", 10, arc.Length())
+	}
+	for _, s := range []float64{-1, 0, 2.5, 5, 9, 10, 11} {
+		expected := math.Max(0, math.Min(10, s))
+		if actual := line.At(arc.Param(s)).X; math.Abs(expected-actual) > 1e-2 {
+			t.Errorf("at length %v, expected: %v, got: %v", s, expected, actual)
+		}
+	}
+
+	// quarter circle
+	k := 4.0 / 3 * (math.Sqrt2 - 1)
+	quarter := Bezier2[float64]{{1, 0}, {1, k}, {k, 1}, {0, 1}}
+	if length := quarter.ArcLength(1e-4).Length(); math.Abs(length-math.Pi/2) > 1e-3 {
+		t.Errorf("expected length: %v, got: %v", math.Pi/2, length)
+	}
+}
+
+func TestBezier3(t *testing.T) {
+	cubic := Bezier3[float64]{{0, 0, 0}, {0, 3, 1}, {3, 3, -1}, {3, 0, 2}}
+	if expected, actual := (Vec3[float64]{1.5, 2.25, 0.25}), cubic.At(0.5); !vec3Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	left, right := cubic.Split(0.4)
+	if !vec3Identical(cubic.At(0.2), left.At(0.5)) || !vec3Identical(cubic.At(0.7), right.At(0.5)) {
+		t.Errorf("expected split halves to follow the curve")
+	}
+
+	bounds := cubic.Bounds()
+	for i := 0; i <= 1000; i++ {
+		if v := cubic.At(float64(i) / 1000); !bounds.Outset(1e-9).Contains(v) {
+			t.Fatalf("expected %v to contain %v", bounds, v)
+		}
+	}
+	if !floatIdentical(2.25, bounds.Max.Y) || !floatIdentical(2, bounds.Max.Z) || !floatIdentical(0, bounds.Min.Z) {
+		t.Errorf("expected tight bounds, got: %v", bounds)
+	}
+
+	line := cubic.Flatten(0.01)
+	for i := 1; i < len(line); i++ {
+		mid := line[i-1].Plus(line[i]).ScaledBy(0.5)
+		closest := math.Inf(1)
+		for j := 0; j <= 2000; j++ {
+			closest = math.Min(closest, cubic.At(float64(j)/2000).Minus(mid).Len())
+		}
+		if closest > 0.01*1.01 {
+			t.Fatalf("expected line within tolerance, got: %v", closest)
+		}
+	}
+
+	h := 1e-6
+	numeric := cubic.At(0.3 + h).Minus(cubic.At(0.3 - h)).ScaledBy(1 / (2 * h))
+	if numeric.Minus(cubic.Derivative(0.3)).Len() > 1e-5 {
+		t.Errorf("expected derivative: %v, got: %v", numeric, cubic.Derivative(0.3))
+	}
+}
+
+/* Barry and Goldman's pyramid for the segment from p1 to p2 */
+func centripetalAt(p0, p1, p2, p3 Vec2[float64], u float64) Vec2[float64] {
+	t0 := 0.0
+	t1 := t0 + math.Sqrt(p1.Minus(p0).Len())
+	t2 := t1 + math.Sqrt(p2.Minus(p1).Len())
+	t3 := t2 + math.Sqrt(p3.Minus(p2).Len())
+	t := t1 + (t2-t1)*u
+
+	lerp := func(a, b Vec2[float64], ta, tb float64) Vec2[float64] {
+		return a.ScaledBy((tb - t) / (tb - ta)).Plus(b.ScaledBy((t - ta) / (tb - ta)))
+	}
+	a1, a2, a3 := lerp(p0, p1, t0, t1), lerp(p1, p2, t1, t2), lerp(p2, p3, t2, t3)
+	b1, b2 := lerp(a1, a2, t0, t2), lerp(a2, a3, t1, t3)
+	return lerp(b1, b2, t1, t2)
+}
+
+func TestCatmullRom2(t *testing.T) {
+	spline := CatmullRom2[float64]{{0, 0}, {1, 3}, {1.2, 3.1}, {5, 0}, {6, 4}}
+
+	for i, p := range spline {
+		if actual := spline.At(float64(i) / 4); !vec2Identical(p, actual) {
+			t.Errorf("expected to pass through %v, got: %v", p, actual)
+		}
+	}
+
+	for i := 1; i+2 < len(spline); i++ {
+		for _, u := range []float64{0.2, 0.5, 0.8} {
+			expected := centripetalAt(spline[i-1], spline[i], spline[i+1], spline[i+2], u)
+			if actual := spline.At((float64(i) + u) / 4); !vec2Identical(expected, actual) {
+				t.Errorf("segment %v at %v, expected: %v, got: %v", i, u, expected, actual)
+			}
+		}
+	}
+
+	// the tangent direction is continuous at each point
+	for i := 1; i < 4; i++ {
+		before := spline.Derivative(float64(i)/4 - 1e-9)
+		after := spline.Derivative(float64(i)/4 + 1e-9)
+		if math.Abs(before.Normal().Cross(after.Normal())) > 1e-6 || before.Dot(after) <= 0 {
+			t.Errorf("point %v, expected matching tangents, got: %v %v", i, before, after)
+		}
+	}
+
+	if beziers := spline.Beziers(); len(beziers) != 4 {
+		t.Errorf("expected 4 segments, got: %v", len(beziers))
+	}
+
+	bounds := spline.Bounds()
+	for i := 0; i <= 1000; i++ {
+		if v := spline.At(float64(i) / 1000); !bounds.Outset(1e-9).Contains(v) {
+			t.Fatalf("expected %v to contain %v", bounds, v)
+		}
+	}
+
+	line := spline.Flatten(0.01)
+	for i := 0; i <= 1000; i++ {
+		if d := polylineDistance(line, spline.At(float64(i)/1000)); d > 0.01*1.0001 {
+			t.Fatalf("expected line within tolerance, got: %v", d)
+		}
+	}
+
+	// two points make a straight line
+	straight := CatmullRom2[float64]{{0, 0}, {4, 0}}
+	if length := straight.ArcLength(1e-3).Length(); !floatIdentical(4, length) {
+		t.Errorf("expected length: %v, got: %v", 4, length)
+	}
+}
+
+func TestCatmullRom3(t *testing.T) {
+	spline := CatmullRom3[float64]{{0, 0, 0}, {1, 2, 3}, {4, 0, 1}, {4, 4, 4}}
+	for i, p := range spline {
+		if actual := spline.At(float64(i) / 3); !vec3Identical(p, actual) {
+			t.Errorf("expected to pass through %v, got: %v", p, actual)
+		}
+	}
+
+	arc := spline.ArcLength(1e-4)
+	for _, s := range []float64{0.5, 2, 5} {
+		// integrate the speed up to the parameter
+		param := arc.Param(s)
+		walked := 0.0
+		for i := 0; i < 10000; i++ {
+			walked += spline.Derivative(param*(float64(i)+0.5)/10000).Len() * param / 10000
+		}
+		if math.Abs(walked-s) > 1e-3 {
+			t.Errorf("expected distance: %v, got: %v", s, walked)
+		}
+	}
+}
+
+func TestBSpline2(t *testing.T) {
+	spline := BSpline2[float64]{{0, 0}, {1, 3}, {3, 3}, {4, 0}, {6, 1}, {7, 5}}
+
+	if expected, actual := (Vec2[float64]{(0 + 4 + 3) / 6.0, (0 + 12 + 3) / 6.0}), spline.At(0); !vec2Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+	if beziers := spline.Beziers(); len(beziers) != 3 {
+		t.Errorf("expected 3 segments, got: %v", len(beziers))
+	}
+
+	// second derivative continuity at the knots
+	for i := 1; i < 3; i++ {
+		k := float64(i) / 3
+		before, after := spline.SecondDerivative(k-1e-9), spline.SecondDerivative(k+1e-9)
+		if before.Minus(after).Len() > 1e-5 {
+			t.Errorf("knot %v, expected continuity, got: %v %v", i, before, after)
+		}
+	}
+
+	for _, tt := range []float64{0.1, 0.5, 0.9} {
+		expected := numericDerivative(spline.At, tt)
+		if actual := spline.Derivative(tt); expected.Minus(actual).Len() > 1e-4 {
+			t.Errorf("derivative at %v, expected: %v, got: %v", tt, expected, actual)
+		}
+	}
+
+	// evenly spaced collinear points give a line traversed at constant speed
+	line := BSpline2[float64]{{0, 0}, {1, 0}, {2, 0}, {3, 0}, {4, 0}}
+	for _, tt := range []float64{0, 0.25, 0.5, 1} {
+		if expected, actual := (Vec2[float64]{1 + 2*tt, 0}), line.At(tt); !vec2Identical(expected, actual) {
+			t.Errorf("at %v, expected: %v, got: %v", tt, expected, actual)
+		}
+	}
+	if bounds := line.Bounds(); !rectIdentical(Rect[float64]{Vec2[float64]{1, 0}, Vec2[float64]{3, 0}}, bounds) {
+		t.Errorf("expected tight bounds, got: %v", bounds)
+	}
+}
+
+func TestBSpline3(t *testing.T) {
+	spline := BSpline3[float64]{{0, 0, 0}, {1, 3, 1}, {3, 3, 2}, {4, 0, 3}, {6, 1, 2}}
+	expected := Vec3[float64]{3 + 16 + 6, 3 + 0 + 1, 2 + 12 + 2}.ScaledBy(1.0 / 6)
+	if actual := spline.At(1); !vec3Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	line := spline.Flatten(0.05)
+	if !vec3Identical(spline.At(0), line[0]) || !vec3Identical(expected, line[len(line)-1]) {
+		t.Errorf("expected line to cover the curve, got: %v", line)
+	}
+	if !spline.Bounds().Contains(spline.At(0.37)) {
+		t.Errorf("expected bounds to contain the curve")
+	}
+}
+
+func TestCurveDegenerate(t *testing.T) {
+	// a single point is a curve of no length
+	point2 := Bezier2[float64]{{1, 2}}
+	if line := point2.Flatten(0.1); len(line) != 2 || !vec2Identical(point2[0], line[0]) || !vec2Identical(point2[0], line[1]) {
+		t.Errorf("expected the point twice, got: %v", line)
+	}
+	if arc := point2.ArcLength(0.1); arc.Length() != 0 || arc.Param(1) != 1 {
+		t.Errorf("expected no length, got: %v", arc.Length())
+	}
+	point3 := Bezier3[float64]{{1, 2, 3}}
+	if line := point3.Flatten(0.1); len(line) != 2 || !vec3Identical(point3[0], line[1]) {
+		t.Errorf("expected the point twice, got: %v", line)
+	}
+	if arc := point3.ArcLength(0.1); arc.Length() != 0 {
+		t.Errorf("expected no length, got: %v", arc.Length())
+	}
+
+	// splines without a segment
+	expectPanic(t, "not enough points", func() { CatmullRom2[float64]{{0, 0}}.Bounds() })
+	expectPanic(t, "not enough points", func() { CatmullRom3[float64]{}.Bounds() })
+	expectPanic(t, "not enough points", func() { BSpline2[float64]{{0, 0}, {1, 0}, {2, 0}}.Bounds() })
+	expectPanic(t, "not enough points", func() { BSpline3[float64]{{0, 0, 0}}.Bounds() })
+	expectPanic(t, "not enough points", func() { Bezier2[float64]{}.Flatten(0.1) })
+}
+
+func TestSplineSplit(t *testing.T) {
+	catmull := CatmullRom2[float64]{{0, 0}, {1, 3}, {3, 3}, {4, 0}}
+	bspline := BSpline2[float64]{{0, 0}, {1, 3}, {3, 3}, {4, 0}, {6, 1}, {7, 5}}
+	splines := []struct {
+		at    func(float64) Vec2[float64]
+		split func(float64) ([]Bezier2[float64], []Bezier2[float64])
+		n     int
+	}{
+		{catmull.At, catmull.Split, 3},
+		{bspline.At, bspline.Split, 3},
+	}
+
+	for i, s := range splines {
+		for _, tt := range []float64{0, 0.2, 0.5, 2.0 / 3, 0.9, 1} {
+			left, right := s.split(tt)
+			if len(left)+len(right) != s.n+1 {
+				t.Errorf("spline %v at %v, expected %v segments, got: %v %v", i, tt, s.n+1, len(left), len(right))
+				continue
+			}
+			end, start := left[len(left)-1], right[0]
+			if !vec2Identical(s.at(tt), end[len(end)-1]) || !vec2Identical(s.at(tt), start[0]) {
+				t.Errorf("spline %v, expected halves to meet at: %v, got: %v %v", i, s.at(tt), end[len(end)-1], start[0])
+			}
+			if !vec2Identical(s.at(0), left[0][0]) || !vec2Identical(s.at(1), right[len(right)-1][3]) {
+				t.Errorf("spline %v at %v, expected halves to cover the curve", i, tt)
+			}
+
+			// the split segment follows the spline
+			k := float64(len(left) - 1)
+			local := tt*float64(s.n) - k
+			if expected, actual := s.at((k+local/2)/float64(s.n)), end.At(0.5); !vec2Identical(expected, actual) {
+				t.Errorf("spline %v at %v, expected: %v, got: %v", i, tt, expected, actual)
+			}
+		}
+	}
+
+	spline3 := BSpline3[float64]{{0, 0, 0}, {1, 3, 1}, {3, 3, 2}, {4, 0, 3}, {6, 1, 2}}
+	left, right := spline3.Split(0.25)
+	if end := left[len(left)-1]; !vec3Identical(spline3.At(0.25), end[3]) || !vec3Identical(spline3.At(0.25), right[0][0]) {
+		t.Errorf("expected halves to meet at: %v, got: %v %v", spline3.At(0.25), end[3], right[0][0])
+	}
+	catmull3 := CatmullRom3[float64]{{0, 0, 0}, {1, 3, 1}, {3, 3, 2}}
+	left, right = catmull3.Split(0.75)
+	if end := left[len(left)-1]; !vec3Identical(catmull3.At(0.75), end[3]) || !vec3Identical(catmull3.At(0.75), right[0][0]) {
+		t.Errorf("expected halves to meet at: %v, got: %v %v", catmull3.At(0.75), end[3], right[0][0])
+	}
+	expectPanic(t, "not enough points", func() { BSpline2[float64]{{0, 0}}.Split(0.5) })
+}
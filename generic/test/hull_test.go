@@ -0,0 +1,183 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestPolyConvexHull(t *testing.T) {
+	cases := []struct {
+		points []Vec2[float64]
+		result Poly[float64]
+	}{
+		{[]Vec2[float64]{}, Poly[float64]{}},
+		{[]Vec2[float64]{{1, 2}}, Poly[float64]{{1, 2}}},
+		{[]Vec2[float64]{{1, 2}, {1, 2}}, Poly[float64]{{1, 2}}},
+		{[]Vec2[float64]{{3, 4}, {1, 2}}, Poly[float64]{{1, 2}, {3, 4}}},
+		{[]Vec2[float64]{{0, 0}, {2, 2}, {1, 1}, {3, 3}}, Poly[float64]{{0, 0}, {3, 3}}},
+		{
+			[]Vec2[float64]{{0, 0}, {0, 1}, {1, 0}},
+			Poly[float64]{{0, 0}, {1, 0}, {0, 1}},
+		},
+		{ // interior, duplicate and collinear points
+			[]Vec2[float64]{
+				{1, 1}, {0, 0}, {2, 0}, {2, 2}, {0, 2},
+				{1, 0}, {2, 1}, {0, 0}, {0.5, 1.5}, {1, 2},
+			},
+			Poly[float64]{{0, 0}, {2, 0}, {2, 2}, {0, 2}},
+		},
+		{
+			[]Vec2[float64]{{0, 3}, {1, 1}, {2, 2}, {4, 4}, {0, 0}, {1, 2}, {3, 1}, {3, 3}},
+			Poly[float64]{{0, 0}, {3, 1}, {4, 4}, {0, 3}},
+		},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := PolyConvexHull(c.points)
+		if !polyIdentical(expected, actual) {
+			t.Errorf("points: %v, expected: %v, got: %v", c.points, expected, actual)
+		}
+	}
+}
+
+func TestPolyConvexHullRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	points := []Vec2[float64]{}
+	for i := 0; i < 500; i++ {
+		points = append(points, Vec2[float64]{r.Float64(), r.Float64()})
+	}
+
+	hull := PolyConvexHull(points)
+	if hull.Area() <= 0 {
+		t.Errorf("expected clockwise hull, area: %v", hull.Area())
+	}
+	for _, p := range points {
+		if !hull.Contains(p) {
+			t.Errorf("hull does not contain: %v", p)
+		}
+	}
+	for i := range hull {
+		a, b, c := hull[i], hull[(i+1)%len(hull)], hull[(i+2)%len(hull)]
+		if b.Minus(a).Cross(c.Minus(b)) <= 0 {
+			t.Errorf("hull is not strictly convex at: %v", b)
+		}
+	}
+}
+
+/* signed volume by the divergence theorem */
+func hullVolume(points []Vec3[float64], faces [][3]int) float64 {
+	sum := 0.0
+	for _, f := range faces {
+		sum += points[f[0]].Dot(points[f[1]].Cross(points[f[2]]))
+	}
+	return sum / 6
+}
+
+func checkHull(t *testing.T, points []Vec3[float64], faces [][3]int) {
+	edges := map[[2]int]int{}
+	for _, f := range faces {
+		a, b, c := points[f[0]], points[f[1]], points[f[2]]
+		n := b.Minus(a).Cross(c.Minus(a)).Normal()
+		for _, p := range points {
+			if n.Dot(p.Minus(a)) > 1e-9 {
+				t.Errorf("point %v is outside face %v", p, f)
+			}
+		}
+		for j := 0; j < 3; j++ {
+			edges[[2]int{f[j], f[(j+1)%3]}]++
+		}
+	}
+
+	for e, n := range edges {
+		if n != 1 || edges[[2]int{e[1], e[0]}] != 1 {
+			t.Errorf("hull is not a closed manifold at edge: %v", e)
+		}
+	}
+}
+
+func TestVec3ConvexHullCube(t *testing.T) {
+	points := []Vec3[float64]{}
+	for x := 0; x <= 2; x++ {
+		for y := 0; y <= 2; y++ {
+			for z := 0; z <= 2; z++ { // corners, edge and face centres are coplanar
+				points = append(points, Vec3[float64]{float64(x), float64(y), float64(z)})
+			}
+		}
+	}
+	points = append(points, points[0], points[26]) // duplicates
+
+	faces := Vec3ConvexHull(points)
+	if len(faces) != 12 {
+		t.Errorf("expected 12 faces, got: %v", len(faces))
+	}
+	if v := hullVolume(points, faces); !floatIdentical(8, v) {
+		t.Errorf("expected volume 8, got: %v", v)
+	}
+	checkHull(t, points, faces)
+
+	for _, f := range faces {
+		for _, i := range f {
+			p := points[i]
+			if p.X == 1 || p.Y == 1 || p.Z == 1 {
+				t.Errorf("expected only corners as verts, got: %v", p)
+			}
+		}
+	}
+}
+
+func TestVec3ConvexHullSphere(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	points := []Vec3[float64]{}
+	for i := 0; i < 300; i++ {
+		theta := r.Float64() * 2 * math.Pi
+		z := r.Float64()*2 - 1
+		v := Vec3[float64]{math.Sqrt(1-z*z) * math.Cos(theta), math.Sqrt(1-z*z) * math.Sin(theta), z}
+		points = append(points, v, v.ScaledBy(r.Float64()))
+	}
+
+	faces := Vec3ConvexHull(points)
+	checkHull(t, points, faces)
+
+	if v := hullVolume(points, faces); v <= 0 || v > 4*math.Pi/3 {
+		t.Errorf("unexpected volume: %v", v)
+	}
+	if len(faces) != 2*300-4 {
+		t.Errorf("expected %v faces, got: %v", 2*300-4, len(faces))
+	}
+}
+
+func TestVec3ConvexHullDegenerate(t *testing.T) {
+	cases := [][]Vec3[float64]{
+		{},
+		{{1, 2, 3}},
+		{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}},
+		{{1, 1, 1}, {1, 1, 1}, {1, 1, 1}, {1, 1, 1}, {1, 1, 1}},
+		{{0, 0, 0}, {1, 1, 1}, {2, 2, 2}, {3, 3, 3}, {-1, -1, -1}},
+		{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {1, 1, 0}, {0.5, 0.5, 0}},
+	}
+
+	for _, points := range cases {
+		if faces := Vec3ConvexHull(points); len(faces) != 0 {
+			t.Errorf("points: %v, expected no faces, got: %v", points, faces)
+		}
+	}
+
+	faces := Vec3ConvexHull([]Vec3[float64]{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1}})
+	if len(faces) != 4 {
+		t.Errorf("expected tetrahedron, got: %v", faces)
+	}
+}
+
+func TestVec3ConvexHullFloat32(t *testing.T) {
+	points := []Vec3[float32]{
+		{0, 0, 0}, {1, 0, 0}, {0, 1, 0}, {0, 0, 1},
+		{1, 1, 0}, {1, 0, 1}, {0, 1, 1}, {1, 1, 1},
+		{0.5, 0.5, 0.5}, {0.5, 0.5, 0},
+	}
+	if faces := Vec3ConvexHull(points); len(faces) != 12 {
+		t.Errorf("expected 12 faces, got: %v", len(faces))
+	}
+}
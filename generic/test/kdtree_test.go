@@ -0,0 +1,126 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+/* dist(i) for each i below n, sorted */
+func sortedDists(n int, dist func(i int) float64) []float64 {
+	dists := make([]float64, n)
+	for i := range dists {
+		dists[i] = dist(i)
+	}
+	sort.Float64s(dists)
+	return dists
+}
+
+func TestKDTree2(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	points := make([]Vec2[float64], 2000)
+	for i := range points {
+		points[i] = Vec2[float64]{r.Float64() * 100, r.Float64() * 100}
+	}
+	points[10] = points[20] // duplicates are kept
+
+	tree := NewKDTree2(points)
+	if tree.Len() != len(points) {
+		t.Errorf("expected: %v, got: %v", len(points), tree.Len())
+	}
+
+	for k := 0; k < 200; k++ {
+		v := Vec2[float64]{r.Float64()*120 - 10, r.Float64()*120 - 10}
+		dists := sortedDists(len(points), func(i int) float64 {
+			return points[i].Minus(v).Len()
+		})
+
+		nearest, ok := tree.Nearest(v)
+		if !ok || !floatIdentical(dists[0], points[nearest].Minus(v).Len()) {
+			t.Fatalf("point: %v, expected: %v, got: %v", v, dists[0], points[nearest].Minus(v).Len())
+		}
+
+		n := 1 + r.Intn(30)
+		kNearest := tree.KNearest(v, n)
+		if len(kNearest) != n {
+			t.Fatalf("expected: %v results, got: %v", n, len(kNearest))
+		}
+		for j, i := range kNearest {
+			if d := points[i].Minus(v).Len(); !floatIdentical(dists[j], d) {
+				t.Fatalf("point: %v, rank: %v, expected: %v, got: %v", v, j, dists[j], d)
+			}
+		}
+
+		radius := r.Float64() * 10
+		expected := []int{}
+		for i, p := range points {
+			if p.Minus(v).Len() <= radius {
+				expected = append(expected, i)
+			}
+		}
+		actual := tree.WithinRadius(v, radius)
+		sort.Ints(actual)
+		if !intsEqual(expected, actual) {
+			t.Fatalf("point: %v, radius: %v, expected: %v, got: %v", v, radius, expected, actual)
+		}
+	}
+
+	if actual := tree.KNearest(Vec2[float64]{}, 5000); len(actual) != len(points) {
+		t.Errorf("expected: %v, got: %v", len(points), len(actual))
+	}
+}
+
+func TestKDTree3(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := make([]Vec3[float64], 2000)
+	for i := range points {
+		points[i] = Vec3[float64]{r.Float64() * 100, r.Float64() * 10, r.Float64() * 50}
+	}
+
+	tree := NewKDTree3(points)
+	for k := 0; k < 200; k++ {
+		v := Vec3[float64]{r.Float64() * 100, r.Float64() * 10, r.Float64() * 50}
+		dists := sortedDists(len(points), func(i int) float64 {
+			return points[i].Minus(v).Len()
+		})
+
+		nearest, ok := tree.Nearest(v)
+		if !ok || !floatIdentical(dists[0], points[nearest].Minus(v).Len()) {
+			t.Fatalf("point: %v, expected: %v, got: %v", v, dists[0], points[nearest].Minus(v).Len())
+		}
+
+		n := 1 + r.Intn(30)
+		for j, i := range tree.KNearest(v, n) {
+			if d := points[i].Minus(v).Len(); !floatIdentical(dists[j], d) {
+				t.Fatalf("point: %v, rank: %v, expected: %v, got: %v", v, j, dists[j], d)
+			}
+		}
+
+		radius := r.Float64() * 10
+		expected := []int{}
+		for i, p := range points {
+			if p.Minus(v).Len() <= radius {
+				expected = append(expected, i)
+			}
+		}
+		actual := tree.WithinRadius(v, radius)
+		sort.Ints(actual)
+		if !intsEqual(expected, actual) {
+			t.Fatalf("point: %v, radius: %v, expected: %v, got: %v", v, radius, expected, actual)
+		}
+	}
+}
+
+func TestKDTreeEmpty(t *testing.T) {
+	tree := NewKDTree3[float64](nil)
+	if _, ok := tree.Nearest(Vec3[float64]{}); ok {
+		t.Errorf("expected no nearest point")
+	}
+	if actual := tree.KNearest(Vec3[float64]{}, 3); len(actual) != 0 {
+		t.Errorf("expected no points, got: %v", actual)
+	}
+	if actual := tree.WithinRadius(Vec3[float64]{}, 1); len(actual) != 0 {
+		t.Errorf("expected no points, got: %v", actual)
+	}
+}
@@ -186,3 +186,146 @@ func TestMat3Times(t *testing.T) {
 		}
 	}
 }
+
+func TestMat3Transpose(t *testing.T) {
+	expected := Mat3[float64]{
+		1, 4, 7,
+		2, 5, 8,
+		3, 6, 9,
+	}
+	actual := Mat3[float64]{
+		1, 2, 3,
+		4, 5, 6,
+		7, 8, 9,
+	}.Transpose()
+	if !mat3Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestMat3Determinant(t *testing.T) {
+	cases := []struct {
+		mat    Mat3[float64]
+		result float64
+	}{
+		{Mat3Identity[float64](), 1},
+		{Mat3[float64]{1, 2, 3, 4, 5, 6, 7, 8, 9}, 0},
+		{Mat3[float64]{2, 0, 1, 1, 3, 2, 1, 1, 2}, 6},
+		{Mat3Scalar[float64](2, -3), -6},
+		{Mat3Rotation[float64](0.7), 1},
+		{Mat3Translation(Vec2[float64]{5, 6}), 1},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := c.mat.Determinant()
+		if !floatIdentical(expected, actual) {
+			t.Errorf("mat: %v, expected: %v, got: %v", c.mat, expected, actual)
+		}
+	}
+}
+
+func TestMat3Inverse(t *testing.T) {
+	cases := []struct {
+		mat Mat3[float64]
+		ok  bool
+	}{
+		{Mat3Identity[float64](), true},
+		{Mat3[float64]{2, 0, 1, 1, 3, 2, 1, 1, 2}, true},
+		{Mat3Translation(Vec2[float64]{3, -4}).Product(Mat3Rotation[float64](1.2)), true},
+		{Mat3Scalar[float64](0.5, 8), true},
+		{Mat3[float64]{1, 2, 3, 4, 5, 6, 7, 8, 9}, false},
+		{Mat3Scalar[float64](0, 1), false},
+		{Mat3[float64]{nan, 0, 0, 0, 1, 0, 0, 0, 1}, false},
+	}
+
+	for _, c := range cases {
+		inv, ok := c.mat.Inverse()
+		if ok != c.ok {
+			t.Errorf("mat: %v, expected: %v, got: %v", c.mat, c.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		expected := Mat3Identity[float64]()
+		if actual := c.mat.Product(inv); !mat3Identical(expected, actual) {
+			t.Errorf("mat: %v, expected: %v, got: %v", c.mat, expected, actual)
+		}
+		if actual := inv.Product(c.mat); !mat3Identical(expected, actual) {
+			t.Errorf("mat: %v, expected: %v, got: %v", c.mat, expected, actual)
+		}
+	}
+}
+
+func TestMat3InverseCamera2D(t *testing.T) {
+	camera := Rect[float64]{
+		Min: Vec2[float64]{10, 16},
+		Max: Vec2[float64]{50, 32},
+	}
+	display := Rect[float64]{
+		Min: Vec2[float64]{-1, -2},
+		Max: Vec2[float64]{3, 4},
+	}
+	inv, ok := Mat3Camera2D(camera, display).Inverse()
+	if !ok {
+		t.Fatalf("expected invertible")
+	}
+
+	expected := Vec2[float64]{30, 24}
+	actual := inv.TimesVec2(Vec2[float64]{1, 1}, 1)
+	if !vec2Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestMat3Decompose(t *testing.T) {
+	cases := []struct {
+		mat         Mat3[float64]
+		translation Vec2[float64]
+		theta       float64
+		scale       Vec2[float64]
+		ok          bool
+	}{
+		{Mat3Identity[float64](), Vec2[float64]{}, 0, Vec2[float64]{1, 1}, true},
+		{Mat3Translation(Vec2[float64]{3, -4}), Vec2[float64]{3, -4}, 0, Vec2[float64]{1, 1}, true},
+		{Mat3Rotation[float64](1.2), Vec2[float64]{}, 1.2, Vec2[float64]{1, 1}, true},
+		{Mat3Scalar[float64](2, 3), Vec2[float64]{}, 0, Vec2[float64]{2, 3}, true},
+		{Mat3Scalar[float64](2, -3), Vec2[float64]{}, 0, Vec2[float64]{2, -3}, true},
+		{Mat3Scalar[float64](-2, -3), Vec2[float64]{}, math.Pi, Vec2[float64]{2, 3}, true},
+		{
+			Mat3Translation(Vec2[float64]{5, 6}).
+				Product(Mat3Rotation[float64](-2)).
+				Product(Mat3Scalar[float64](0.5, 4)),
+			Vec2[float64]{5, 6}, -2, Vec2[float64]{0.5, 4}, true,
+		},
+		{Mat3[float64]{1, 1, 0, 0, 1, 0, 0, 0, 1}, Vec2[float64]{}, 0, Vec2[float64]{}, false}, // shear
+		{Mat3Scalar[float64](0, 1), Vec2[float64]{}, 0, Vec2[float64]{}, false},
+		{Mat3[float64]{1, 0, 0, 0, 1, 0, 1, 0, 1}, Vec2[float64]{}, 0, Vec2[float64]{}, false},
+	}
+
+	for _, c := range cases {
+		translation, theta, scale, ok := c.mat.Decompose()
+		if ok != c.ok {
+			t.Errorf("mat: %v, expected: %v, got: %v", c.mat, c.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if !vec2Identical(c.translation, translation) ||
+			!floatIdentical(c.theta, theta) ||
+			!vec2Identical(c.scale, scale) {
+			t.Errorf("mat: %v, expected: %v %v %v, got: %v %v %v",
+				c.mat, c.translation, c.theta, c.scale, translation, theta, scale)
+		}
+
+		expected := c.mat
+		actual := Mat3Compose(translation, theta, scale)
+		if !mat3Identical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
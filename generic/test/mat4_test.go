@@ -383,3 +383,326 @@ func TestMat4RollPitchYaw(t *testing.T) {
 		}
 	}
 }
+
+func TestMat4Transpose(t *testing.T) {
+	m := Mat4[float64]{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+	expected := Mat4[float64]{
+		0, 4, 8, 12,
+		1, 5, 9, 13,
+		2, 6, 10, 14,
+		3, 7, 11, 15,
+	}
+	actual := m.Transpose()
+	if !mat4Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+	if !mat4Identical(m, actual.Transpose()) {
+		t.Errorf("expected: %v, got: %v", m, actual.Transpose())
+	}
+}
+
+func TestMat4Determinant(t *testing.T) {
+	cases := []struct {
+		m      Mat4[float64]
+		result float64
+	}{
+		{Mat4Identity[float64](), 1},
+		{Mat4Scalar[float64](2, 3, 4), 24},
+		{Mat4RollPitchYaw[float64](0.3, -1.2, 2.5), 1},
+		{Mat4[float64]{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, 0},
+		{
+			Mat4[float64]{
+				5, 7, 9, 10,
+				2, 3, 3, 8,
+				8, 10, 2, 3,
+				3, 3, 4, 8,
+			},
+			-361,
+		},
+		{Mat4Perspective[float64](1, -1, 1, -1, 1, 4), -8. / 3.},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := c.m.Determinant()
+		if !floatIdentical(expected, actual) {
+			t.Errorf("m: %v, expected: %v, got: %v", c.m, expected, actual)
+		}
+	}
+}
+
+func TestMat4Inverse(t *testing.T) {
+	cases := []struct {
+		m  Mat4[float64]
+		ok bool
+	}{
+		{Mat4Identity[float64](), true},
+		{Mat4Translation(Vec3[float64]{1, -2, 3}), true},
+		{Mat4Scalar[float64](2, 0.5, -4), true},
+		{Mat4RollPitchYaw[float64](0.3, -1.2, 2.5), true},
+		{Mat4Perspective[float64](2, -2, 1, -1, 1, 4), true},
+		{
+			Mat4[float64]{
+				5, 7, 9, 10,
+				2, 3, 3, 8,
+				8, 10, 2, 3,
+				3, 3, 4, 8,
+			},
+			true,
+		},
+		{Mat4[float64]{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}, false},
+		{Mat4Scalar[float64](1, 0, 1), false},
+		{Mat4[float64]{}, false},
+	}
+
+	for _, c := range cases {
+		inv, ok := c.m.Inverse()
+		if ok != c.ok {
+			t.Errorf("m: %v, expected ok: %v, got: %v", c.m, c.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if actual := c.m.Product(inv); !mat4Identical(Mat4Identity[float64](), actual) {
+			t.Errorf("m: %v, expected identity, got: %v", c.m, actual)
+		}
+		if actual := inv.Product(c.m); !mat4Identical(Mat4Identity[float64](), actual) {
+			t.Errorf("m: %v, expected identity, got: %v", c.m, actual)
+		}
+	}
+}
+
+func TestMat4InverseAffine(t *testing.T) {
+	cases := []struct {
+		m  Mat4[float64]
+		ok bool
+	}{
+		{Mat4Identity[float64](), true},
+		{Mat4Translation(Vec3[float64]{1, -2, 3}), true},
+		{Mat4RotationX[float64](0.7), true},
+		{Mat4RotationY[float64](-2.1), true},
+		{Mat4RotationZ[float64](1.3), true},
+		{Mat4Scalar[float64](2, 0.5, -4), true},
+		{
+			Mat4Translation(Vec3[float64]{4, 5, -6}).
+				Product(Mat4RollPitchYaw[float64](0.3, -1.2, 2.5)).
+				Product(Mat4Scalar[float64](3, 2, 1)),
+			true,
+		},
+		{Mat4Translation(Vec3[float64]{1, 2, 3}).Product(Mat4Scalar[float64](0, 1, 1)), false},
+	}
+
+	for _, c := range cases {
+		inv, ok := c.m.InverseAffine()
+		if ok != c.ok {
+			t.Errorf("m: %v, expected ok: %v, got: %v", c.m, c.ok, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		if actual := c.m.Product(inv); !mat4Identical(Mat4Identity[float64](), actual) {
+			t.Errorf("m: %v, expected identity, got: %v", c.m, actual)
+		}
+
+		expected, _ := c.m.Inverse()
+		if !mat4Identical(expected, inv) {
+			t.Errorf("m: %v, expected: %v, got: %v", c.m, expected, inv)
+		}
+	}
+}
+
+func TestMat4PerspectiveFov(t *testing.T) {
+	cases := []struct {
+		fovY, aspect, n, f float64
+		result             Mat4[float64]
+	}{
+		{math.Pi / 2, 1, 1, 4, Mat4Perspective[float64](1, -1, 1, -1, 1, 4)},
+		{math.Pi / 2, 2, 1, 4, Mat4Perspective[float64](2, -2, 1, -1, 1, 4)},
+		{math.Pi / 3, 1.5, 0.1, 100, Mat4Perspective(
+			0.15*math.Tan(math.Pi/6),
+			-0.15*math.Tan(math.Pi/6),
+			0.1*math.Tan(math.Pi/6),
+			-0.1*math.Tan(math.Pi/6),
+			0.1, 100,
+		)},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := Mat4PerspectiveFov(c.fovY, c.aspect, c.n, c.f)
+		if !mat4Identical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func TestMat4PerspectiveFovInfinite(t *testing.T) {
+	p := Mat4PerspectiveFovInfinite[float64](math.Pi/2, 2, 1)
+
+	for _, c := range []struct {
+		world, screen Vec3[float64]
+	}{
+		{Vec3[float64]{2, 1, -1}, Vec3[float64]{1, 1, -1}},
+		{Vec3[float64]{-2, -1, -1}, Vec3[float64]{-1, -1, -1}},
+		{Vec3[float64]{8, 4, -4}, Vec3[float64]{1, 1, 0.5}},
+		{Vec3[float64]{0, 0, -1e12}, Vec3[float64]{0, 0, 1}},
+	} {
+		expected := c.screen
+		actual := p.TransformVec3(c.world, 1)
+		if !vec3Identical(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+
+	// agrees with the finite form for a distant far plane
+	finite := Mat4PerspectiveFov[float64](math.Pi/2, 2, 1, 1e12)
+	v := Vec3[float64]{3, -2, -7}
+	if !vec3Identical(finite.TransformVec3(v, 1), p.TransformVec3(v, 1)) {
+		t.Errorf("expected: %v, actual: %v", finite.TransformVec3(v, 1), p.TransformVec3(v, 1))
+	}
+}
+
+func TestMat4PerspectiveFovReverseZ(t *testing.T) {
+	p := Mat4PerspectiveFovReverseZ[float64](math.Pi/2, 1, 1, 4)
+
+	for _, c := range []struct {
+		world, screen Vec3[float64]
+	}{
+		{Vec3[float64]{1, 1, -1}, Vec3[float64]{1, 1, 1}},
+		{Vec3[float64]{-1, -1, -1}, Vec3[float64]{-1, -1, 1}},
+		{Vec3[float64]{1, 1, -4}, Vec3[float64]{1. / 4., 1. / 4., 0}},
+		{Vec3[float64]{0, 0, -2}, Vec3[float64]{0, 0, 1. / 3.}},
+	} {
+		expected := c.screen
+		actual := p.TransformVec3(c.world, 1)
+		if !vec3Identical(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestMat4PerspectiveFovReverseZInfinite(t *testing.T) {
+	p := Mat4PerspectiveFovReverseZInfinite[float64](math.Pi/2, 1, 1)
+
+	for _, c := range []struct {
+		world, screen Vec3[float64]
+	}{
+		{Vec3[float64]{1, 1, -1}, Vec3[float64]{1, 1, 1}},
+		{Vec3[float64]{1, 1, -4}, Vec3[float64]{1. / 4., 1. / 4., 1. / 4.}},
+		{Vec3[float64]{0, 0, -1e12}, Vec3[float64]{0, 0, 0}},
+	} {
+		expected := c.screen
+		actual := p.TransformVec3(c.world, 1)
+		if !vec3Identical(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestMat4Orthographic(t *testing.T) {
+	cases := []struct {
+		r, l, t, b, n, f float64
+		world, screen    []Vec3[float64]
+	}{
+		{
+			1, -1, 1, -1, 1, 4,
+			[]Vec3[float64]{
+				{1, 1, -1},
+				{-1, -1, -4},
+				{0, 0, -2.5},
+			},
+			[]Vec3[float64]{
+				{1, 1, -1},
+				{-1, -1, 1},
+				{0, 0, 0},
+			},
+		},
+		{
+			10, 0, 0, -5, 0, 2,
+			[]Vec3[float64]{
+				{10, 0, 0},
+				{0, -5, -2},
+				{5, -2.5, -1},
+			},
+			[]Vec3[float64]{
+				{1, 1, -1},
+				{-1, -1, 1},
+				{0, 0, 0},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		p := Mat4Orthographic(c.r, c.l, c.t, c.b, c.n, c.f)
+
+		for i := range c.world {
+			expected := c.screen[i]
+			actual := p.TransformVec3(c.world[i], 1)
+
+			if !vec3Identical(expected, actual) {
+				t.Errorf("expected: %v, actual: %v", expected, actual)
+			}
+		}
+	}
+}
+
+func TestMat4LookAt(t *testing.T) {
+	cases := []struct {
+		eye, target, up Vec3[float64]
+		world, view     []Vec3[float64]
+	}{
+		{
+			Vec3[float64]{0, 0, 0}, Vec3[float64]{0, 0, -1}, Vec3[float64]{0, 1, 0},
+			[]Vec3[float64]{{1, 2, 3}},
+			[]Vec3[float64]{{1, 2, 3}},
+		},
+		{
+			Vec3[float64]{1, 2, 3}, Vec3[float64]{1, 2, 8}, Vec3[float64]{0, -1, 0},
+			[]Vec3[float64]{
+				{1, 2, 3}, // eye
+				{1, 2, 8}, // target
+				{1, 1, 8}, // up
+				{2, 2, 8}, // right is +Z cross -Y = +X
+			},
+			[]Vec3[float64]{
+				{0, 0, 0},
+				{0, 0, -5},
+				{0, 1, -5},
+				{1, 0, -5},
+			},
+		},
+		{
+			Vec3[float64]{2, 0, 0}, Vec3[float64]{0, 0, 0}, Vec3[float64]{0, 0, 1},
+			[]Vec3[float64]{
+				{0, 0, 0},
+				{0, 0, 3},
+				{2, 1, 0},
+			},
+			[]Vec3[float64]{
+				{0, 0, -2},
+				{0, 3, -2},
+				{1, 0, 0},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		view := Mat4LookAt(c.eye, c.target, c.up)
+
+		if !floatIdentical(1, view.Determinant()) {
+			t.Errorf("expected right-handed rigid transform, det: %v", view.Determinant())
+		}
+
+		for i := range c.world {
+			expected := c.view[i]
+			actual := view.TransformVec3(c.world[i], 1)
+			if !vec3Identical(expected, actual) {
+				t.Errorf("expected: %v, actual: %v", expected, actual)
+			}
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+	"time"
+)
+
+/* unit cube from the origin with outward facing triangles */
+func unitCube() Mesh3[float64] {
+	positions := []Vec3[float64]{}
+	for i := 0; i < 8; i++ {
+		positions = append(positions, Vec3[float64]{float64(i & 1), float64(i >> 1 & 1), float64(i >> 2 & 1)})
+	}
+	return Mesh3[float64]{Positions: positions, Indices: Vec3ConvexHull(positions)}
+}
+
+func meshFacesOutward(m Mesh3[float64], centre Vec3[float64]) bool {
+	for i, n := range m.FaceNormals() {
+		tri := m.Indices[i]
+		mid := m.Positions[tri[0]].Plus(m.Positions[tri[1]]).Plus(m.Positions[tri[2]]).ScaledBy(1.0 / 3)
+		if mid.Minus(centre).Dot(n) <= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func TestMesh3Normals(t *testing.T) {
+	cube := unitCube()
+	if len(cube.Indices) != 12 || !meshFacesOutward(cube, Vec3[float64]{0.5, 0.5, 0.5}) {
+		t.Fatalf("expected an outward facing cube, got: %v", cube.Indices)
+	}
+	for _, n := range cube.FaceNormals() {
+		if !floatIdentical(1, math.Abs(n.X)+math.Abs(n.Y)+math.Abs(n.Z)) || !floatIdentical(1, n.Len()) {
+			t.Errorf("expected an axis normal, got: %v", n)
+		}
+	}
+
+	// weighted by area
+	m := Mesh3[float64]{
+		Positions: []Vec3[float64]{{0, 0, 0}, {1, 0, 0}, {0, 2, 0}, {0, 3, 0}, {0, 0, 2}},
+		Indices:   [][3]int{{0, 1, 2}, {0, 3, 4}},
+	}
+	expected := []Vec3[float64]{Vec3[float64]{6, 0, 2}.Normal(), {0, 0, 1}, {0, 0, 1}, {1, 0, 0}, {1, 0, 0}}
+	for i, n := range m.VertexNormals() {
+		if !vec3Identical(expected[i], n) {
+			t.Errorf("position %v, expected: %v, got: %v", i, expected[i], n)
+		}
+	}
+	if smooth := m.Smooth(); len(smooth.Normals) != 5 || len(m.Normals) != 0 {
+		t.Errorf("expected normals on a copy, got: %v", smooth.Normals)
+	}
+
+	flat := cube.Flat()
+	if len(flat.Positions) != 36 || len(flat.Normals) != 36 || len(flat.Indices) != 12 {
+		t.Fatalf("expected separate positions, got: %v %v", len(flat.Positions), len(flat.Normals))
+	}
+	faceNormals := cube.FaceNormals()
+	for i, tri := range flat.Indices {
+		for j, v := range tri {
+			if !vec3Identical(faceNormals[i], flat.Normals[v]) || !vec3Identical(cube.Positions[cube.Indices[i][j]], flat.Positions[v]) {
+				t.Errorf("triangle %v, expected face normal and matching position", i)
+			}
+		}
+	}
+}
+
+func TestMesh3Bounds(t *testing.T) {
+	cube := unitCube()
+	if expected := CuboidOrigin(1.0, 1.0, 1.0); !cuboidIdentical(expected, cube.Bounds()) {
+		t.Errorf("expected: %v, got: %v", expected, cube.Bounds())
+	}
+}
+
+func TestMesh3Transformed(t *testing.T) {
+	cube := unitCube().Smooth()
+
+	for _, mat := range []Mat4[float64]{
+		Mat4Translation(Vec3[float64]{1, 2, 3}).Product(Mat4RotationZ(0.3)).Product(Mat4Scalar(2.0, 0.5, 3)),
+		Mat4Scalar(-1.0, 1, 1),
+		Mat4RotationX(1.0).Product(Mat4Scalar(1.0, -4, 0.25)),
+	} {
+		transformed := cube.Transformed(mat)
+		centre := mat.TransformVec3(Vec3[float64]{0.5, 0.5, 0.5}, 1)
+
+		for i, p := range cube.Positions {
+			if expected := mat.TransformVec3(p, 1); !vec3Identical(expected, transformed.Positions[i]) {
+				t.Errorf("expected: %v, got: %v", expected, transformed.Positions[i])
+			}
+		}
+		if !meshFacesOutward(transformed, centre) {
+			t.Errorf("expected triangles to face outwards, got: %v", transformed.Indices)
+		}
+
+		// transformed smooth normals still point away from the centre
+		for i, n := range transformed.Normals {
+			if !floatIdentical(1, n.Len()) || n.Dot(transformed.Positions[i].Minus(centre)) <= 0 {
+				t.Errorf("expected an outward unit normal, got: %v", n)
+			}
+		}
+
+		// face normals stay perpendicular to the faces
+		flat := unitCube().Flat().Transformed(mat)
+		for _, tri := range flat.Indices {
+			n := flat.Normals[tri[0]]
+			a, b, c := flat.Positions[tri[0]], flat.Positions[tri[1]], flat.Positions[tri[2]]
+			if !floatIdentical(0, n.Dot(b.Minus(a))) || !floatIdentical(0, n.Dot(c.Minus(a))) || n.Dot(b.Minus(a).Cross(c.Minus(a))) <= 0 {
+				t.Errorf("expected normal perpendicular to face, got: %v", n)
+			}
+		}
+	}
+}
+
+func TestMeshMerge(t *testing.T) {
+	a := unitCube().Smooth()
+	a.UVs = make([]Vec2[float64], len(a.Positions))
+	for i := range a.UVs {
+		a.UVs[i] = Vec2[float64]{1, 1}
+	}
+	b := unitCube().Transformed(Mat4Translation(Vec3[float64]{2, 0, 0}))
+
+	merged := Mesh3Merge(a, b)
+	if len(merged.Positions) != 16 || len(merged.Normals) != 16 || len(merged.UVs) != 16 || len(merged.Indices) != 24 {
+		t.Fatalf("expected combined meshes, got: %v %v %v %v", len(merged.Positions), len(merged.Normals), len(merged.UVs), len(merged.Indices))
+	}
+	for i, tri := range b.Indices {
+		for j := range tri {
+			if !vec3Identical(b.Positions[tri[j]], merged.Positions[merged.Indices[12+i][j]]) {
+				t.Errorf("expected offset indices")
+			}
+		}
+	}
+	if merged.Normals[8] != (Vec3[float64]{}) || merged.UVs[8] != (Vec2[float64]{}) || merged.UVs[0] != (Vec2[float64]{1, 1}) {
+		t.Errorf("expected zeroes for the mesh without normals and UVs")
+	}
+	if expected := (Cuboid[float64]{Vec3[float64]{0, 0, 0}, Vec3[float64]{3, 1, 1}}); !cuboidIdentical(expected, merged.Bounds()) {
+		t.Errorf("expected: %v, got: %v", expected, merged.Bounds())
+	}
+
+	if empty := Mesh3Merge[float64](); len(empty.Positions) != 0 || len(empty.Normals) != 0 {
+		t.Errorf("expected an empty mesh, got: %v", empty)
+	}
+}
+
+func TestMesh3Weld(t *testing.T) {
+	flat := unitCube().Flat()
+
+	// normals differ between faces
+	if welded := flat.Weld(1e-6); len(welded.Positions) != 24 || len(welded.Indices) != 12 {
+		t.Errorf("expected 4 positions per face, got: %v %v", len(welded.Positions), len(welded.Indices))
+	}
+
+	flat.Normals = nil
+	for i := range flat.Positions {
+		flat.Positions[i].PlusEquals(Vec3[float64]{1e-9 * float64(i), -2e-9 * float64(i), 0})
+	}
+	welded := flat.Weld(1e-6)
+	if len(welded.Positions) != 8 || len(welded.Indices) != 12 || !meshFacesOutward(welded, Vec3[float64]{0.5, 0.5, 0.5}) {
+		t.Errorf("expected the cube positions, got: %v %v", len(welded.Positions), len(welded.Indices))
+	}
+	if welded = flat.Weld(0); len(welded.Positions) != 36 {
+		t.Errorf("expected nothing to weld, got: %v", len(welded.Positions))
+	}
+
+	// triangles collapsing to a point are removed
+	if welded = flat.Weld(2); len(welded.Positions) != 1 || len(welded.Indices) != 0 {
+		t.Errorf("expected a single position, got: %v %v", len(welded.Positions), len(welded.Indices))
+	}
+}
+
+func TestMesh3WeldExactLarge(t *testing.T) {
+	// a 150x150 grid of quads with separate positions for each triangle
+	n := 150
+	grid := Mesh3[float64]{}
+	for y := 0; y <= n; y++ {
+		for x := 0; x <= n; x++ {
+			grid.Positions = append(grid.Positions, Vec3[float64]{float64(x) * 0.1, float64(y) * 0.1, 1e6})
+		}
+	}
+	for y := 0; y < n; y++ {
+		for x := 0; x < n; x++ {
+			i := y*(n+1) + x
+			grid.Indices = append(grid.Indices, [3]int{i, i + 1, i + n + 2}, [3]int{i, i + n + 2, i + n + 1})
+		}
+	}
+	flat := grid.Flat()
+	flat.Normals = nil
+
+	// welding is linear in the positions, quadratic would take many seconds
+	start := time.Now()
+	welded := flat.Weld(0)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected a fast weld of %v positions, took: %v", len(flat.Positions), elapsed)
+	}
+	if len(welded.Positions) != (n+1)*(n+1) || len(welded.Indices) != 2*n*n {
+		t.Errorf("expected the grid positions, got: %v %v", len(welded.Positions), len(welded.Indices))
+	}
+
+	// small tolerances don't overflow the grid either
+	start = time.Now()
+	if welded = flat.Weld(1e-300); len(welded.Positions) != (n+1)*(n+1) {
+		t.Errorf("expected the grid positions, got: %v", len(welded.Positions))
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected a fast weld, took: %v", elapsed)
+	}
+}
@@ -0,0 +1,242 @@
+package geomTest
+
+import (
+	"bytes"
+	"errors"
+	. "github.com/tadeuszjt/geom/generic"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func readObjFile(t *testing.T, name string) []ObjObject[float64] {
+	f, err := os.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	objects, err := ReadObj[float64](f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return objects
+}
+
+func TestReadObjCube(t *testing.T) {
+	objects := readObjFile(t, "testdata/cube.obj")
+	if len(objects) != 1 || objects[0].Name != "Cube" {
+		t.Fatalf("expected a single cube, got: %v", objects)
+	}
+
+	// each face has its own normal so corners aren't shared between faces
+	m := objects[0].Mesh
+	if len(m.Positions) != 24 || len(m.Normals) != 24 || len(m.UVs) != 24 || len(m.Indices) != 12 {
+		t.Fatalf("expected 24 corners and 12 triangles, got: %v %v %v %v", len(m.Positions), len(m.Normals), len(m.UVs), len(m.Indices))
+	}
+	if !meshFacesOutward(m, Vec3[float64]{0.5, 0.5, 0.5}) {
+		t.Errorf("expected outward facing triangles, got: %v", m.Indices)
+	}
+	for i, n := range m.FaceNormals() {
+		for _, v := range m.Indices[i] {
+			if !vec3Identical(n, m.Normals[v]) {
+				t.Errorf("triangle %v, expected normal: %v, got: %v", i, n, m.Normals[v])
+			}
+		}
+	}
+	if expected := CuboidOrigin(1.0, 1.0, 1.0); !cuboidIdentical(expected, m.Bounds()) {
+		t.Errorf("expected: %v, got: %v", expected, m.Bounds())
+	}
+}
+
+func TestReadObjShapes(t *testing.T) {
+	objects := readObjFile(t, "testdata/shapes.obj")
+	if len(objects) != 3 {
+		t.Fatalf("expected 3 objects, got: %v", len(objects))
+	}
+
+	// concave face triangulated facing +z
+	shape := objects[0]
+	if shape.Name != "L shape" || len(shape.Mesh.Positions) != 6 || len(shape.Mesh.Indices) != 4 {
+		t.Fatalf("expected a triangulated L shape, got: %v", shape)
+	}
+	if len(shape.Mesh.Normals) != 0 || len(shape.Mesh.UVs) != 0 {
+		t.Errorf("expected no normals or UVs")
+	}
+	area := 0.0
+	for _, n := range shape.Mesh.FaceNormals() {
+		if !vec3Identical(Vec3[float64]{0, 0, 1}, n) {
+			t.Errorf("expected normal facing +z, got: %v", n)
+		}
+	}
+	for _, tri := range shape.Mesh.Indices {
+		a, b, c := shape.Mesh.Positions[tri[0]], shape.Mesh.Positions[tri[1]], shape.Mesh.Positions[tri[2]]
+		area += b.Minus(a).Cross(c.Minus(a)).Len() / 2
+	}
+	if !floatIdentical(3, area) {
+		t.Errorf("expected area: 3, got: %v", area)
+	}
+
+	path := objects[1]
+	expected := []Vec3[float64]{{0, 0, 1}, {1, 0, 1}, {1, 1, 1}}
+	if path.Name != "path" || len(path.Mesh.Indices) != 0 || len(path.Lines) != 1 || len(path.Lines[0]) != 3 {
+		t.Fatalf("expected a single polyline, got: %v", path)
+	}
+	for i, v := range path.Lines[0] {
+		if !vec3Identical(expected[i], v) {
+			t.Errorf("expected: %v, got: %v", expected[i], v)
+		}
+	}
+
+	tri := objects[2]
+	if tri.Name != "tri" || len(tri.Mesh.Positions) != 3 || len(tri.Mesh.Normals) != 3 || len(tri.Mesh.Indices) != 1 {
+		t.Fatalf("expected a triangle with normals, got: %v", tri)
+	}
+	if !vec3Identical(Vec3[float64]{5, 6, 5}, tri.Mesh.Positions[2]) || !vec3Identical(Vec3[float64]{0, 0, 1}, tri.Mesh.Normals[0]) {
+		t.Errorf("expected relative indices from the end, got: %v %v", tri.Mesh.Positions, tri.Mesh.Normals)
+	}
+}
+
+func TestWriteObj(t *testing.T) {
+	objects := readObjFile(t, "testdata/shapes.obj")
+	objects = append(objects, readObjFile(t, "testdata/cube.obj")...)
+	objects[1].Mesh = objects[3].Mesh.Transformed(Mat4RotationZ(0.1)) // faces and lines in one object
+
+	var buf bytes.Buffer
+	if err := WriteObj(&buf, objects); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadObj[float64](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read) != len(objects) {
+		t.Fatalf("expected %v objects, got: %v", len(objects), len(read))
+	}
+	for i, o := range objects {
+		r := read[i]
+		if o.Name != r.Name || len(o.Mesh.Positions) != len(r.Mesh.Positions) || len(o.Mesh.Normals) != len(r.Mesh.Normals) ||
+			len(o.Mesh.UVs) != len(r.Mesh.UVs) || len(o.Lines) != len(r.Lines) || len(o.Mesh.Indices) != len(r.Mesh.Indices) {
+			t.Fatalf("object %v, expected: %v, got: %v", i, o, r)
+		}
+
+		// corners may be renumbered but floats are exact
+		for j, tri := range o.Mesh.Indices {
+			for k, v := range tri {
+				w := r.Mesh.Indices[j][k]
+				if o.Mesh.Positions[v] != r.Mesh.Positions[w] {
+					t.Errorf("object %v, expected: %v, got: %v", i, o.Mesh.Positions[v], r.Mesh.Positions[w])
+				}
+				if len(o.Mesh.Normals) > 0 && o.Mesh.Normals[v] != r.Mesh.Normals[w] {
+					t.Errorf("object %v, expected: %v, got: %v", i, o.Mesh.Normals[v], r.Mesh.Normals[w])
+				}
+				if len(o.Mesh.UVs) > 0 && o.Mesh.UVs[v] != r.Mesh.UVs[w] {
+					t.Errorf("object %v, expected: %v, got: %v", i, o.Mesh.UVs[v], r.Mesh.UVs[w])
+				}
+			}
+		}
+		for j, line := range o.Lines {
+			for k, v := range line {
+				if v != r.Lines[j][k] {
+					t.Errorf("object %v, expected: %v, got: %v", i, v, r.Lines[j][k])
+				}
+			}
+		}
+	}
+}
+
+func TestWriteObjFloat32(t *testing.T) {
+	objects := []ObjObject[float32]{{
+		Name: "a",
+		Mesh: Mesh3[float32]{
+			Positions: []Vec3[float32]{{0.1, 0.2, 0.3}, {1.0 / 3, 0, 0}, {0, 1e-7, 0}},
+			Indices:   [][3]int{{0, 1, 2}},
+		},
+	}}
+
+	var buf bytes.Buffer
+	if err := WriteObj(&buf, objects); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "v 0.1 0.2 0.3\n") {
+		t.Errorf("expected the shortest float32 digits, got: %q", buf.String())
+	}
+
+	read, err := ReadObj[float32](&buf)
+	if err != nil || len(read) != 1 {
+		t.Fatalf("expected one object, got: %v %v", read, err)
+	}
+	for i, v := range objects[0].Mesh.Positions {
+		if v != read[0].Mesh.Positions[i] {
+			t.Errorf("expected: %v, got: %v", v, read[0].Mesh.Positions[i])
+		}
+	}
+}
+
+func TestReadObjErrors(t *testing.T) {
+	cases := []struct {
+		text string
+		line int
+		msg  string
+	}{
+		{"v 1 2 x", 1, `invalid number "x"`},
+		{"v 1 2", 1, "expected 3 to 4 numbers, got 2"},
+		{"vn 0 0 1 1", 1, "expected 3 numbers, got 4"},
+		{"v 0 0 0\nv 1 0 0\nv 0 1 0\nf 0 1 2", 4, "position index must not be 0"},
+		{"v 0 0 0\nv 1 0 0\nv 0 1 0\n\nf 1 2 4", 5, "position index 4 out of range, there are 3"},
+		{"v 0 0 0\nv 1 0 0\nv 0 1 0\nf -4 1 2", 4, "position index -4 out of range, there are 3"},
+		{"v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1/1 2/1 3/1", 4, "uv index 1 out of range, there are 0"},
+		{"v 0 0 0\nv 1 0 0\nf 1 2", 3, "face needs at least 3 verts, got 2"},
+		{"v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 /3", 4, `invalid vert "/3"`},
+		{"v 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3//1/1", 4, `invalid vert "3//1/1"`},
+		{"v 0 0 0\nv 1 0 0\nl 1 b", 3, `invalid index "b"`},
+		{"v 0 0 0\nl 1", 2, "line needs at least 2 verts, got 1"},
+	}
+
+	for _, c := range cases {
+		_, err := ReadObj[float64](strings.NewReader(c.text))
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%q, expected a ParseError, got: %v", c.text, err)
+			continue
+		}
+		if parseErr.Format != "obj" || parseErr.Line != c.line || parseErr.Msg != c.msg {
+			t.Errorf("%q, expected: line %v: %v, got: %v", c.text, c.line, c.msg, err)
+		}
+	}
+
+	if objects, err := ReadObj[float64](strings.NewReader("# nothing\nv 1 2 3\n")); err != nil || len(objects) != 0 {
+		t.Errorf("expected no objects, got: %v %v", objects, err)
+	}
+
+	// reader errors are reported at the line that failed
+	broken := io.MultiReader(strings.NewReader("v 0 0 0\n"), iotest.ErrReader(errors.New("broken")))
+	var parseErr *ParseError
+	if _, err := ReadObj[float64](broken); !errors.As(err, &parseErr) || parseErr.Line != 2 || parseErr.Msg != "broken" {
+		t.Errorf("expected: line 2: broken, got: %v", err)
+	}
+}
+
+func TestWriteObjUnnamed(t *testing.T) {
+	objects := []ObjObject[float64]{{Mesh: unitCube()}, {Mesh: unitCube().Transformed(Mat4Translation(Vec3[float64]{2, 0, 0}))}}
+
+	var buf bytes.Buffer
+	if err := WriteObj(&buf, objects); err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadObj[float64](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != 2 || read[0].Name != "" || read[1].Name != "" {
+		t.Fatalf("expected 2 unnamed objects, got: %v", read)
+	}
+	for i, o := range objects {
+		if len(read[i].Mesh.Indices) != len(o.Mesh.Indices) || !meshCornersEqual(o.Mesh, read[i].Mesh) {
+			t.Errorf("object %v, expected: %v, got: %v", i, o.Mesh, read[i].Mesh)
+		}
+	}
+}
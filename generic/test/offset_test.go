@@ -0,0 +1,105 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func ringDistance(ring Poly[float64], v Vec2[float64]) float64 {
+	closed := append(PolyCopy(ring), ring[0])
+	return polylineDistance(closed, v)
+}
+
+func TestPolyOffset(t *testing.T) {
+	box := []Poly[float64]{square(0, 0, 10)}
+	holed := []Poly[float64]{square(0, 0, 10), square(3, 3, 4)}
+	dumbbell := []Poly[float64]{{{0, 0}, {4, 0}, {4, 1.5}, {6, 1.5}, {6, 0}, {10, 0}, {10, 4}, {6, 4}, {6, 2.5}, {4, 2.5}, {4, 4}, {0, 4}}}
+
+	cases := []struct {
+		region []Poly[float64]
+		delta  float64
+		style  OffsetStyle[float64]
+		area   float64
+		rings  int
+	}{
+		{box, 1, OffsetStyle[float64]{Join: StrokeJoinRound}, 140 + math.Pi, 1},
+		{box, 1, OffsetStyle[float64]{Join: StrokeJoinMiter, MiterLimit: 2}, 144, 1},
+		{box, 1, OffsetStyle[float64]{}, 144, 1}, // default limit of 4
+		{box, 1, OffsetStyle[float64]{Join: StrokeJoinMiter, MiterLimit: 1.2}, 142, 1},
+		{box, 1, OffsetStyle[float64]{Join: StrokeJoinBevel}, 142, 1},
+		{box, 1, OffsetStyle[float64]{Join: StrokeJoinSquare}, 140 + 4*(1-(2-math.Sqrt2)*(2-math.Sqrt2)/2), 1},
+		{box, -1, OffsetStyle[float64]{Join: StrokeJoinRound}, 64, 1},
+		{box, -5, OffsetStyle[float64]{Join: StrokeJoinRound}, 0, 0},
+		{box, 0, OffsetStyle[float64]{}, 100, 1},
+		{holed, 1, OffsetStyle[float64]{Join: StrokeJoinMiter, MiterLimit: 2}, 144 - 4, 2},
+		{holed, -1, OffsetStyle[float64]{Join: StrokeJoinMiter, MiterLimit: 2}, 64 - 36, 2},
+		{holed, 2.5, OffsetStyle[float64]{Join: StrokeJoinMiter, MiterLimit: 2}, 225, 1},
+		{dumbbell, -0.75, OffsetStyle[float64]{Join: StrokeJoinMiter, MiterLimit: 2}, 2 * 2.5 * 2.5, 2},
+		{dumbbell, 1, OffsetStyle[float64]{Join: StrokeJoinMiter, MiterLimit: 2}, 12 * 6, 1},
+	}
+
+	for i, c := range cases {
+		rings := PolyOffset(c.region, c.delta, c.style)
+		if len(rings) != c.rings {
+			t.Errorf("case %v, expected %v rings, got: %v", i, c.rings, rings)
+		}
+		if area := ringsArea(rings); math.Abs(area-c.area) > 0.01*math.Max(c.area, 1) {
+			t.Errorf("case %v, expected area: %v, got: %v", i, c.area, area)
+		}
+	}
+}
+
+func TestPolyOffsetRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for n := 0; n < 20; n++ {
+		// star shaped and usually concave
+		poly := Poly[float64]{}
+		for i := 0; i < 12; i++ {
+			theta := 2 * math.Pi * float64(i) / 12
+			radius := 3 + 5*r.Float64()
+			poly = append(poly, Vec2[float64]{radius * math.Cos(theta), radius * math.Sin(theta)})
+		}
+
+		for _, delta := range []float64{0.7, -0.4} {
+			rings := PolyOffset([]Poly[float64]{poly}, delta, OffsetStyle[float64]{Join: StrokeJoinRound, Tolerance: 0.01})
+			if len(rings) == 0 {
+				t.Fatalf("expected rings")
+			}
+
+			for _, ring := range rings {
+				for _, v := range ring {
+					if d := ringDistance(poly, v); math.Abs(d-math.Abs(delta)) > 0.01+1e-9 {
+						t.Fatalf("delta: %v, expected %v to be offset from the polygon, got: %v", delta, v, d)
+					}
+					if inside := poly.Contains(v); inside != (delta < 0) {
+						t.Fatalf("delta: %v, expected %v on the other side", delta, v)
+					}
+				}
+			}
+
+			for i := 0; i < 200; i++ {
+				v := Vec2[float64]{r.Float64()*20 - 10, r.Float64()*20 - 10}
+				d := ringDistance(poly, v)
+				if math.Abs(d-math.Abs(delta)) < 0.02 {
+					continue
+				}
+				expected := poly.Contains(v) || d < delta
+				if delta < 0 {
+					expected = poly.Contains(v) && d > -delta
+				}
+				if ringsContain(rings, v) != expected {
+					t.Fatalf("delta: %v, expected %v contained: %v", delta, v, expected)
+				}
+			}
+		}
+	}
+}
+
+func TestPolyOffsetFloat32(t *testing.T) {
+	rings := PolyOffset([]Poly[float32]{{{0, 0}, {10, 0}, {10, 10}, {0, 10}}}, 1, OffsetStyle[float32]{Join: StrokeJoinMiter, MiterLimit: 2})
+	if len(rings) != 1 || math.Abs(float64(rings[0].Area())-144) > 1e-3 {
+		t.Errorf("expected: %v, got: %v", 144, rings)
+	}
+}
@@ -185,3 +185,148 @@ func TestOri2Dot(t *testing.T) {
 		}
 	}
 }
+
+func TestOri2Compose(t *testing.T) {
+	cases := []struct {
+		a, b, result Ori2[float64]
+	}{
+		{Ori2[float64]{}, Ori2[float64]{}, Ori2[float64]{}},
+		{Ori2[float64]{1, 2, 0}, Ori2[float64]{3, 4, 0.5}, Ori2[float64]{4, 6, 0.5}},
+		{Ori2[float64]{1, 2, math.Pi / 2}, Ori2[float64]{3, 4, 1}, Ori2[float64]{-3, 5, math.Pi/2 + 1}},
+		{Ori2[float64]{-2, 8, math.Pi}, Ori2[float64]{3, -2, -math.Pi}, Ori2[float64]{-5, 10, 0}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := c.a.Compose(c.b)
+		if !ori2Identical(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+
+		expectedMat := c.a.Mat3Transform().Product(c.b.Mat3Transform())
+		actualMat := actual.Mat3Transform()
+		if !mat3Identical(expectedMat, actualMat) {
+			t.Errorf("expected: %v, actual: %v", expectedMat, actualMat)
+		}
+	}
+}
+
+func TestOri2Inverse(t *testing.T) {
+	cases := []struct {
+		o, result Ori2[float64]
+	}{
+		{Ori2[float64]{}, Ori2[float64]{}},
+		{Ori2[float64]{1, 2, 0}, Ori2[float64]{-1, -2, 0}},
+		{Ori2[float64]{3, 4, math.Pi / 2}, Ori2[float64]{-4, 3, -math.Pi / 2}},
+		{Ori2[float64]{-2, 8, math.Pi}, Ori2[float64]{-2, 8, -math.Pi}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := c.o.Inverse()
+		if !ori2Identical(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+
+		identity := Ori2[float64]{}
+		if composed := c.o.Compose(actual); !ori2Identical(identity, composed) {
+			t.Errorf("expected: %v, actual: %v", identity, composed)
+		}
+	}
+}
+
+func TestOri2Between(t *testing.T) {
+	cases := []struct {
+		a, b, result Ori2[float64]
+	}{
+		{Ori2[float64]{}, Ori2[float64]{1, 2, 3}, Ori2[float64]{1, 2, 3}},
+		{Ori2[float64]{1, 2, 3}, Ori2[float64]{1, 2, 3}, Ori2[float64]{}},
+		{Ori2[float64]{1, 2, math.Pi / 2}, Ori2[float64]{1, 5, math.Pi}, Ori2[float64]{3, 0, math.Pi / 2}},
+		{Ori2[float64]{-1, 3, 0.3}, Ori2[float64]{4, -2, -1.2}, Ori2[float64]{3.2990814, -6.2542835, -1.5}},
+	}
+
+	for _, c := range cases {
+		actual := c.a.Between(c.b)
+		if !ori2Identical(c.b, c.a.Compose(actual)) {
+			t.Errorf("expected: %v, actual: %v", c.b, c.a.Compose(actual))
+		}
+		if !ori2Identical(c.result, actual) {
+			t.Errorf("expected: %v, actual: %v", c.result, actual)
+		}
+	}
+}
+
+func TestOri2TransformVec2(t *testing.T) {
+	cases := []struct {
+		o             Ori2[float64]
+		local, result Vec2[float64]
+	}{
+		{Ori2[float64]{}, Vec2[float64]{1, 2}, Vec2[float64]{1, 2}},
+		{Ori2[float64]{1, 2, 0}, Vec2[float64]{3, 4}, Vec2[float64]{4, 6}},
+		{Ori2[float64]{3, 4, math.Pi / 2}, Vec2[float64]{1, 2}, Vec2[float64]{1, 5}},
+		{Ori2[float64]{-2, 8, math.Pi}, Vec2[float64]{3, -2}, Vec2[float64]{-5, 10}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := c.o.TransformVec2(c.local)
+		if !vec2Identical(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+
+		if back := c.o.InverseTransformVec2(actual); !vec2Identical(c.local, back) {
+			t.Errorf("expected: %v, actual: %v", c.local, back)
+		}
+
+		if mat := c.o.Mat3Transform().TimesVec2(c.local, 1); !vec2Identical(expected, mat) {
+			t.Errorf("expected: %v, actual: %v", expected, mat)
+		}
+	}
+}
+
+func TestOri2Exp(t *testing.T) {
+	cases := []struct {
+		twist, result Ori2[float64]
+	}{
+		{Ori2[float64]{}, Ori2[float64]{}},
+		{Ori2[float64]{1, 2, 0}, Ori2[float64]{1, 2, 0}},
+		{Ori2[float64]{1, 0, math.Pi / 2}, Ori2[float64]{2 / math.Pi, 2 / math.Pi, math.Pi / 2}},
+		{Ori2[float64]{math.Pi, 0, math.Pi}, Ori2[float64]{0, 2, math.Pi}},
+		{Ori2[float64]{0, 0, 1}, Ori2[float64]{0, 0, 1}},
+		{Ori2[float64]{1, 0, 1e-9}, Ori2[float64]{1, 5e-10, 1e-9}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := Ori2Exp(c.twist)
+		if !ori2Identical(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+}
+
+func TestOri2Log(t *testing.T) {
+	cases := []Ori2[float64]{
+		{},
+		{1, 2, 0},
+		{2 / math.Pi, 2 / math.Pi, math.Pi / 2},
+		{-3, 0.5, -2},
+		{1, -1, 1e-7},
+		{0, 2, math.Pi},
+	}
+
+	for _, c := range cases {
+		expected := c
+		actual := Ori2Exp(c.Log())
+		if !ori2Identical(expected, actual) {
+			t.Errorf("expected: %v, actual: %v", expected, actual)
+		}
+	}
+
+	// theta is wrapped to the shortest arc
+	expected := Ori2[float64]{0, 0, -math.Pi / 2}
+	actual := Ori2[float64]{0, 0, 3 * math.Pi / 2}.Log()
+	if !ori2Identical(expected, actual) {
+		t.Errorf("expected: %v, actual: %v", expected, actual)
+	}
+}
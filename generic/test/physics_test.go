@@ -0,0 +1,243 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func centredBox(w, h float64) Poly[float64] {
+	return Poly[float64]{{-w / 2, -h / 2}, {w / 2, -h / 2}, {w / 2, h / 2}, {-w / 2, h / 2}}
+}
+
+func TestNewBody2(t *testing.T) {
+	// either winding, away from the origin
+	body := NewBody2(Poly[float64]{{0, 0}, {0, 1}, {2, 1}, {2, 0}}, 3, Ori2[float64]{1, 1, math.Pi / 2})
+
+	if !floatIdentical(6, body.Mass) {
+		t.Errorf("expected mass: %v, got: %v", 6, body.Mass)
+	}
+	if expected := 6 * (4 + 1) / 12.0; !floatIdentical(expected, body.Inertia) {
+		t.Errorf("expected inertia: %v, got: %v", expected, body.Inertia)
+	}
+	if expected := (Ori2[float64]{0.5, 2, math.Pi / 2}); !ori2Identical(expected, body.Pose) {
+		t.Errorf("expected pose: %v, got: %v", expected, body.Pose)
+	}
+	if !vec2Identical(Vec2[float64]{}, body.Shape.Centroid()) || body.Shape.Area() <= 0 {
+		t.Errorf("expected a clockwise shape about the origin, got: %v", body.Shape)
+	}
+
+	expected := Poly[float64]{{1, 1}, {0, 1}, {0, 3}, {1, 3}}
+	actual := body.WorldShape()
+	for _, v := range expected {
+		found := false
+		for _, a := range actual {
+			found = found || vec2Identical(v, a)
+		}
+		if !found {
+			t.Errorf("expected world shape to have vertex: %v, got: %v", v, actual)
+		}
+	}
+
+	static := NewStaticBody2(centredBox(1, 1), Ori2[float64]{})
+	if !static.Static() || body.Static() {
+		t.Errorf("expected only the static body to be static")
+	}
+}
+
+func TestBody2ApplyImpulse(t *testing.T) {
+	body := NewBody2(centredBox(2, 2), 1, Ori2[float64]{1, 1, 0})
+	body.ApplyImpulse(Vec2[float64]{0, 4}, Vec2[float64]{2, 1})
+
+	expected := Ori2[float64]{0, 1, 4 / body.Inertia}
+	if !ori2Identical(expected, body.Velocity) {
+		t.Errorf("expected: %v, got: %v", expected, body.Velocity)
+	}
+
+	velocity := body.VelocityAt(Vec2[float64]{2, 1})
+	if expectedV := (Vec2[float64]{0, 1 + 4/body.Inertia}); !vec2Identical(expectedV, velocity) {
+		t.Errorf("expected: %v, got: %v", expectedV, velocity)
+	}
+}
+
+func TestWorld2FreeFall(t *testing.T) {
+	w := NewWorld2(Vec2[float64]{0, 10}, 1.0/60)
+	body := NewBody2(centredBox(1, 1), 1, Ori2[float64]{})
+	w.Add(body)
+
+	if steps := w.Update(1.0 + 1e-9); steps != 60 {
+		t.Errorf("expected 60 steps, got: %v", steps)
+	}
+
+	// semi-implicit Euler
+	expectedY := 10.0 / 60 / 60 * (60 * 61 / 2)
+	if !floatIdentical(10, body.Velocity.Y) || !floatIdentical(expectedY, body.Pose.Y) {
+		t.Errorf("expected: %v %v, got: %v %v", 10, expectedY, body.Velocity.Y, body.Pose.Y)
+	}
+}
+
+func TestWorld2Update(t *testing.T) {
+	w := NewWorld2(Vec2[float64]{}, 0.25)
+	for _, c := range []struct {
+		elapsed float64
+		steps   int
+	}{
+		{0.1, 0}, {0.1, 0}, {0.1, 1}, {0.5, 2}, {0.24, 1}, {1, 4},
+	} {
+		if steps := w.Update(c.elapsed); steps != c.steps {
+			t.Errorf("elapsed: %v, expected: %v, got: %v", c.elapsed, c.steps, steps)
+		}
+	}
+}
+
+func TestWorld2Invalid(t *testing.T) {
+	expectPanic(t, "World2.Update: Timestep must be positive", func() { (&World2[float64]{}).Update(1) })
+	expectPanic(t, "World2.Update: Timestep must be positive", func() { NewWorld2(Vec2[float64]{}, -1.0).Update(1) })
+
+	w := NewWorld2(Vec2[float64]{}, 1.0/60)
+	expectPanic(t, "World2.Add: shape has no area", func() { w.Add(&Body2[float64]{Shape: Poly[float64]{{0, 0}, {1, 0}, {2, 0}}}) })
+	expectPanic(t, "World2.Add: shape has no area", func() { w.Add(&Body2[float64]{}) })
+	if len(w.Bodies) != 0 {
+		t.Errorf("expected no bodies, got: %v", len(w.Bodies))
+	}
+}
+
+func newGroundWorld() *World2[float64] {
+	w := NewWorld2(Vec2[float64]{0, 10}, 1.0/60)
+	w.Add(NewStaticBody2(centredBox(20, 1), Ori2[float64]{0, 10.5, 0}))
+	return w
+}
+
+func TestWorld2Resting(t *testing.T) {
+	w := newGroundWorld()
+	box := NewBody2(centredBox(1, 1), 1, Ori2[float64]{0, 5, 0})
+	w.Add(box)
+	ground := *w.Bodies[0]
+
+	w.Update(4)
+
+	if math.Abs(box.Pose.Y-9.5) > w.Slop*2 {
+		t.Errorf("expected box to rest at: %v, got: %v", 9.5, box.Pose.Y)
+	}
+	if box.Velocity.Vec2().Len() > 1e-3 || math.Abs(box.Velocity.Theta) > 1e-3 || math.Abs(box.Pose.Theta) > 1e-3 {
+		t.Errorf("expected box to be at rest, got: %v %v", box.Pose, box.Velocity)
+	}
+	if !ori2Identical(ground.Pose, w.Bodies[0].Pose) {
+		t.Errorf("expected ground not to move, got: %v", w.Bodies[0].Pose)
+	}
+}
+
+func TestWorld2Stack(t *testing.T) {
+	w := newGroundWorld()
+	for i := 0; i < 5; i++ {
+		w.Add(NewBody2(centredBox(1, 1), 1, Ori2[float64]{0.05 * float64(i%2), 9.5 - float64(i)*1.05, 0}))
+	}
+
+	w.Update(6)
+
+	for i, b := range w.Bodies[1:] {
+		expected := 9.5 - float64(i)
+		if math.Abs(b.Pose.Y-expected) > 0.1 || math.Abs(b.Pose.Theta) > 0.01 || b.Velocity.Vec2().Len() > 0.01 {
+			t.Errorf("box %v, expected at rest at: %v, got: %v %v", i, expected, b.Pose, b.Velocity)
+		}
+	}
+}
+
+func TestWorld2Restitution(t *testing.T) {
+	for _, restitution := range []float64{0, 0.5} {
+		w := newGroundWorld()
+		box := NewBody2(centredBox(1, 1), 1, Ori2[float64]{0, 5, 0})
+		box.Restitution = restitution
+		w.Add(box)
+
+		impact := math.Sqrt(2 * 10 * 4.5)
+		bounce := 0.0
+		for i := 0; i < 120; i++ {
+			w.Step()
+			bounce = math.Max(bounce, -box.Velocity.Y)
+		}
+
+		if expected := restitution * impact; math.Abs(bounce-expected) > 0.1*impact {
+			t.Errorf("restitution: %v, expected bounce: %v, got: %v", restitution, expected, bounce)
+		}
+	}
+}
+
+func TestWorld2Friction(t *testing.T) {
+	for _, friction := range []float64{0, 0.5} {
+		w := newGroundWorld()
+		w.Bodies[0].Friction = friction
+		box := NewBody2(centredBox(1, 1), 1, Ori2[float64]{-5, 9.5, 0})
+		box.Friction = friction
+		box.Velocity.X = 4
+		w.Add(box)
+
+		w.Update(0.5)
+
+		switch {
+		case friction == 0 && !floatIdentical(4, box.Velocity.X):
+			t.Errorf("expected no friction, got: %v", box.Velocity)
+		case friction > 0 && box.Velocity.X >= 4-0.5*10*0.5+0.1:
+			t.Errorf("expected friction to slow the box, got: %v", box.Velocity)
+		}
+
+		w.Update(1.5)
+		if friction > 0 && math.Abs(box.Velocity.X) > 1e-3 {
+			t.Errorf("expected the box to stop, got: %v", box.Velocity)
+		}
+	}
+}
+
+func TestWorld2Momentum(t *testing.T) {
+	w := NewWorld2(Vec2[float64]{}, 1.0/60)
+	a := NewBody2(centredBox(1, 1), 1, Ori2[float64]{0, 0, 0})
+	b := NewBody2(centredBox(1, 2), 2, Ori2[float64]{3, 0.3, 0.2})
+	a.Friction, b.Friction = 0, 0
+	a.Velocity = Ori2[float64]{4, 0, 0}
+	b.Velocity = Ori2[float64]{-1, 0, 0}
+	w.Add(a)
+	w.Add(b)
+
+	momentum := func() Vec2[float64] {
+		return a.Velocity.Vec2().ScaledBy(a.Mass).Plus(b.Velocity.Vec2().ScaledBy(b.Mass))
+	}
+	expected := momentum()
+
+	collided := false
+	for i := 0; i < 120; i++ {
+		w.Step()
+		collided = collided || a.Velocity.X < 4
+	}
+
+	if !collided {
+		t.Errorf("expected a collision")
+	}
+	if actual := momentum(); !vec2Identical(expected, actual) {
+		t.Errorf("expected momentum: %v, got: %v", expected, actual)
+	}
+}
+
+func TestWorld2Deterministic(t *testing.T) {
+	run := func() []Ori2[float64] {
+		w := newGroundWorld()
+		for i := 0; i < 6; i++ {
+			body := NewBody2(centredBox(1, 0.5+0.1*float64(i)), 1, Ori2[float64]{float64(i) * 0.3, 8 - float64(i), float64(i) * 0.4})
+			body.Restitution = 0.2
+			w.Add(body)
+		}
+		w.Update(3)
+
+		poses := []Ori2[float64]{}
+		for _, b := range w.Bodies {
+			poses = append(poses, b.Pose, b.Velocity)
+		}
+		return poses
+	}
+
+	expected, actual := run(), run()
+	for i := range expected {
+		if expected[i] != actual[i] {
+			t.Fatalf("expected: %v, got: %v", expected[i], actual[i])
+		}
+	}
+}
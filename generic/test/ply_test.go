@@ -0,0 +1,193 @@
+package geomTest
+
+import (
+	"bytes"
+	"errors"
+	. "github.com/tadeuszjt/geom/generic"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadPly(t *testing.T) {
+	f, err := os.Open("testdata/square.ply")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	m, err := ReadPly[float64](f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mesh := m.Mesh
+	if len(mesh.Positions) != 6 || len(mesh.Normals) != 6 || len(mesh.UVs) != 0 || len(m.Colours) != 6 || len(mesh.Indices) != 4 {
+		t.Fatalf("expected 6 positions and 4 triangles, got: %v %v %v %v %v",
+			len(mesh.Positions), len(mesh.Normals), len(mesh.UVs), len(m.Colours), len(mesh.Indices))
+	}
+	if m.Colours[0] != [4]uint8{255, 0, 0, 255} || m.Colours[5] != [4]uint8{40, 50, 60, 255} {
+		t.Errorf("expected opaque colours, got: %v", m.Colours)
+	}
+
+	// the quad is triangulated facing the same way as the triangles
+	area := 0.0
+	for i, n := range mesh.FaceNormals() {
+		if !vec3Identical(Vec3[float64]{0, 0, 1}, n) {
+			t.Errorf("triangle %v, expected normal facing +z, got: %v", i, n)
+		}
+		a, b, c := mesh.Positions[mesh.Indices[i][0]], mesh.Positions[mesh.Indices[i][1]], mesh.Positions[mesh.Indices[i][2]]
+		area += b.Minus(a).Cross(c.Minus(a)).Len() / 2
+	}
+	if !floatIdentical(2, area) {
+		t.Errorf("expected area: 2, got: %v", area)
+	}
+}
+
+func TestReadPlyElementOrder(t *testing.T) {
+	vertexHeader := "element vertex 4\nproperty float x\nproperty float y\nproperty float z\n"
+	faceHeader := "element face 2\nproperty list uchar int vertex_indices\n"
+	verts := "0 0 0\n2 0 0\n2 1 0\n0 1 0\n"
+	faces := "4 0 1 2 3\n3 0 2 3\n"
+
+	for _, text := range []string{
+		"ply\nformat ascii 1.0\n" + vertexHeader + faceHeader + "end_header\n" + verts + faces,
+		"ply\nformat ascii 1.0\n" + faceHeader + vertexHeader + "end_header\n" + faces + verts,
+	} {
+		m, err := ReadPly[float64](strings.NewReader(text))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(m.Mesh.Positions) != 4 || len(m.Mesh.Indices) != 3 {
+			t.Fatalf("expected 4 positions and 3 triangles, got: %v %v", len(m.Mesh.Positions), len(m.Mesh.Indices))
+		}
+		if m.Mesh.Indices[2] != [3]int{0, 2, 3} {
+			t.Errorf("expected faces in file order, got: %v", m.Mesh.Indices)
+		}
+		for i, n := range m.Mesh.FaceNormals() {
+			if !vec3Identical(Vec3[float64]{0, 0, 1}, n) {
+				t.Errorf("triangle %v, expected normal facing +z, got: %v", i, n)
+			}
+		}
+	}
+}
+
+func TestWritePly(t *testing.T) {
+	cube := unitCube().Smooth().Transformed(Mat4RotationY(0.3))
+	cube.UVs = make([]Vec2[float64], len(cube.Positions))
+	colours := make([][4]uint8, len(cube.Positions))
+	for i := range cube.UVs {
+		cube.UVs[i] = Vec2[float64]{float64(i) / 7, 1 - float64(i)/3}
+		colours[i] = [4]uint8{uint8(i), uint8(255 - i), 128, uint8(i * 30)}
+	}
+
+	for _, format := range []PlyFormat{PlyAscii, PlyBinaryLittleEndian, PlyBinaryBigEndian} {
+		for _, m := range []PlyMesh[float64]{{Mesh: cube, Colours: colours}, {Mesh: unitCube()}} {
+			var buf bytes.Buffer
+			if err := WritePly(&buf, m, format); err != nil {
+				t.Fatal(err)
+			}
+			read, err := ReadPly[float64](&buf)
+			if err != nil {
+				t.Fatalf("format: %v, %v", format, err)
+			}
+
+			if len(read.Mesh.Positions) != len(m.Mesh.Positions) || !meshCornersEqual(m.Mesh, read.Mesh) {
+				t.Errorf("format: %v, expected the same mesh, got: %v", format, read.Mesh)
+			}
+			if len(read.Colours) != len(m.Colours) {
+				t.Fatalf("format: %v, expected %v colours, got: %v", format, len(m.Colours), len(read.Colours))
+			}
+			for i, c := range m.Colours {
+				if c != read.Colours[i] {
+					t.Errorf("format: %v, expected: %v, got: %v", format, c, read.Colours[i])
+				}
+			}
+		}
+	}
+
+	expectPanic(t, "WritePly: normals, UVs and colours must have one entry per position", func() {
+		WritePly(&bytes.Buffer{}, PlyMesh[float64]{Mesh: cube, Colours: colours[:3]}, PlyAscii)
+	})
+}
+
+func TestWritePlyFloat32(t *testing.T) {
+	m := PlyMesh[float32]{Mesh: Mesh3[float32]{
+		Positions: []Vec3[float32]{{0.1, 0.2, 0.3}, {1.0 / 3, 0, 0}, {0, 1e-7, 0}},
+		Indices:   [][3]int{{0, 1, 2}},
+	}}
+
+	for _, format := range []PlyFormat{PlyAscii, PlyBinaryBigEndian} {
+		var buf bytes.Buffer
+		if err := WritePly(&buf, m, format); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), "property float x\n") {
+			t.Errorf("expected float properties, got: %q", buf.String())
+		}
+		if format == PlyAscii && !strings.Contains(buf.String(), "\n0.1 0.2 0.3\n") {
+			t.Errorf("expected the shortest float32 digits, got: %q", buf.String())
+		}
+		read, err := ReadPly[float32](&buf)
+		if err != nil || !meshCornersEqual(m.Mesh, read.Mesh) {
+			t.Errorf("format: %v, expected the same mesh, got: %v %v", format, read.Mesh, err)
+		}
+	}
+}
+
+func TestReadPlyErrors(t *testing.T) {
+	header := "ply\nformat ascii 1.0\nelement vertex 3\nproperty float x\nproperty float y\nproperty float z\n" +
+		"element face 1\nproperty list uchar int vertex_indices\nend_header\n"
+	verts := "0 0 0\n1 0 0\n0 1 0\n"
+	cases := []struct {
+		text string
+		line int
+		msg  string
+	}{
+		{"plx\n", 1, `expected "ply"`},
+		{"ply\nformat ascii 1.0\nelement vertex 1\n", 3, "missing end_header"},
+		{"ply\nformat text 1.0\nend_header\n", 2, `unknown format "text"`},
+		{"ply\nend_header\n", 2, "missing format"},
+		{"ply\nformat ascii 1.0\nproperty float x\n", 3, "property before element"},
+		{"ply\nformat ascii 1.0\nelement vertex -1\n", 3, `invalid element count "-1"`},
+		{"ply\nformat ascii 1.0\nelement vertex 1\nproperty real x\n", 4, `unknown type "real"`},
+		{"ply\nformat ascii 1.0\nelement vertex 1\nproperty list uchar x\n", 4, "invalid property"},
+		{"ply\nformat ascii 1.0\nelement vertex 1\nproperty float x\nend_header\n0\n", 0, "vertex element has no x, y and z properties"},
+		{"ply\nformat ascii 1.0\nelement face 1\nproperty int x\nend_header\n0\n", 0, "face element has no vertex_indices list"},
+		{"ply\nformat ascii 1.0\nfoo\n", 3, `unexpected "foo"`},
+		{header + "0 0 0\n1 0 x\n", 11, `invalid number "x"`},
+		{header + "0 0 0\n1 0 0 1\n", 11, "vertex 1 has too many values"},
+		{header + "0 0 0\n1 0 0\n", 11, "data ends in vertex 2 of 3"},
+		{header + verts + "3 0 1 3\n", 13, "face 0 vertex index 3 out of range, there are 3"},
+		{header + verts + "2 0 1\n", 13, "face 0 has 2 vertices, expected at least 3"},
+		{header + verts + "3.5 0 1 2\n", 13, "face 0 has invalid list length 3.5"},
+	}
+
+	var parseErr *ParseError
+	for _, c := range cases {
+		_, err := ReadPly[float64](strings.NewReader(c.text))
+		if !errors.As(err, &parseErr) || parseErr.Format != "ply" || parseErr.Line != c.line || parseErr.Msg != c.msg {
+			t.Errorf("%q, expected: line %v: %v, got: %v", c.text, c.line, c.msg, err)
+		}
+	}
+
+	// reader errors are reported at the line that failed
+	broken := io.MultiReader(strings.NewReader(header+"0 0 0\n"), iotest.ErrReader(errors.New("broken")))
+	if _, err := ReadPly[float64](broken); !errors.As(err, &parseErr) || parseErr.Line != 11 || parseErr.Msg != "broken" {
+		t.Errorf("expected: line 11: broken, got: %v", err)
+	}
+	if _, err := ReadPly[float64](iotest.ErrReader(errors.New("broken"))); !errors.As(err, &parseErr) || parseErr.Line != 1 || parseErr.Msg != "broken" {
+		t.Errorf("expected: line 1: broken, got: %v", err)
+	}
+	broken = io.MultiReader(strings.NewReader(strings.Replace(header, "ascii", "binary_little_endian", 1)), iotest.ErrReader(errors.New("broken")))
+	if _, err := ReadPly[float64](broken); !errors.As(err, &parseErr) || parseErr.Line != 0 || parseErr.Msg != "broken" {
+		t.Errorf("expected: broken, got: %v", err)
+	}
+
+	// binary data has no line numbers
+	binary := strings.Replace(header, "ascii", "binary_little_endian", 1) + "\x00\x00\x00\x00"
+	if _, err := ReadPly[float64](strings.NewReader(binary)); !errors.As(err, &parseErr) || parseErr.Line != 0 || parseErr.Msg != "data ends in vertex 0 of 3" {
+		t.Errorf("expected a truncated error, got: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func randRect(r *rand.Rand, bounds Rect[float64], maxSize float64) Rect[float64] {
+	x := bounds.Min.X + r.Float64()*bounds.Width()
+	y := bounds.Min.Y + r.Float64()*bounds.Height()
+	return MakeRect(x, y, r.Float64()*maxSize, r.Float64()*maxSize)
+}
+
+func rectDist(r Rect[float64], v Vec2[float64]) float64 {
+	return r.Clamp(v).Minus(v).Len()
+}
+
+func quadtreeQuery(q *Quadtree[float64, int], r Rect[float64]) []int {
+	ids := []int{}
+	q.Query(r, func(id int, payload int) bool {
+		if id != payload {
+			panic("payload mismatch")
+		}
+		ids = append(ids, id)
+		return true
+	})
+	sort.Ints(ids)
+	return ids
+}
+
+func linearQuery(items map[int]Rect[float64], r Rect[float64]) []int {
+	ids := []int{}
+	for id, b := range items {
+		if b.Intersects(r) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids
+}
+
+func intsEqual(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQuadtree(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	world := MakeRect[float64](-100, -100, 200, 200)
+	q := NewQuadtree[float64, int](world)
+	items := map[int]Rect[float64]{}
+
+	check := func() {
+		if q.Len() != len(items) {
+			t.Fatalf("expected len: %v, got: %v", len(items), q.Len())
+		}
+		for i := 0; i < 50; i++ {
+			query := randRect(r, world.Outset(20), 40)
+			expected := linearQuery(items, query)
+			actual := quadtreeQuery(q, query)
+			if !intsEqual(expected, actual) {
+				t.Fatalf("query: %v, expected: %v, got: %v", query, expected, actual)
+			}
+		}
+	}
+
+	insert := func(b Rect[float64]) {
+		id := q.Insert(b, 0)
+		q.Update(id, id)
+		items[id] = b
+	}
+
+	for i := 0; i < 2000; i++ {
+		insert(randRect(r, world, 10))
+	}
+	for i := 0; i < 20; i++ { // partly or wholly outside the bounds
+		insert(randRect(r, world.Outset(50), 60))
+	}
+	check()
+
+	for id := range items {
+		if id%3 == 0 {
+			if !q.Remove(id) {
+				t.Fatalf("failed to remove: %v", id)
+			}
+			delete(items, id)
+		}
+	}
+	if q.Remove(0) {
+		t.Errorf("removed twice")
+	}
+	check()
+
+	for id := range items {
+		if id%2 == 0 {
+			b := randRect(r, world, 10)
+			if id%4 == 0 { // small moves stay in the same node
+				b = items[id]
+				b.Min.X += 0.1
+				b.Max.X += 0.1
+			}
+			if !q.Move(id, b) {
+				t.Fatalf("failed to move: %v", id)
+			}
+			items[id] = b
+		}
+	}
+	check()
+
+	for i := 0; i < 500; i++ { // reuses removed ids
+		insert(randRect(r, world, 10))
+	}
+	check()
+
+	for id, b := range items {
+		actual, payload, ok := q.Get(id)
+		if !ok || payload != id || !rectIdentical(b, actual) {
+			t.Fatalf("get: %v, expected: %v, got: %v %v %v", id, b, actual, payload, ok)
+		}
+	}
+
+	for id := range items {
+		q.Remove(id)
+		delete(items, id)
+	}
+	check()
+}
+
+func TestQuadtreeQueryPoint(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	world := MakeRect[float64](0, 0, 100, 100)
+	q := NewQuadtree[float64, int](world)
+	items := map[int]Rect[float64]{}
+	for i := 0; i < 1000; i++ {
+		b := randRect(r, world, 15)
+		id := q.Insert(b, i)
+		items[id] = b
+	}
+
+	for i := 0; i < 200; i++ {
+		v := Vec2[float64]{r.Float64() * 100, r.Float64() * 100}
+		expected := []int{}
+		for id, b := range items {
+			if b.Contains(v) {
+				expected = append(expected, id)
+			}
+		}
+		sort.Ints(expected)
+
+		actual := []int{}
+		q.QueryPoint(v, func(id int, payload int) bool {
+			actual = append(actual, id)
+			return true
+		})
+		sort.Ints(actual)
+
+		if !intsEqual(expected, actual) {
+			t.Fatalf("point: %v, expected: %v, got: %v", v, expected, actual)
+		}
+	}
+
+	count := 0
+	q.Query(world, func(id int, payload int) bool {
+		count++
+		return count < 5
+	})
+	if count != 5 {
+		t.Errorf("expected early stop after 5, got: %v", count)
+	}
+}
+
+func TestQuadtreeNearest(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	world := MakeRect[float64](0, 0, 100, 100)
+	q := NewQuadtree[float64, int](world)
+	items := map[int]Rect[float64]{}
+	for i := 0; i < 1000; i++ {
+		b := randRect(r, world, 3)
+		id := q.Insert(b, i)
+		items[id] = b
+	}
+	outside := MakeRect[float64](150, 150, 1, 1)
+	items[q.Insert(outside, -1)] = outside
+
+	for i := 0; i < 100; i++ {
+		v := Vec2[float64]{r.Float64()*200 - 50, r.Float64()*200 - 50}
+		k := 1 + r.Intn(20)
+
+		dists := []float64{}
+		for _, b := range items {
+			dists = append(dists, rectDist(b, v))
+		}
+		sort.Float64s(dists)
+
+		actual := q.Nearest(v, k)
+		if len(actual) != k {
+			t.Fatalf("expected %v results, got: %v", k, len(actual))
+		}
+		for j, id := range actual {
+			if d := rectDist(items[id], v); !floatIdentical(dists[j], d) {
+				t.Fatalf("point: %v, rank: %v, expected: %v, got: %v", v, j, dists[j], d)
+			}
+		}
+	}
+
+	if actual := q.Nearest(Vec2[float64]{150, 150}, 1); len(actual) != 1 || !rectIdentical(items[actual[0]], outside) {
+		t.Errorf("expected the item outside the bounds, got: %v", actual)
+	}
+	if actual := q.Nearest(Vec2[float64]{}, 5000); len(actual) != len(items) {
+		t.Errorf("expected: %v, got: %v", len(items), len(actual))
+	}
+}
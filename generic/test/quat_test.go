@@ -0,0 +1,237 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func quatIdentical(a, b Quat[float64]) bool {
+	return floatIdentical(a.X, b.X) &&
+		floatIdentical(a.Y, b.Y) &&
+		floatIdentical(a.Z, b.Z) &&
+		floatIdentical(a.W, b.W)
+}
+
+/* q and -q represent the same rotation */
+func quatSameRotation(a, b Quat[float64]) bool {
+	return quatIdentical(a, b) || quatIdentical(a, b.ScaledBy(-1))
+}
+
+func TestQuatAxisAngle(t *testing.T) {
+	cases := []struct {
+		axis   Vec3[float64]
+		rad    float64
+		result Mat4[float64]
+	}{
+		{Vec3[float64]{1, 0, 0}, 0, Mat4Identity[float64]()},
+		{Vec3[float64]{1, 0, 0}, math.Pi / 4, Mat4RotationX[float64](math.Pi / 4)},
+		{Vec3[float64]{0, 2, 0}, -3 * math.Pi / 4, Mat4RotationY[float64](-3 * math.Pi / 4)},
+		{Vec3[float64]{0, 0, 1}, 1.2, Mat4RotationZ[float64](1.2)},
+		{Vec3[float64]{0, 0, -1}, 1.2, Mat4RotationZ[float64](-1.2)},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := QuatAxisAngle(c.axis, c.rad).Mat4()
+		if !mat4Identical(expected, actual) {
+			t.Errorf("axis: %v, rad: %v, expected: %v, got: %v", c.axis, c.rad, expected, actual)
+		}
+	}
+}
+
+func TestQuatAxisAngleRoundTrip(t *testing.T) {
+	cases := []struct {
+		axis Vec3[float64]
+		rad  float64
+	}{
+		{Vec3[float64]{1, 0, 0}, 0.5},
+		{Vec3[float64]{0, 1, 0}, 3},
+		{Vec3[float64]{1, 2, 3}.Normal(), 1.7},
+		{Vec3[float64]{-1, 0, 1}.Normal(), 5},
+	}
+
+	for _, c := range cases {
+		axis, rad := QuatAxisAngle(c.axis, c.rad).AxisAngle()
+		if !vec3Identical(c.axis, axis) || !floatIdentical(c.rad, rad) {
+			t.Errorf("expected: %v %v, got: %v %v", c.axis, c.rad, axis, rad)
+		}
+	}
+
+	axis, rad := QuatIdentity[float64]().AxisAngle()
+	if !vec3Identical(Vec3[float64]{1, 0, 0}, axis) || rad != 0 {
+		t.Errorf("identity, got: %v %v", axis, rad)
+	}
+}
+
+func TestQuatRollPitchYaw(t *testing.T) {
+	cases := []struct {
+		r, p, y float64
+	}{
+		{0, 0, 0},
+		{math.Pi / 4, 0, 0},
+		{0, math.Pi / 4, 0},
+		{0, 0, math.Pi / 4},
+		{math.Pi / 2, math.Pi / 4, math.Pi / 2},
+		{-math.Pi / 2, -math.Pi / 2, math.Pi / 4},
+		{0.3, -1.2, 2.5},
+	}
+
+	for _, c := range cases {
+		expected := Mat4RollPitchYaw(c.r, c.p, c.y)
+		actual := QuatRollPitchYaw(c.r, c.p, c.y).Mat4()
+		if !mat4Identical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func TestQuatProduct(t *testing.T) {
+	cases := []struct {
+		a, b Quat[float64]
+	}{
+		{QuatIdentity[float64](), QuatIdentity[float64]()},
+		{QuatRotationX[float64](0.4), QuatRotationY[float64](1.1)},
+		{QuatRollPitchYaw[float64](1, 2, 3), QuatAxisAngle(Vec3[float64]{1, 1, -1}, 2.2)},
+	}
+
+	for _, c := range cases {
+		expected := c.a.Mat4().Product(c.b.Mat4())
+		actual := c.a.Product(c.b).Mat4()
+		if !mat4Identical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func TestQuatInverse(t *testing.T) {
+	cases := []Quat[float64]{
+		QuatIdentity[float64](),
+		QuatRollPitchYaw[float64](0.3, -1.2, 2.5),
+		{1, 2, 3, 4},
+	}
+
+	for _, q := range cases {
+		actual := q.Product(q.Inverse())
+		if !quatIdentical(QuatIdentity[float64](), actual) {
+			t.Errorf("q: %v, expected identity, got: %v", q, actual)
+		}
+	}
+
+	if actual := (Quat[float64]{}).Inverse(); !quatIdentical(Quat[float64]{}, actual) {
+		t.Errorf("expected zero, got: %v", actual)
+	}
+}
+
+func TestQuatConjugate(t *testing.T) {
+	expected := Quat[float64]{-1, -2, -3, 4}
+	actual := Quat[float64]{1, 2, 3, 4}.Conjugate()
+	if !quatIdentical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestQuatNormal(t *testing.T) {
+	cases := []struct {
+		q, result Quat[float64]
+	}{
+		{Quat[float64]{}, Quat[float64]{}},
+		{Quat[float64]{0, 0, 0, 2}, QuatIdentity[float64]()},
+		{Quat[float64]{1, 1, 1, 1}, Quat[float64]{.5, .5, .5, .5}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := c.q.Normal()
+		if !quatIdentical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func TestQuatRotateVec3(t *testing.T) {
+	cases := []struct {
+		q Quat[float64]
+		v Vec3[float64]
+	}{
+		{QuatIdentity[float64](), Vec3[float64]{1, 2, 3}},
+		{QuatRotationZ[float64](math.Pi / 2), Vec3[float64]{1, 0, 0}},
+		{QuatRollPitchYaw[float64](0.3, -1.2, 2.5), Vec3[float64]{-4, 5, 0.5}},
+	}
+
+	for _, c := range cases {
+		expected := c.q.Mat4().TransformVec3(c.v, 1)
+		actual := c.q.RotateVec3(c.v)
+		if !vec3Identical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+
+	expected := Vec3[float64]{0, 1, 0}
+	actual := QuatRotationZ[float64](math.Pi / 2).RotateVec3(Vec3[float64]{1, 0, 0})
+	if !vec3Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestQuatFromMat4(t *testing.T) {
+	cases := []Quat[float64]{
+		QuatIdentity[float64](),
+		QuatRotationX[float64](math.Pi),
+		QuatRotationY[float64](math.Pi),
+		QuatRotationZ[float64](math.Pi),
+		QuatRotationY[float64](3),
+		QuatRollPitchYaw[float64](0.3, -1.2, 2.5),
+		QuatAxisAngle(Vec3[float64]{1, -2, 0.5}, 3.1),
+	}
+
+	for _, q := range cases {
+		actual := QuatFromMat4(q.Mat4())
+		if !quatSameRotation(q, actual) {
+			t.Errorf("expected: %v, got: %v", q, actual)
+		}
+	}
+}
+
+func TestQuatSlerp(t *testing.T) {
+	axis := Vec3[float64]{1, 2, -1}
+	a := QuatAxisAngle(axis, 0.2)
+	b := QuatAxisAngle(axis, 1.8)
+
+	for _, f := range []float64{0, 0.25, 0.5, 0.75, 1} {
+		expected := QuatAxisAngle(axis, 0.2+1.6*f)
+		actual := a.Slerp(b, f)
+		if !quatSameRotation(expected, actual) {
+			t.Errorf("t: %v, expected: %v, got: %v", f, expected, actual)
+		}
+	}
+
+	// shortest path: -b is the same rotation as b
+	actual := a.Slerp(b.ScaledBy(-1), 0.5)
+	if !quatSameRotation(QuatAxisAngle(axis, 1.0), actual) {
+		t.Errorf("expected: %v, got: %v", QuatAxisAngle(axis, 1.0), actual)
+	}
+
+	// nearly identical inputs
+	c := QuatAxisAngle(axis, 0.2000001)
+	if actual := a.Slerp(c, 0.5); !quatSameRotation(a, actual) {
+		t.Errorf("expected: %v, got: %v", a, actual)
+	}
+}
+
+func TestQuatNlerp(t *testing.T) {
+	a := QuatRotationZ[float64](0)
+	b := QuatRotationZ[float64](1)
+
+	for _, f := range []float64{0, 0.5, 1} {
+		actual := a.Nlerp(b, f)
+		if !floatIdentical(1, actual.Len()) {
+			t.Errorf("expected unit length, got: %v", actual.Len())
+		}
+	}
+
+	// symmetric interpolation halves the angle
+	if actual := a.Nlerp(b, 0.5); !quatSameRotation(QuatRotationZ[float64](0.5), actual) {
+		t.Errorf("expected: %v, got: %v", QuatRotationZ[float64](0.5), actual)
+	}
+}
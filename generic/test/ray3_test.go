@@ -0,0 +1,230 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func ray3HitIdentical(a, b Ray3Hit[float64]) bool {
+	return floatIdentical(a.Dist, b.Dist) &&
+		vec3Identical(a.Point, b.Point) &&
+		vec3Identical(a.Normal, b.Normal)
+}
+
+func TestRay3IntersectTriangle(t *testing.T) {
+	p0 := Vec3[float64]{0, 0, 0}
+	p1 := Vec3[float64]{2, 0, 0}
+	p2 := Vec3[float64]{0, 2, 0}
+
+	cases := []struct {
+		r    Ray3[float64]
+		ok   bool
+		hit  Ray3Hit[float64]
+		u, v float64
+	}{
+		{
+			Ray3[float64]{Vec3[float64]{0.5, 0.5, 3}, Vec3[float64]{0, 0, -1}},
+			true,
+			Ray3Hit[float64]{3, Vec3[float64]{0.5, 0.5, 0}, Vec3[float64]{0, 0, 1}},
+			0.25, 0.25,
+		},
+		{ // from behind, distance in units of Dir
+			Ray3[float64]{Vec3[float64]{1, 0, -4}, Vec3[float64]{0, 0, 2}},
+			true,
+			Ray3Hit[float64]{2, Vec3[float64]{1, 0, 0}, Vec3[float64]{0, 0, 1}},
+			0.5, 0,
+		},
+		{ // on a vertex
+			Ray3[float64]{Vec3[float64]{0, 2, 1}, Vec3[float64]{0, 0, -1}},
+			true,
+			Ray3Hit[float64]{1, Vec3[float64]{0, 2, 0}, Vec3[float64]{0, 0, 1}},
+			0, 1,
+		},
+		{ // outside
+			Ray3[float64]{Vec3[float64]{1.5, 1.5, 1}, Vec3[float64]{0, 0, -1}},
+			false, Ray3Hit[float64]{}, 0, 0,
+		},
+		{ // pointing away
+			Ray3[float64]{Vec3[float64]{0.5, 0.5, 1}, Vec3[float64]{0, 0, 1}},
+			false, Ray3Hit[float64]{}, 0, 0,
+		},
+		{ // parallel
+			Ray3[float64]{Vec3[float64]{0.5, 0.5, 1}, Vec3[float64]{1, 0, 0}},
+			false, Ray3Hit[float64]{}, 0, 0,
+		},
+	}
+
+	for _, c := range cases {
+		hit, u, v, ok := c.r.IntersectTriangle(p0, p1, p2)
+		if ok != c.ok {
+			t.Errorf("r: %v, expected ok: %v, got: %v", c.r, c.ok, ok)
+			continue
+		}
+		if ok && (!ray3HitIdentical(c.hit, hit) || !floatIdentical(c.u, u) || !floatIdentical(c.v, v)) {
+			t.Errorf("r: %v, expected: %v %v %v, got: %v %v %v", c.r, c.hit, c.u, c.v, hit, u, v)
+		}
+	}
+}
+
+func TestRay3IntersectPlane(t *testing.T) {
+	cases := []struct {
+		r             Ray3[float64]
+		point, normal Vec3[float64]
+		ok            bool
+		hit           Ray3Hit[float64]
+	}{
+		{
+			Ray3[float64]{Vec3[float64]{1, 5, 1}, Vec3[float64]{0, -1, 0}},
+			Vec3[float64]{0, 2, 0}, Vec3[float64]{0, 3, 0},
+			true,
+			Ray3Hit[float64]{3, Vec3[float64]{1, 2, 1}, Vec3[float64]{0, 1, 0}},
+		},
+		{
+			Ray3[float64]{Vec3[float64]{0, 0, 0}, Vec3[float64]{1, 1, 0}},
+			Vec3[float64]{2, 0, 0}, Vec3[float64]{-1, 0, 0},
+			true,
+			Ray3Hit[float64]{2, Vec3[float64]{2, 2, 0}, Vec3[float64]{-1, 0, 0}},
+		},
+		{
+			Ray3[float64]{Vec3[float64]{0, 0, 0}, Vec3[float64]{1, 0, 0}},
+			Vec3[float64]{-2, 0, 0}, Vec3[float64]{1, 0, 0},
+			false, Ray3Hit[float64]{},
+		},
+		{
+			Ray3[float64]{Vec3[float64]{0, 0, 0}, Vec3[float64]{0, 1, 0}},
+			Vec3[float64]{2, 0, 0}, Vec3[float64]{1, 0, 0},
+			false, Ray3Hit[float64]{},
+		},
+	}
+
+	for _, c := range cases {
+		hit, ok := c.r.IntersectPlane(c.point, c.normal)
+		if ok != c.ok || (ok && !ray3HitIdentical(c.hit, hit)) {
+			t.Errorf("r: %v, expected: %v %v, got: %v %v", c.r, c.hit, c.ok, hit, ok)
+		}
+	}
+}
+
+func TestRay3IntersectSphere(t *testing.T) {
+	centre := Vec3[float64]{1, 1, 1}
+
+	cases := []struct {
+		r   Ray3[float64]
+		ok  bool
+		hit Ray3Hit[float64]
+	}{
+		{
+			Ray3[float64]{Vec3[float64]{1, 1, -5}, Vec3[float64]{0, 0, 1}},
+			true,
+			Ray3Hit[float64]{4, Vec3[float64]{1, 1, -1}, Vec3[float64]{0, 0, -1}},
+		},
+		{ // inside
+			Ray3[float64]{Vec3[float64]{1, 1, 1}, Vec3[float64]{0, 4, 0}},
+			true,
+			Ray3Hit[float64]{0.5, Vec3[float64]{1, 3, 1}, Vec3[float64]{0, 1, 0}},
+		},
+		{ // tangent
+			Ray3[float64]{Vec3[float64]{3, -5, 1}, Vec3[float64]{0, 1, 0}},
+			true,
+			Ray3Hit[float64]{6, Vec3[float64]{3, 1, 1}, Vec3[float64]{1, 0, 0}},
+		},
+		{ // behind
+			Ray3[float64]{Vec3[float64]{1, 1, 5}, Vec3[float64]{0, 0, 1}},
+			false, Ray3Hit[float64]{},
+		},
+		{
+			Ray3[float64]{Vec3[float64]{4, 1, -5}, Vec3[float64]{0, 0, 1}},
+			false, Ray3Hit[float64]{},
+		},
+	}
+
+	for _, c := range cases {
+		hit, ok := c.r.IntersectSphere(centre, 2)
+		if ok != c.ok || (ok && !ray3HitIdentical(c.hit, hit)) {
+			t.Errorf("r: %v, expected: %v %v, got: %v %v", c.r, c.hit, c.ok, hit, ok)
+		}
+	}
+}
+
+func TestRay3IntersectCuboid(t *testing.T) {
+	cuboid := Cuboid[float64]{Vec3[float64]{0, 0, 0}, Vec3[float64]{2, 4, 6}}
+
+	cases := []struct {
+		r    Ray3[float64]
+		ok   bool
+		hit  Ray3Hit[float64]
+		exit float64
+	}{
+		{
+			Ray3[float64]{Vec3[float64]{-1, 1, 1}, Vec3[float64]{1, 0, 0}},
+			true,
+			Ray3Hit[float64]{1, Vec3[float64]{0, 1, 1}, Vec3[float64]{-1, 0, 0}},
+			3,
+		},
+		{
+			Ray3[float64]{Vec3[float64]{1, 1, 10}, Vec3[float64]{0, 0, -2}},
+			true,
+			Ray3Hit[float64]{2, Vec3[float64]{1, 1, 6}, Vec3[float64]{0, 0, 1}},
+			5,
+		},
+		{ // diagonal through a corner region
+			Ray3[float64]{Vec3[float64]{-1, -2, 1}, Vec3[float64]{1, 1, 0}},
+			true,
+			Ray3Hit[float64]{2, Vec3[float64]{1, 0, 1}, Vec3[float64]{0, -1, 0}},
+			3,
+		},
+		{ // inside hits the exit
+			Ray3[float64]{Vec3[float64]{1, 1, 1}, Vec3[float64]{0, 1, 0}},
+			true,
+			Ray3Hit[float64]{3, Vec3[float64]{1, 4, 1}, Vec3[float64]{0, 1, 0}},
+			3,
+		},
+		{
+			Ray3[float64]{Vec3[float64]{-1, 5, 1}, Vec3[float64]{1, 0, 0}},
+			false, Ray3Hit[float64]{}, 0,
+		},
+		{
+			Ray3[float64]{Vec3[float64]{3, 1, 1}, Vec3[float64]{1, 0, 0}},
+			false, Ray3Hit[float64]{}, 0,
+		},
+		{
+			Ray3[float64]{Vec3[float64]{-1, -1, 1}, Vec3[float64]{1, 6, 0}},
+			false, Ray3Hit[float64]{}, 0,
+		},
+	}
+
+	for _, c := range cases {
+		hit, exit, ok := c.r.IntersectCuboid(cuboid)
+		if ok != c.ok || (ok && (!ray3HitIdentical(c.hit, hit) || !floatIdentical(c.exit, exit))) {
+			t.Errorf("r: %v, expected: %v %v %v, got: %v %v %v", c.r, c.hit, c.exit, c.ok, hit, exit, ok)
+		}
+	}
+}
+
+func TestRay3Unproject(t *testing.T) {
+	view := Mat4LookAt(Vec3[float64]{0, 0, 5}, Vec3[float64]{0, 0, 0}, Vec3[float64]{0, 1, 0})
+	proj := Mat4PerspectiveFov[float64](math.Pi/2, 1, 1, 100)
+	viewProj := proj.Product(view)
+
+	r, ok := Ray3Unproject(viewProj, Vec2[float64]{0, 0})
+	if !ok {
+		t.Fatalf("expected ok")
+	}
+	if !vec3Identical(Vec3[float64]{0, 0, 4}, r.Origin) || !vec3Identical(Vec3[float64]{0, 0, -1}, r.Dir) {
+		t.Errorf("expected ray down -Z from the near plane, got: %v", r)
+	}
+
+	// a picked point projects back to the same screen position
+	target := Vec3[float64]{1, -2, 0}
+	ndc := viewProj.TransformVec3(target, 1).Vec2()
+	r, _ = Ray3Unproject(viewProj, ndc)
+	hit, ok := r.IntersectPlane(Vec3[float64]{}, Vec3[float64]{0, 0, 1})
+	if !ok || !vec3Identical(target, hit.Point) {
+		t.Errorf("expected: %v, got: %v", target, hit.Point)
+	}
+
+	if _, ok := Ray3Unproject(Mat4[float64]{}, Vec2[float64]{}); ok {
+		t.Errorf("expected singular matrix to fail")
+	}
+}
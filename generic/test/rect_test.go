@@ -231,3 +231,132 @@ func TestRectConvert(t *testing.T) {
 		t.Errorf("expected: %v, got: %v", expected, actual)
 	}
 }
+
+func TestRectBounds(t *testing.T) {
+	cases := []struct {
+		points []Vec2[float64]
+		result Rect[float64]
+	}{
+		{[]Vec2[float64]{}, Rect[float64]{}},
+		{[]Vec2[float64]{{1, 2}}, Rect[float64]{Vec2[float64]{1, 2}, Vec2[float64]{1, 2}}},
+		{
+			[]Vec2[float64]{{1, 2}, {-3, 4}, {5, -6}},
+			Rect[float64]{Vec2[float64]{-3, -6}, Vec2[float64]{5, 4}},
+		},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		if actual := RectBounds(c.points); !rectIdentical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+		if actual := Poly[float64](c.points).Bounds(); !rectIdentical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func TestRectCentre(t *testing.T) {
+	expected := Vec2[float64]{1, 3}
+	actual := MakeRect[float64](-1, 2, 4, 2).Centre()
+	if !vec2Identical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestRectIntersection(t *testing.T) {
+	cases := []struct {
+		a, b   Rect[float64]
+		ok     bool
+		result Rect[float64]
+	}{
+		{MakeRect[float64](0, 0, 2, 2), MakeRect[float64](1, 1, 2, 2), true, MakeRect[float64](1, 1, 1, 1)},
+		{MakeRect[float64](0, 0, 4, 4), MakeRect[float64](1, 1, 1, 1), true, MakeRect[float64](1, 1, 1, 1)},
+		{MakeRect[float64](0, 0, 2, 2), MakeRect[float64](2, 0, 2, 2), true, MakeRect[float64](2, 0, 0, 2)},
+		{MakeRect[float64](0, 0, 2, 2), MakeRect[float64](3, 0, 2, 2), false, Rect[float64]{}},
+		{MakeRect[float64](0, 0, 2, 2), MakeRect[float64](0, -3, 2, 2), false, Rect[float64]{}},
+	}
+
+	for _, c := range cases {
+		if actual := c.a.Intersects(c.b); actual != c.ok {
+			t.Errorf("a: %v, b: %v, expected: %v, got: %v", c.a, c.b, c.ok, actual)
+		}
+		if actual := c.b.Intersects(c.a); actual != c.ok {
+			t.Errorf("a: %v, b: %v, expected: %v, got: %v", c.b, c.a, c.ok, actual)
+		}
+
+		actual, ok := c.a.Intersection(c.b)
+		if ok != c.ok || !rectIdentical(c.result, actual) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.result, c.ok, actual, ok)
+		}
+	}
+}
+
+func TestRectUnion(t *testing.T) {
+	expected := Rect[float64]{Vec2[float64]{-1, 0}, Vec2[float64]{5, 7}}
+	actual := MakeRect[float64](-1, 0, 2, 2).Union(MakeRect[float64](3, 4, 2, 3))
+	if !rectIdentical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
+
+func TestRectExpandToInclude(t *testing.T) {
+	r := MakeRect[float64](0, 0, 2, 2)
+	cases := []struct {
+		v      Vec2[float64]
+		result Rect[float64]
+	}{
+		{Vec2[float64]{1, 1}, r},
+		{Vec2[float64]{3, 1}, Rect[float64]{Vec2[float64]{0, 0}, Vec2[float64]{3, 2}}},
+		{Vec2[float64]{-1, -2}, Rect[float64]{Vec2[float64]{-1, -2}, Vec2[float64]{2, 2}}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		if actual := r.ExpandToInclude(c.v); !rectIdentical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
+
+func TestRectInset(t *testing.T) {
+	r := MakeRect[float64](0, 0, 4, 2)
+	cases := []struct {
+		margin float64
+		result Rect[float64]
+	}{
+		{0, r},
+		{0.5, Rect[float64]{Vec2[float64]{0.5, 0.5}, Vec2[float64]{3.5, 1.5}}},
+		{1.5, Rect[float64]{Vec2[float64]{1.5, 1}, Vec2[float64]{2.5, 1}}},
+		{-1, Rect[float64]{Vec2[float64]{-1, -1}, Vec2[float64]{5, 3}}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		if actual := r.Inset(c.margin); !rectIdentical(expected, actual) {
+			t.Errorf("margin: %v, expected: %v, got: %v", c.margin, expected, actual)
+		}
+		if actual := r.Outset(-c.margin); !rectIdentical(expected, actual) {
+			t.Errorf("margin: %v, expected: %v, got: %v", -c.margin, expected, actual)
+		}
+	}
+}
+
+func TestRectClamp(t *testing.T) {
+	r := MakeRect[float64](0, 0, 4, 2)
+	cases := []struct {
+		v, result Vec2[float64]
+	}{
+		{Vec2[float64]{1, 1}, Vec2[float64]{1, 1}},
+		{Vec2[float64]{-1, 1}, Vec2[float64]{0, 1}},
+		{Vec2[float64]{5, 3}, Vec2[float64]{4, 2}},
+		{Vec2[float64]{2, -7}, Vec2[float64]{2, 0}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		if actual := r.Clamp(c.v); !vec2Identical(expected, actual) {
+			t.Errorf("expected: %v, got: %v", expected, actual)
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func segment2Identical(a, b Segment2[float64]) bool {
+	return vec2Identical(a.A, b.A) && vec2Identical(a.B, b.B)
+}
+
+func TestSegment2Intersect(t *testing.T) {
+	cases := []struct {
+		a, b   Segment2[float64]
+		kind   IntersectKind
+		result Segment2[float64]
+	}{
+		{
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{2, 2}},
+			Segment2[float64]{Vec2[float64]{0, 2}, Vec2[float64]{2, 0}},
+			IntersectPoint,
+			Segment2[float64]{Vec2[float64]{1, 1}, Vec2[float64]{1, 1}},
+		},
+		{ // touching at an endpoint
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{2, 0}},
+			Segment2[float64]{Vec2[float64]{2, 0}, Vec2[float64]{3, 5}},
+			IntersectPoint,
+			Segment2[float64]{Vec2[float64]{2, 0}, Vec2[float64]{2, 0}},
+		},
+		{ // T junction
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{4, 0}},
+			Segment2[float64]{Vec2[float64]{1, 3}, Vec2[float64]{1, 0}},
+			IntersectPoint,
+			Segment2[float64]{Vec2[float64]{1, 0}, Vec2[float64]{1, 0}},
+		},
+		{
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 1}},
+			Segment2[float64]{Vec2[float64]{0, 3}, Vec2[float64]{3, 0}},
+			IntersectNone,
+			Segment2[float64]{},
+		},
+		{
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{2, 0}},
+			Segment2[float64]{Vec2[float64]{0, 1}, Vec2[float64]{2, 1}},
+			IntersectParallel,
+			Segment2[float64]{},
+		},
+		{ // collinear, not touching
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 0}},
+			Segment2[float64]{Vec2[float64]{2, 0}, Vec2[float64]{3, 0}},
+			IntersectParallel,
+			Segment2[float64]{},
+		},
+		{ // collinear, touching end to end
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 1}},
+			Segment2[float64]{Vec2[float64]{2, 2}, Vec2[float64]{1, 1}},
+			IntersectPoint,
+			Segment2[float64]{Vec2[float64]{1, 1}, Vec2[float64]{1, 1}},
+		},
+		{
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{4, 0}},
+			Segment2[float64]{Vec2[float64]{5, 0}, Vec2[float64]{2, 0}},
+			IntersectOverlap,
+			Segment2[float64]{Vec2[float64]{2, 0}, Vec2[float64]{4, 0}},
+		},
+		{ // contained, reversed
+			Segment2[float64]{Vec2[float64]{4, 4}, Vec2[float64]{0, 0}},
+			Segment2[float64]{Vec2[float64]{1, 1}, Vec2[float64]{2, 2}},
+			IntersectOverlap,
+			Segment2[float64]{Vec2[float64]{2, 2}, Vec2[float64]{1, 1}},
+		},
+		{ // degenerate segment on the other
+			Segment2[float64]{Vec2[float64]{1, 0}, Vec2[float64]{1, 0}},
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{2, 0}},
+			IntersectPoint,
+			Segment2[float64]{Vec2[float64]{1, 0}, Vec2[float64]{1, 0}},
+		},
+		{
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{2, 0}},
+			Segment2[float64]{Vec2[float64]{1, 1}, Vec2[float64]{1, 1}},
+			IntersectNone,
+			Segment2[float64]{},
+		},
+	}
+
+	for _, c := range cases {
+		actual, kind := c.a.Intersect(c.b)
+		if kind != c.kind || !segment2Identical(c.result, actual) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.kind, c.result, kind, actual)
+		}
+	}
+}
+
+func TestSegment2ClosestPoint(t *testing.T) {
+	s := Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{4, 0}}
+
+	cases := []struct {
+		v, closest Vec2[float64]
+		distance   float64
+		param      float64
+	}{
+		{Vec2[float64]{2, 3}, Vec2[float64]{2, 0}, 3, 0.5},
+		{Vec2[float64]{-3, 4}, Vec2[float64]{0, 0}, 5, -0.75},
+		{Vec2[float64]{7, -4}, Vec2[float64]{4, 0}, 5, 1.75},
+		{Vec2[float64]{1, 0}, Vec2[float64]{1, 0}, 0, 0.25},
+	}
+
+	for _, c := range cases {
+		if actual := s.ClosestPoint(c.v); !vec2Identical(c.closest, actual) {
+			t.Errorf("expected: %v, got: %v", c.closest, actual)
+		}
+		if actual := s.Distance(c.v); !floatIdentical(c.distance, actual) {
+			t.Errorf("expected: %v, got: %v", c.distance, actual)
+		}
+		if actual := s.Project(c.v); !floatIdentical(c.param, actual) {
+			t.Errorf("expected: %v, got: %v", c.param, actual)
+		}
+	}
+
+	point := Segment2[float64]{Vec2[float64]{1, 1}, Vec2[float64]{1, 1}}
+	if actual := point.Distance(Vec2[float64]{4, 5}); !floatIdentical(5, actual) {
+		t.Errorf("expected: 5, got: %v", actual)
+	}
+}
+
+func TestRay2IntersectSegment(t *testing.T) {
+	cases := []struct {
+		r      Ray2[float64]
+		s      Segment2[float64]
+		kind   IntersectKind
+		result Segment2[float64]
+	}{
+		{
+			Ray2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 0}},
+			Segment2[float64]{Vec2[float64]{3, -1}, Vec2[float64]{3, 1}},
+			IntersectPoint,
+			Segment2[float64]{Vec2[float64]{3, 0}, Vec2[float64]{3, 0}},
+		},
+		{ // behind the ray
+			Ray2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 0}},
+			Segment2[float64]{Vec2[float64]{-3, -1}, Vec2[float64]{-3, 1}},
+			IntersectNone,
+			Segment2[float64]{},
+		},
+		{
+			Ray2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 1}},
+			Segment2[float64]{Vec2[float64]{0, 1}, Vec2[float64]{1, 2}},
+			IntersectParallel,
+			Segment2[float64]{},
+		},
+		{
+			Ray2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{2, 0}},
+			Segment2[float64]{Vec2[float64]{-1, 0}, Vec2[float64]{5, 0}},
+			IntersectOverlap,
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{5, 0}},
+		},
+		{
+			Ray2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 0}},
+			Segment2[float64]{Vec2[float64]{-4, 0}, Vec2[float64]{-1, 0}},
+			IntersectParallel,
+			Segment2[float64]{},
+		},
+		{
+			Ray2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 0}},
+			Segment2[float64]{Vec2[float64]{-4, 0}, Vec2[float64]{0, 0}},
+			IntersectPoint,
+			Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{0, 0}},
+		},
+	}
+
+	for _, c := range cases {
+		actual, kind := c.r.IntersectSegment(c.s)
+		if kind != c.kind || !segment2Identical(c.result, actual) {
+			t.Errorf("r: %v, s: %v, expected: %v %v, got: %v %v", c.r, c.s, c.kind, c.result, kind, actual)
+		}
+	}
+
+	r := Ray2[float64]{Vec2[float64]{1, 1}, Vec2[float64]{0, 2}}
+	hit, _ := r.IntersectSegment(Segment2[float64]{Vec2[float64]{0, 7}, Vec2[float64]{2, 7}})
+	if actual := r.Project(hit.A); !floatIdentical(3, actual) {
+		t.Errorf("expected: 3, got: %v", actual)
+	}
+}
+
+func TestRay2ClosestPoint(t *testing.T) {
+	r := Ray2[float64]{Vec2[float64]{1, 1}, Vec2[float64]{1, 0}}
+
+	if actual := r.ClosestPoint(Vec2[float64]{5, 3}); !vec2Identical(Vec2[float64]{5, 1}, actual) {
+		t.Errorf("expected: %v, got: %v", Vec2[float64]{5, 1}, actual)
+	}
+	if actual := r.ClosestPoint(Vec2[float64]{-2, 5}); !vec2Identical(Vec2[float64]{1, 1}, actual) {
+		t.Errorf("expected: %v, got: %v", Vec2[float64]{1, 1}, actual)
+	}
+	if actual := r.Distance(Vec2[float64]{-2, 5}); !floatIdentical(5, actual) {
+		t.Errorf("expected: 5, got: %v", actual)
+	}
+}
+
+func TestLine2Intersect(t *testing.T) {
+	cases := []struct {
+		a, b   Line2[float64]
+		kind   IntersectKind
+		result Vec2[float64]
+	}{
+		{
+			Line2Through(Vec2[float64]{0, 0}, Vec2[float64]{1, 1}),
+			Line2Through(Vec2[float64]{5, 0}, Vec2[float64]{6, -1}),
+			IntersectPoint,
+			Vec2[float64]{2.5, 2.5},
+		},
+		{
+			Line2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 2}},
+			Line2[float64]{Vec2[float64]{1, 0}, Vec2[float64]{-2, -4}},
+			IntersectParallel,
+			Vec2[float64]{},
+		},
+		{
+			Line2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{1, 2}},
+			Line2[float64]{Vec2[float64]{2, 4}, Vec2[float64]{3, 6}},
+			IntersectOverlap,
+			Vec2[float64]{},
+		},
+	}
+
+	for _, c := range cases {
+		actual, kind := c.a.Intersect(c.b)
+		if kind != c.kind || !vec2Identical(c.result, actual) {
+			t.Errorf("a: %v, b: %v, expected: %v %v, got: %v %v", c.a, c.b, c.kind, c.result, kind, actual)
+		}
+	}
+}
+
+func TestLine2ClosestPoint(t *testing.T) {
+	l := Line2Through(Vec2[float64]{0, 0}, Vec2[float64]{2, 2})
+
+	if actual := l.ClosestPoint(Vec2[float64]{0, 2}); !vec2Identical(Vec2[float64]{1, 1}, actual) {
+		t.Errorf("expected: %v, got: %v", Vec2[float64]{1, 1}, actual)
+	}
+	if actual := l.ClosestPoint(Vec2[float64]{-4, -2}); !vec2Identical(Vec2[float64]{-3, -3}, actual) {
+		t.Errorf("expected: %v, got: %v", Vec2[float64]{-3, -3}, actual)
+	}
+	if actual := l.Distance(Vec2[float64]{0, 2}); !floatIdentical(math.Sqrt(2), actual) {
+		t.Errorf("expected: %v, got: %v", math.Sqrt(2), actual)
+	}
+	if actual := l.Project(Vec2[float64]{0, 2}); !floatIdentical(0.5, actual) {
+		t.Errorf("expected: 0.5, got: %v", actual)
+	}
+}
@@ -0,0 +1,147 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func selfIntersects(poly Poly[float64], closed bool) bool {
+	m := len(poly) - 1
+	if closed {
+		m = len(poly)
+	}
+	edge := func(i int) Segment2[float64] {
+		return Segment2[float64]{poly[i], poly[(i+1)%len(poly)]}
+	}
+
+	for i := 0; i < m; i++ {
+		for j := i + 1; j < m; j++ {
+			adjacent := j == i+1 || (closed && i == 0 && j == m-1)
+			_, kind := edge(i).Intersect(edge(j))
+			if kind == IntersectOverlap || (kind == IntersectPoint && !adjacent) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func randStar(r *rand.Rand, n int, minRadius, maxRadius float64) Poly[float64] {
+	poly := Poly[float64]{}
+	for i := 0; i < n; i++ {
+		theta := 2 * math.Pi * float64(i) / float64(n)
+		radius := minRadius + (maxRadius-minRadius)*r.Float64()
+		poly = append(poly, Vec2[float64]{radius * math.Cos(theta), radius * math.Sin(theta)})
+	}
+	return poly
+}
+
+func TestPolySimplify(t *testing.T) {
+	noisy := Poly[float64]{{0, 0}, {1, 0.1}, {2, -0.1}, {3, 0.05}, {4, 0}, {4, 4}}
+	cases := []struct {
+		poly     Poly[float64]
+		style    SimplifyStyle[float64]
+		expected Poly[float64]
+	}{
+		{noisy, SimplifyStyle[float64]{Method: SimplifyDouglasPeucker, Tolerance: 0.2}, Poly[float64]{{0, 0}, {4, 0}, {4, 4}}},
+		{noisy, SimplifyStyle[float64]{Method: SimplifyVisvalingam, Tolerance: 0.25}, Poly[float64]{{0, 0}, {4, 0}, {4, 4}}},
+		{noisy, SimplifyStyle[float64]{Method: SimplifyDouglasPeucker}, noisy},
+		{noisy, SimplifyStyle[float64]{Method: SimplifyVisvalingam, Tolerance: 100}, Poly[float64]{{0, 0}, {4, 4}}},
+		{noisy, SimplifyStyle[float64]{Method: SimplifyDouglasPeucker, Tolerance: 0.2, Closed: true}, Poly[float64]{{0, 0}, {4, 0}, {4, 4}}},
+		{noisy, SimplifyStyle[float64]{Method: SimplifyVisvalingam, Tolerance: 100, Closed: true}, Poly[float64]{{0, 0}, {4, 0}, {4, 4}}},
+		{
+			Poly[float64]{{0, 0}, {5, 0}, {10, 0}, {10, 5}, {10, 10}, {5, 10}, {0, 10}, {0, 5}, {0, 0}},
+			SimplifyStyle[float64]{Method: SimplifyVisvalingam, Tolerance: 1e-9, Closed: true},
+			square(0, 0, 10),
+		},
+		{Poly[float64]{{1, 1}, {1, 1}, {2, 2}}, SimplifyStyle[float64]{Tolerance: 10}, Poly[float64]{{1, 1}, {2, 2}}},
+		{Poly[float64]{}, SimplifyStyle[float64]{Tolerance: 10}, Poly[float64]{}},
+	}
+
+	for i, c := range cases {
+		if actual := c.poly.Simplify(c.style); !polyIdentical(c.expected, actual) {
+			t.Errorf("case %v, expected: %v, got: %v", i, c.expected, actual)
+		}
+	}
+}
+
+func TestPolySimplifyTolerance(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for n := 0; n < 20; n++ {
+		poly := randStar(r, 200, 5, 6)
+		for _, closed := range []bool{false, true} {
+			style := SimplifyStyle[float64]{Method: SimplifyDouglasPeucker, Tolerance: 0.5, Closed: closed}
+			simplified := poly.Simplify(style)
+			if len(simplified) >= len(poly) || len(simplified) < 3 {
+				t.Fatalf("expected fewer verts, got: %v", len(simplified))
+			}
+			if !vec2Identical(poly[0], simplified[0]) || (!closed && !vec2Identical(poly[len(poly)-1], simplified[len(simplified)-1])) {
+				t.Errorf("expected the ends to be kept")
+			}
+
+			line := simplified
+			if closed {
+				line = append(PolyCopy(simplified), simplified[0])
+			}
+			for _, v := range poly {
+				if d := polylineDistance(line, v); d > 0.5+1e-9 {
+					t.Fatalf("expected %v within tolerance, got: %v", v, d)
+				}
+			}
+
+			// larger tolerances remove more
+			style.Method = SimplifyVisvalingam
+			small, large := poly.Simplify(style), poly.Simplify(SimplifyStyle[float64]{Method: SimplifyVisvalingam, Tolerance: 2, Closed: closed})
+			if len(large) >= len(small) {
+				t.Errorf("expected larger area to remove more, got: %v %v", len(small), len(large))
+			}
+		}
+	}
+}
+
+func TestPolySimplifyTopology(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	crossed := map[SimplifyMethod]int{}
+
+	for n := 0; n < 4000; n++ {
+		closed := n%2 == 0
+		poly := Poly[float64]{}
+		for i := 0; i < 8; i++ {
+			poly = append(poly, Vec2[float64]{r.Float64() * 10, r.Float64() * 10})
+		}
+		if selfIntersects(poly, closed) {
+			continue
+		}
+
+		for _, method := range []SimplifyMethod{SimplifyDouglasPeucker, SimplifyVisvalingam} {
+			style := SimplifyStyle[float64]{Method: method, Tolerance: 2, Closed: closed}
+			if method == SimplifyVisvalingam {
+				style.Tolerance = 6
+			}
+			if selfIntersects(poly.Simplify(style), closed) {
+				crossed[method]++
+			}
+
+			style.PreserveTopology = true
+			if simplified := poly.Simplify(style); selfIntersects(simplified, closed) {
+				t.Fatalf("method: %v, closed: %v, expected no self-intersection, got: %v", method, closed, simplified)
+			}
+		}
+	}
+
+	// the cases tested would have crossed without preserving topology
+	if crossed[SimplifyDouglasPeucker] == 0 || crossed[SimplifyVisvalingam] == 0 {
+		t.Errorf("expected some simplifications to self-intersect, got: %v", crossed)
+	}
+}
+
+func TestPolySimplifyFloat32(t *testing.T) {
+	poly := Poly[float32]{{0, 0}, {1, 0.01}, {2, 0}, {2, 2}}
+	expected := Poly[float32]{{0, 0}, {2, 0}, {2, 2}}
+	actual := poly.Simplify(SimplifyStyle[float32]{Method: SimplifyDouglasPeucker, Tolerance: 0.1, PreserveTopology: true})
+	if len(actual) != len(expected) || actual[1] != expected[1] {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+}
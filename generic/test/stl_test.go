@@ -0,0 +1,176 @@
+package geomTest
+
+import (
+	"bytes"
+	"errors"
+	. "github.com/tadeuszjt/geom/generic"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+/* same triangles with exactly equal positions, normals and UVs at each corner */
+func meshCornersEqual[T Num](a, b Mesh3[T]) bool {
+	if len(a.Indices) != len(b.Indices) || (len(a.Normals) > 0) != (len(b.Normals) > 0) || (len(a.UVs) > 0) != (len(b.UVs) > 0) {
+		return false
+	}
+	for i, tri := range a.Indices {
+		for j, v := range tri {
+			w := b.Indices[i][j]
+			if a.Positions[v] != b.Positions[w] ||
+				(len(a.Normals) > 0 && a.Normals[v] != b.Normals[w]) ||
+				(len(a.UVs) > 0 && a.UVs[v] != b.UVs[w]) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestReadStlAscii(t *testing.T) {
+	f, err := os.Open("testdata/tetra.stl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	m, err := ReadStl[float64](f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Positions) != 12 || len(m.Normals) != 12 || len(m.Indices) != 4 {
+		t.Fatalf("expected separate facets, got: %v %v %v", len(m.Positions), len(m.Normals), len(m.Indices))
+	}
+	if !meshFacesOutward(m, Vec3[float64]{0.25, 0.25, 0.25}) {
+		t.Errorf("expected outward facing triangles, got: %v", m.Indices)
+	}
+
+	// the missing normal is computed
+	expected := []Vec3[float64]{{0, 0, -1}, {0, -1, 0}, {-1, 0, 0}, Vec3[float64]{1, 1, 1}.Normal()}
+	for i, tri := range m.Indices {
+		for _, v := range tri {
+			if !vec3Identical(expected[i], m.Normals[v]) {
+				t.Errorf("facet %v, expected: %v, got: %v", i, expected[i], m.Normals[v])
+			}
+		}
+	}
+
+	m.Normals = nil
+	if welded := m.Weld(1e-9); len(welded.Positions) != 4 || len(welded.Indices) != 4 {
+		t.Errorf("expected 4 shared positions, got: %v", len(welded.Positions))
+	}
+}
+
+func TestWriteStl(t *testing.T) {
+	cube := unitCube().Transformed(Mat4Translation(Vec3[float64]{-0.5, 2, 0.25}))
+
+	for _, binary := range []bool{false, true} {
+		var buf bytes.Buffer
+		var err error
+		if binary {
+			err = WriteStlBinary(&buf, cube)
+		} else {
+			err = WriteStl(&buf, cube, "cube")
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if binary != !strings.HasPrefix(buf.String(), "solid cube\n") {
+			t.Errorf("binary: %v, unexpected start: %q", binary, buf.String()[:10])
+		}
+		if binary && buf.Len() != 84+50*12 {
+			t.Errorf("expected 12 facets of 50 bytes, got: %v", buf.Len())
+		}
+
+		read, err := ReadStl[float64](&buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		normals := cube.FaceNormals()
+		for i, tri := range read.Indices {
+			for j, v := range tri {
+				if read.Positions[v] != cube.Positions[cube.Indices[i][j]] || !vec3Identical(normals[i], read.Normals[v]) {
+					t.Errorf("binary: %v, triangle %v, expected matching position and normal", binary, i)
+				}
+			}
+		}
+		if len(read.Indices) != 12 {
+			t.Errorf("binary: %v, expected 12 triangles, got: %v", binary, len(read.Indices))
+		}
+	}
+}
+
+func TestReadStlBinarySolid(t *testing.T) {
+	var buf bytes.Buffer
+	tri := Mesh3[float32]{Positions: []Vec3[float32]{{0, 0, 0}, {1, 0, 0}, {0, 1, 0}}, Indices: [][3]int{{0, 1, 2}}}
+	if err := WriteStlBinary(&buf, tri); err != nil {
+		t.Fatal(err)
+	}
+
+	// some exporters start binary headers with "solid"
+	data := buf.Bytes()
+	copy(data, "solid exported")
+	m, err := ReadStl[float32](bytes.NewReader(data))
+	if err != nil || !meshCornersEqual(tri, Mesh3[float32]{Positions: m.Positions, Indices: m.Indices}) {
+		t.Errorf("expected a binary triangle, got: %v %v", m, err)
+	}
+	if m.Normals[0] != (Vec3[float32]{0, 0, 1}) {
+		t.Errorf("expected normal: %v, got: %v", Vec3[float32]{0, 0, 1}, m.Normals[0])
+	}
+
+	var parseErr *ParseError
+	if _, err := ReadStl[float32](bytes.NewReader(data[:100])); !errors.As(err, &parseErr) || parseErr.Msg != "data ends after 0 of 1 triangles" || parseErr.Line != 0 {
+		t.Errorf("expected a truncated error, got: %v", err)
+	}
+	if _, err := ReadStl[float32](bytes.NewReader(data[:40])); !errors.As(err, &parseErr) || parseErr.Msg != "missing header" {
+		t.Errorf("expected a header error, got: %v", err)
+	}
+}
+
+func TestReadStlErrors(t *testing.T) {
+	facet := "solid a\nfacet normal 0 0 1\nouter loop\nvertex 0 0 0\nvertex 1 0 0\n"
+	cases := []struct {
+		text string
+		line int
+		msg  string
+	}{
+		{facet + "vertex 0 1 0\nendloop\nendfacet\nendsolid a\n", 0, ""},
+		{facet + "vertex 0 1\n", 6, "expected 3 numbers, got 2"},
+		{facet + "vertex 0 1 z\n", 6, `invalid number "z"`},
+		{facet + "endloop\nendfacet\n", 7, "facet has 2 vertices, expected 3"},
+		{facet + "vertex 0 1 0\nvertex 1 1 0\n", 7, "facet has more than 3 vertices"},
+		{facet + "vertex 0 1 0\nendloop\n", 7, "missing endfacet"},
+		{facet + "vertex 0 1 0\nendloop\nfacet normal 0 0 1\n", 8, "facet inside of a facet"},
+		{"solid a\nvertex 0 0 0\n", 2, "vertex outside of a facet"},
+		{"solid a\nfacet 0 0 1\n", 2, "expected facet normal"},
+		{"solid a\nouter loop\n", 2, "loop outside of a facet"},
+		{"solid a\n\nfoo\n", 3, `unexpected "foo"`},
+	}
+
+	for _, c := range cases {
+		m, err := ReadStl[float64](strings.NewReader(c.text))
+		if c.msg == "" {
+			if err != nil || len(m.Indices) != 1 {
+				t.Errorf("expected a triangle, got: %v %v", m, err)
+			}
+			continue
+		}
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Format != "stl" || parseErr.Line != c.line || parseErr.Msg != c.msg {
+			t.Errorf("%q, expected: line %v: %v, got: %v", c.text, c.line, c.msg, err)
+		}
+	}
+
+	if m, err := ReadStl[float64](strings.NewReader("solid empty\nendsolid empty\n")); err != nil || len(m.Indices) != 0 {
+		t.Errorf("expected an empty mesh, got: %v %v", m, err)
+	}
+
+	// reader errors are reported at the line that failed
+	broken := io.MultiReader(strings.NewReader(facet), iotest.ErrReader(errors.New("broken")))
+	var parseErr *ParseError
+	if _, err := ReadStl[float64](broken); !errors.As(err, &parseErr) || parseErr.Line != 6 || parseErr.Msg != "broken" {
+		t.Errorf("expected: line 6: broken, got: %v", err)
+	}
+}
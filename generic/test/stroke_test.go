@@ -0,0 +1,115 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func ringsBounds(rings []Poly[float64]) Rect[float64] {
+	points := []Vec2[float64]{}
+	for _, ring := range rings {
+		points = append(points, ring...)
+	}
+	return RectBounds(points)
+}
+
+func TestPolyStroke(t *testing.T) {
+	line := Poly[float64]{{0, 0}, {10, 0}}
+	corner := Poly[float64]{{0, 0}, {10, 0}, {10, 10}}
+
+	cases := []struct {
+		line   Poly[float64]
+		style  StrokeStyle[float64]
+		area   float64
+		bounds Rect[float64]
+	}{
+		{line, StrokeStyle[float64]{Width: 2}, 20, Rect[float64]{Vec2[float64]{0, -1}, Vec2[float64]{10, 1}}},
+		{line, StrokeStyle[float64]{Width: 2, Cap: StrokeCapSquare}, 24, Rect[float64]{Vec2[float64]{-1, -1}, Vec2[float64]{11, 1}}},
+		{line, StrokeStyle[float64]{Width: 2, Cap: StrokeCapRound}, 20 + math.Pi, Rect[float64]{Vec2[float64]{-1, -1}, Vec2[float64]{11, 1}}},
+		{corner, StrokeStyle[float64]{Width: 2, MiterLimit: 4}, 40, Rect[float64]{Vec2[float64]{0, -1}, Vec2[float64]{11, 10}}},
+		{corner, StrokeStyle[float64]{Width: 2, MiterLimit: 1.4}, 39.5, Rect[float64]{Vec2[float64]{0, -1}, Vec2[float64]{11, 10}}},
+		{corner, StrokeStyle[float64]{Width: 2, Join: StrokeJoinBevel}, 39.5, Rect[float64]{Vec2[float64]{0, -1}, Vec2[float64]{11, 10}}},
+		{corner, StrokeStyle[float64]{Width: 2, Join: StrokeJoinRound}, 39 + math.Pi/4, Rect[float64]{Vec2[float64]{0, -1}, Vec2[float64]{11, 10}}},
+		{square(0, 0, 10), StrokeStyle[float64]{Width: 2, MiterLimit: 4, Closed: true}, 144 - 64, Rect[float64]{Vec2[float64]{-1, -1}, Vec2[float64]{11, 11}}},
+		{Poly[float64]{{5, 5}}, StrokeStyle[float64]{Width: 2, Cap: StrokeCapRound}, math.Pi, Rect[float64]{Vec2[float64]{4, 4}, Vec2[float64]{6, 6}}},
+		{Poly[float64]{{5, 5}, {5, 5}}, StrokeStyle[float64]{Width: 2, Cap: StrokeCapSquare}, 4, Rect[float64]{Vec2[float64]{4, 4}, Vec2[float64]{6, 6}}},
+	}
+
+	for i, c := range cases {
+		rings := c.line.Stroke(c.style)
+		if area := ringsArea(rings); math.Abs(area-c.area) > 0.03*c.area {
+			t.Errorf("case %v, expected area: %v, got: %v", i, c.area, area)
+		}
+		if bounds := ringsBounds(rings); !c.bounds.Outset(1e-9).Contains(bounds.Min) || !c.bounds.Outset(1e-9).Contains(bounds.Max) ||
+			!bounds.Outset(0.05).Contains(c.bounds.Min) || !bounds.Outset(0.05).Contains(c.bounds.Max) {
+			t.Errorf("case %v, expected bounds: %v, got: %v", i, c.bounds, bounds)
+		}
+	}
+}
+
+func TestPolyStrokeDefaultMiter(t *testing.T) {
+	// the zero style miters a right angle, which is within the default limit of 4
+	rings := Poly[float64]{{0, 0}, {10, 0}, {10, 10}}.Stroke(StrokeStyle[float64]{Width: 2})
+	found := false
+	for _, ring := range rings {
+		for _, v := range ring {
+			found = found || vec2Identical(Vec2[float64]{11, -1}, v)
+		}
+	}
+	if !found {
+		t.Errorf("expected a miter at: %v, got: %v", Vec2[float64]{11, -1}, rings)
+	}
+}
+
+func TestPolyStrokeRings(t *testing.T) {
+	// closed paths leave a hole
+	rings := square(0, 0, 10).Stroke(StrokeStyle[float64]{Width: 2, Join: StrokeJoinRound, Closed: true})
+	if len(rings) != 2 || rings[0].Area()*rings[1].Area() >= 0 {
+		t.Fatalf("expected an outer ring and a hole, got: %v", rings)
+	}
+	if ringsContain(rings, Vec2[float64]{5, 5}) || !ringsContain(rings, Vec2[float64]{10.5, 5}) {
+		t.Errorf("expected only the stroke to be filled")
+	}
+
+	// overlapping parts are filled once
+	cross := Poly[float64]{{0, 0}, {10, 10}, {10, 0}, {0, 10}}
+	rings = cross.Stroke(StrokeStyle[float64]{Width: 1, Join: StrokeJoinBevel})
+	for _, v := range []Vec2[float64]{{5, 5}, {2, 2}, {10, 5}, {2, 8}} {
+		if !ringsContain(rings, v) {
+			t.Errorf("expected stroke to contain: %v", v)
+		}
+	}
+	if ringsContain(rings, Vec2[float64]{8, 5}) {
+		t.Errorf("expected a gap inside the loop")
+	}
+
+	// turning back on itself
+	rings = Poly[float64]{{0, 0}, {10, 0}, {5, 0}}.Stroke(StrokeStyle[float64]{Width: 2, Join: StrokeJoinRound})
+	if bounds := ringsBounds(rings); math.Abs(bounds.Max.X-11) > 1e-9 || math.Abs(ringsArea(rings)-(20+math.Pi/2)) > 0.05 {
+		t.Errorf("expected a round end, got: %v", rings)
+	}
+
+	// round joins and caps stay within tolerance of a circle
+	rings = Poly[float64]{{0, 0}}.Stroke(StrokeStyle[float64]{Width: 20, Cap: StrokeCapRound, Tolerance: 0.01})
+	for _, v := range rings[0] {
+		if math.Abs(v.Len()-10) > 1e-9 {
+			t.Errorf("expected point on the circle, got: %v", v)
+		}
+	}
+	if area := ringsArea(rings); area > 100*math.Pi || area < math.Pi*(10-0.01)*(10-0.01) {
+		t.Errorf("expected area within tolerance, got: %v", area)
+	}
+
+	if rings := (Poly[float64]{}).Stroke(StrokeStyle[float64]{Width: 2}); len(rings) != 0 {
+		t.Errorf("expected nothing, got: %v", rings)
+	}
+	if rings := (Poly[float64]{{0, 0}, {1, 0}}).Stroke(StrokeStyle[float64]{}); len(rings) != 0 {
+		t.Errorf("expected nothing, got: %v", rings)
+	}
+
+	rings32 := Poly[float32]{{0, 0}, {10, 0}, {10, 10}}.Stroke(StrokeStyle[float32]{Width: 2, MiterLimit: 4})
+	if len(rings32) != 1 || math.Abs(float64(rings32[0].Area())-40) > 1e-3 {
+		t.Errorf("expected float32 stroke, got: %v", rings32)
+	}
+}
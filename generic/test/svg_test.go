@@ -0,0 +1,180 @@
+package geomTest
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	. "github.com/tadeuszjt/geom/generic"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+/* Writes svg to the temp directory for looking at failures */
+func dumpSvg(t *testing.T, svg Svg[float64]) {
+	t.Helper()
+	name := strings.ReplaceAll(t.Name(), "/", "_") + ".svg"
+	path := filepath.Join(os.TempDir(), name)
+	f, err := os.Create(path)
+	if err != nil {
+		t.Logf("failed to dump svg: %v", err)
+		return
+	}
+	defer f.Close()
+	if err := WriteSvg(f, svg); err != nil {
+		t.Logf("failed to dump svg: %v", err)
+		return
+	}
+	t.Logf("geometry drawn to: %v", path)
+}
+
+type svgElement struct {
+	XMLName  xml.Name
+	Attrs    []xml.Attr   `xml:",any,attr"`
+	Text     string       `xml:",chardata"`
+	Children []svgElement `xml:",any"`
+}
+
+func (e svgElement) attr(name string) string {
+	for _, a := range e.Attrs {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func parseSvg(t *testing.T, svg Svg[float64]) svgElement {
+	var buf bytes.Buffer
+	if err := WriteSvg(&buf, svg); err != nil {
+		t.Fatal(err)
+	}
+	root := svgElement{}
+	if err := xml.Unmarshal(buf.Bytes(), &root); err != nil {
+		t.Fatalf("expected valid xml, got: %v\n%v", err, buf.String())
+	}
+	return root
+}
+
+func TestWriteSvg(t *testing.T) {
+	svg := Svg[float64]{Size: 220, Margin: 10}
+	svg.Poly(Poly[float64]{{0, 0}, {10, 0}, {5, 5}}, SvgStyle{Fill: "red", Label: "a < b"})
+	svg.Rect(MakeRect(-10.0, 0, 5, 5), SvgStyle{Stroke: "blue", StrokeWidth: 2})
+	svg.Segment(Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{10, 10}}, SvgStyle{})
+	svg.Polyline([]Vec2[float64]{{0, 10}, {5, 8}, {10, 10}}, SvgStyle{Label: "line"})
+	svg.Points([]Vec2[float64]{{1, 1}, {2, 2}}, SvgStyle{Stroke: "green", PointRadius: 4})
+	svg.Region([]Poly[float64]{square(0, 0, 4), square(1, 1, 2)}, SvgStyle{Fill: "grey"})
+	svg.Beziers(CatmullRom2[float64]{{0, 0}, {3, 4}, {6, 0}}.Beziers(), SvgStyle{})
+	svg.Poly(Poly[float64]{}, SvgStyle{})
+	svg.Rect(Rect[float64]{}, SvgStyle{})
+
+	root := parseSvg(t, svg)
+	if root.XMLName.Local != "svg" {
+		t.Fatalf("expected svg, got: %v", root.XMLName.Local)
+	}
+
+	// bounds are -10,0 to 10,10 with 10 pixels of margin and 10 pixels per unit
+	if viewBox := root.attr("viewBox"); viewBox != "-11 -1 22 12" {
+		t.Errorf("expected viewBox: -11 -1 22 12, got: %v", viewBox)
+	}
+	if root.attr("width") != "220" || root.attr("height") != "120" {
+		t.Errorf("expected size: 220x120, got: %vx%v", root.attr("width"), root.attr("height"))
+	}
+
+	names := []string{}
+	for _, c := range root.Children {
+		names = append(names, c.XMLName.Local)
+	}
+	expected := "polygon polygon polyline polyline circle circle path path text text"
+	if strings.Join(names, " ") != expected {
+		t.Fatalf("expected: %v, got: %v", expected, names)
+	}
+
+	children := root.Children
+	if children[0].attr("points") != "0,0 10,0 5,5" || children[0].attr("fill") != "red" || children[0].attr("stroke") != "black" {
+		t.Errorf("unexpected polygon: %v", children[0].Attrs)
+	}
+	if children[1].attr("stroke") != "blue" || children[1].attr("stroke-width") != "0.2" || children[1].attr("fill") != "none" {
+		t.Errorf("expected stroke width in pixels, got: %v", children[1].Attrs)
+	}
+	if children[4].attr("r") != "0.4" || children[4].attr("fill") != "green" {
+		t.Errorf("expected points filled with the stroke, got: %v", children[4].Attrs)
+	}
+	if d := children[6].attr("d"); d != "M0,0 4,0 4,4 0,4Z M1,1 3,1 3,3 1,3Z" || children[6].attr("fill-rule") != "evenodd" {
+		t.Errorf("unexpected region: %v", children[6].Attrs)
+	}
+	if d := children[7].attr("d"); !strings.HasPrefix(d, "M0,0 C") || strings.Count(d, "C") != 2 || strings.Count(d, "M") != 1 {
+		t.Errorf("expected two joined cubics, got: %v", d)
+	}
+	if label := children[8]; label.Text != "a < b" || label.attr("x") != "5" || label.attr("y") != "2.5" {
+		t.Errorf("expected label at the centre, got: %v %v", label.Text, label.Attrs)
+	}
+}
+
+func TestWriteSvgYUp(t *testing.T) {
+	svg := Svg[float64]{Size: 120, Margin: 10, YUp: true}
+	svg.Poly(Poly[float64]{{0, 0}, {10, 0}, {10, 10}}, SvgStyle{Label: "tri"})
+	svg.Points([]Vec2[float64]{{5, 5}}, SvgStyle{})
+
+	root := parseSvg(t, svg)
+	if len(root.Children) != 2 || root.Children[0].XMLName.Local != "g" {
+		t.Fatalf("expected a flipped group and a label, got: %v", root.Children)
+	}
+
+	// flipped about the centre of the view, which is -1 to 11
+	if transform := root.Children[0].attr("transform"); transform != "matrix(1 0 0 -1 0 10)" {
+		t.Errorf("expected a flip, got: %v", transform)
+	}
+	if len(root.Children[0].Children) != 2 || root.Children[0].Children[0].attr("points") != "0,0 10,0 10,10" {
+		t.Errorf("expected shapes in their own coordinates, got: %v", root.Children[0].Children)
+	}
+	if label := root.Children[1]; label.XMLName.Local != "text" || label.attr("y") != "5" || label.attr("x") != "5" {
+		t.Errorf("expected an unflipped label, got: %v", label.Attrs)
+	}
+}
+
+func TestWriteSvgDegenerate(t *testing.T) {
+	for i, svg := range []Svg[float64]{{}, {}, {}} {
+		switch i {
+		case 1:
+			svg.Points([]Vec2[float64]{{3, 4}}, SvgStyle{})
+		case 2:
+			svg.Segment(Segment2[float64]{Vec2[float64]{0, 0}, Vec2[float64]{0, 4}}, SvgStyle{})
+		}
+
+		root := parseSvg(t, svg)
+		var x, y, w, h float64
+		if _, err := fmt.Sscanf(root.attr("viewBox"), "%g %g %g %g", &x, &y, &w, &h); err != nil || w <= 0 || h <= 0 {
+			t.Errorf("case %v, expected a viewBox with area, got: %v", i, root.attr("viewBox"))
+		}
+		if i == 1 && !(Rect[float64]{Vec2[float64]{x, y}, Vec2[float64]{x + w, y + h}}).Contains(Vec2[float64]{3, 4}) {
+			t.Errorf("expected the point in view, got: %v", root.attr("viewBox"))
+		}
+	}
+}
+
+func TestWriteSvgPointsNoStroke(t *testing.T) {
+	svg := Svg[float64]{}
+	svg.Points([]Vec2[float64]{{1, 1}, {2, 2}}, SvgStyle{Stroke: "none"})
+
+	root := parseSvg(t, svg)
+	for _, c := range root.Children {
+		if c.attr("fill") != "black" {
+			t.Errorf("expected points filled black, got: %v", c.Attrs)
+		}
+	}
+}
+
+func TestWriteSvgFloat32(t *testing.T) {
+	svg := Svg[float32]{}
+	svg.Poly(Poly[float32]{{0.1, 0.2}, {1, 0}, {0, 1}}, SvgStyle{})
+
+	var buf bytes.Buffer
+	if err := WriteSvg(&buf, svg); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `points="0.1,0.2 1,0 0,1"`) {
+		t.Errorf("expected the shortest float32 digits, got: %v", buf.String())
+	}
+}
@@ -0,0 +1,268 @@
+package geomTest
+
+import (
+	"errors"
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func parsePath(t *testing.T, d string, tolerance float64) []SvgSubpath[float64] {
+	t.Helper()
+	subpaths, err := ParseSvgPath(d, Mat3Identity[float64](), tolerance)
+	if err != nil {
+		t.Fatalf("%q, %v", d, err)
+	}
+	return subpaths
+}
+
+func subpathsIdentical(a, b []SvgSubpath[float64]) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Closed != b[i].Closed || !polyIdentical(a[i].Poly, b[i].Poly) {
+			return false
+		}
+	}
+	return true
+}
+
+func TestParseSvgPathLines(t *testing.T) {
+	cases := []struct {
+		d        string
+		expected []SvgSubpath[float64]
+	}{
+		{"M0 0 L10 0 L10 10 Z", []SvgSubpath[float64]{{Poly[float64]{{0, 0}, {10, 0}, {10, 10}}, true}}},
+		{"m0,0 10,0 0,10z", []SvgSubpath[float64]{{Poly[float64]{{0, 0}, {10, 0}, {10, 10}}, true}}},
+		{"M0 0 H10 V10 H0 Z", []SvgSubpath[float64]{{square(0, 0, 10), true}}},
+		{"M5 5 h5 v5 h-5 v-5 z", []SvgSubpath[float64]{{square(5, 5, 5), true}}},
+		{"M1-2L.5.5e1-3e-1 0", []SvgSubpath[float64]{{Poly[float64]{{1, -2}, {0.5, 5}, {-0.3, 0}}, false}}},
+		{ // drawing after Z starts from the first point
+			"M0 0 L1 0 L1 1 Z l0 5",
+			[]SvgSubpath[float64]{{Poly[float64]{{0, 0}, {1, 0}, {1, 1}}, true}, {Poly[float64]{{0, 0}, {0, 5}}, false}},
+		},
+		{ // relative moveto after Z is from the first point
+			"m1 1 l1 0 l0 1 z m2 0 l1 0",
+			[]SvgSubpath[float64]{{Poly[float64]{{1, 1}, {2, 1}, {2, 2}}, true}, {Poly[float64]{{3, 1}, {4, 1}}, false}},
+		},
+		{"M0 0 M1 1 L2 2 M3 3", []SvgSubpath[float64]{{Poly[float64]{{1, 1}, {2, 2}}, false}}},
+		{"M0 0 L1 0 L0 0 Z", []SvgSubpath[float64]{{Poly[float64]{{0, 0}, {1, 0}}, true}}},
+		{"", nil},
+	}
+
+	for _, c := range cases {
+		if actual := parsePath(t, c.d, 0.1); !subpathsIdentical(c.expected, actual) {
+			t.Errorf("%q, expected: %v, got: %v", c.d, c.expected, actual)
+		}
+	}
+}
+
+func TestParseSvgPathCurves(t *testing.T) {
+	tolerance := 0.01
+	cases := []struct {
+		d      string
+		curves []Bezier2[float64]
+	}{
+		{"M0 0 C0 10 10 10 10 0", []Bezier2[float64]{{{0, 0}, {0, 10}, {10, 10}, {10, 0}}}},
+		{"M0 0 c0 10 10 10 10 0 s10 -10 10 0", []Bezier2[float64]{
+			{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+			{{10, 0}, {10, -10}, {20, -10}, {20, 0}},
+		}},
+		{"M0 0 Q5 10 10 0 T20 0", []Bezier2[float64]{{{0, 0}, {5, 10}, {10, 0}}, {{10, 0}, {15, -10}, {20, 0}}}},
+		{"M0 0 q5 10 10 0 t10 0 t10 0", []Bezier2[float64]{
+			{{0, 0}, {5, 10}, {10, 0}}, {{10, 0}, {15, -10}, {20, 0}}, {{20, 0}, {25, 10}, {30, 0}},
+		}},
+		{ // S after a line and T after a cubic don't reflect
+			"M0 0 L5 0 S10 5 10 0 T20 0",
+			[]Bezier2[float64]{{{0, 0}, {5, 0}}, {{5, 0}, {5, 0}, {10, 5}, {10, 0}}, {{10, 0}, {10, 0}, {20, 0}}},
+		},
+	}
+
+	for _, c := range cases {
+		subpaths := parsePath(t, c.d, tolerance)
+		if len(subpaths) != 1 {
+			t.Fatalf("%q, expected one subpath, got: %v", c.d, subpaths)
+		}
+		poly := subpaths[0].Poly
+
+		// every point is on the curves and every curve is near the polyline
+		exact := Poly[float64]{}
+		for _, b := range c.curves {
+			exact = append(exact, b.Flatten(1e-6)...)
+		}
+		for _, v := range poly {
+			if d := polylineDistance(exact, v); d > 1e-6 {
+				t.Errorf("%q, expected %v on the curve, got distance: %v", c.d, v, d)
+			}
+		}
+		for _, v := range exact {
+			if d := polylineDistance(poly, v); d > tolerance+1e-6 {
+				t.Errorf("%q, expected %v within tolerance, got: %v", c.d, v, d)
+			}
+		}
+		last := c.curves[len(c.curves)-1]
+		if !vec2Identical(last[len(last)-1], poly[len(poly)-1]) {
+			t.Errorf("%q, expected to end at: %v, got: %v", c.d, last[len(last)-1], poly[len(poly)-1])
+		}
+	}
+}
+
+func TestParseSvgPathArcs(t *testing.T) {
+	for _, tolerance := range []float64{0.1, 1e-3, 1e-6} {
+		// two half circles of radius 10 about the origin
+		subpaths := parsePath(t, "M10 0 A10 10 0 0 1 -10 0 a10,10 0 1,1 20,0 z", tolerance)
+		if len(subpaths) != 1 || !subpaths[0].Closed {
+			t.Fatalf("expected a closed circle, got: %v", subpaths)
+		}
+		circle := subpaths[0].Poly
+		for i, v := range circle {
+			mid := v.Plus(circle[(i+1)%len(circle)]).ScaledBy(0.5)
+			if d := math.Abs(v.Len() - 10); d > tolerance {
+				t.Fatalf("tolerance: %v, expected %v on the circle, got: %v", tolerance, v, d)
+			}
+			if d := 10 - mid.Len(); d > tolerance {
+				t.Fatalf("tolerance: %v, expected edges within tolerance, got: %v", tolerance, d)
+			}
+		}
+		if math.Abs(circle.Area()-100*math.Pi) > 20*math.Pi*tolerance {
+			t.Errorf("tolerance: %v, expected area: %v, got: %v", tolerance, 100*math.Pi, circle.Area())
+		}
+
+		// positive angles turn clockwise on screen
+		if circle.Area() <= 0 || circle[1].Y <= 0 {
+			t.Errorf("expected the sweep flag to go through +y first, got: %v", circle[:2])
+		}
+	}
+
+	// ellipse rotated by 90 degrees, radii scaled up to 8 and 4 to reach the end point
+	subpaths := parsePath(t, "M0 0 A1 2 90 0 0 0 8", 1e-4)
+	for _, v := range subpaths[0].Poly {
+		centred := v.Minus(Vec2[float64]{0, 4})
+		if d := math.Abs(centred.X*centred.X/64 + centred.Y*centred.Y/16 - 1); d > 1e-3 {
+			t.Fatalf("expected %v on the ellipse, got: %v", v, d)
+		}
+		if v.X > 1e-9 {
+			t.Errorf("expected the small arc on the left side, got: %v", v)
+		}
+	}
+
+	// zero radius is a line and equal end points are left out
+	expected := []SvgSubpath[float64]{{Poly[float64]{{0, 0}, {5, 5}}, false}}
+	if actual := parsePath(t, "M0 0 A0 3 0 0 0 5 5 A3 3 0 1 1 5 5", 0.1); !subpathsIdentical(expected, actual) {
+		t.Errorf("expected: %v, got: %v", expected, actual)
+	}
+
+	// flags needn't be separated
+	a, b := parsePath(t, "M0 0 a5 5 0 1 0 10 0", 0.1), parsePath(t, "M0 0a5,5,0,10,10,0", 0.1)
+	if !subpathsIdentical(a, b) {
+		t.Errorf("expected: %v, got: %v", a, b)
+	}
+}
+
+func TestParseSvgPathTransform(t *testing.T) {
+	d := "M0 0 L10 0 Q10 10 0 10 A5 5 0 0 1 0 0 Z"
+	mat := Mat3Translation(Vec2[float64]{3, 4}).Product(Mat3Rotation(0.5)).Product(Mat3Scalar(2.0, 2))
+
+	plain := parsePath(t, d, 1e-4)
+	transformed, err := ParseSvgPath(d, mat, 1e-4)
+	if err != nil || len(transformed) != 1 || !transformed[0].Closed {
+		t.Fatalf("expected a closed subpath, got: %v %v", transformed, err)
+	}
+	for _, v := range transformed[0].Poly {
+		back := Mat3Scalar(0.5, 0.5).Product(Mat3Rotation(-0.5)).Product(Mat3Translation(Vec2[float64]{-3, -4})).TimesVec2(v, 1)
+		if d := polylineDistance(append(PolyCopy(plain[0].Poly), plain[0].Poly[0]), back); d > 1e-4 {
+			t.Errorf("expected %v on the path, got distance: %v", back, d)
+		}
+	}
+
+	// the tolerance is for the transformed path so it needs more points
+	if len(transformed[0].Poly) <= len(plain[0].Poly) {
+		t.Errorf("expected more points when scaled up, got: %v %v", len(transformed[0].Poly), len(plain[0].Poly))
+	}
+
+	float32Path, err := ParseSvgPath("M0 0 L1 0.5", Mat3Identity[float32](), 0.1)
+	if err != nil || len(float32Path) != 1 || float32Path[0].Poly[1] != (Vec2[float32]{1, 0.5}) {
+		t.Errorf("expected a float32 line, got: %v %v", float32Path, err)
+	}
+}
+
+func TestParseSvgTransform(t *testing.T) {
+	cases := []struct {
+		text     string
+		expected Mat3[float64]
+	}{
+		{"", Mat3Identity[float64]()},
+		{"translate(10)", Mat3Translation(Vec2[float64]{10, 0})},
+		{"translate(10, -5)", Mat3Translation(Vec2[float64]{10, -5})},
+		{"scale(2)", Mat3Scalar(2.0, 2)},
+		{"scale(2 3)", Mat3Scalar(2.0, 3)},
+		{"rotate(90)", Mat3Rotation(math.Pi / 2)},
+		{"matrix(1 2 3 4 5 6)", Mat3[float64]{1, 3, 5, 2, 4, 6, 0, 0, 1}},
+		{"skewX(45)", Mat3[float64]{1, 1, 0, 0, 1, 0, 0, 0, 1}},
+		{"skewY(45)", Mat3[float64]{1, 0, 0, 1, 1, 0, 0, 0, 1}},
+		{
+			"translate(1,2) scale(3)\nrotate(-30)",
+			Mat3Translation(Vec2[float64]{1, 2}).Product(Mat3Scalar(3.0, 3)).Product(Mat3Rotation(-math.Pi / 6)),
+		},
+		{
+			"rotate(90 10 0)",
+			Mat3Translation(Vec2[float64]{10, 0}).Product(Mat3Rotation(math.Pi / 2)).Product(Mat3Translation(Vec2[float64]{-10, 0})),
+		},
+	}
+
+	for _, c := range cases {
+		actual, err := ParseSvgTransform[float64](c.text)
+		if err != nil || !mat3Identical(c.expected, actual) {
+			t.Errorf("%q, expected: %v, got: %v %v", c.text, c.expected, actual, err)
+		}
+	}
+
+	// a point rotated about (10, 0)
+	mat, _ := ParseSvgTransform[float64]("rotate(90 10 0)")
+	if v := mat.TimesVec2(Vec2[float64]{20, 0}, 1); !vec2Identical(Vec2[float64]{10, 10}, v) {
+		t.Errorf("expected: %v, got: %v", Vec2[float64]{10, 10}, v)
+	}
+}
+
+func TestParseSvgErrors(t *testing.T) {
+	paths := []struct {
+		d   string
+		msg string
+	}{
+		{"L1 1", "offset 1: path must start with a moveto"},
+		{"M1", "offset 2: expected a number"},
+		{"M1 1 L2 x", "offset 8: expected a number"},
+		{"M1 1 Z 3 4", "offset 7: expected a command"},
+		{"M1 1 B2 2", "offset 6: unknown command 'B'"},
+		{"M0 0 A1 1 0 2 0 5 5", "offset 12: expected a flag"},
+		{"M0 0 L1e400 0", "offset 6: invalid number \"1e400\""},
+		{"1 2", "offset 0: expected a command"},
+	}
+	for _, c := range paths {
+		_, err := ParseSvgPath(c.d, Mat3Identity[float64](), 0.1)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Format != "svg" || parseErr.Msg != c.msg {
+			t.Errorf("%q, expected: %v, got: %v", c.d, c.msg, err)
+		}
+	}
+
+	transforms := []struct {
+		text string
+		msg  string
+	}{
+		{"spin(1)", `offset 4: unknown transform "spin"`},
+		{"scale 2", "offset 6: expected ("},
+		{"scale(2", "offset 7: expected )"},
+		{"rotate(1 2)", "offset 11: rotate has 2 arguments"},
+		{"matrix(1,2,3,4,5)", "offset 17: matrix has 5 arguments"},
+		{"translate(a)", "offset 10: expected a number"},
+	}
+	for _, c := range transforms {
+		_, err := ParseSvgTransform[float64](c.text)
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || parseErr.Msg != c.msg {
+			t.Errorf("%q, expected: %v, got: %v", c.text, c.msg, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package geomTest
+
+import (
+	. "github.com/tadeuszjt/geom/generic"
+	"math"
+	"testing"
+)
+
+func triangulationArea(verts Poly[float64], tris [][3]int) (float64, bool) {
+	sum := 0.0
+	for _, tri := range tris {
+		area := Poly[float64]{verts[tri[0]], verts[tri[1]], verts[tri[2]]}.Area()
+		if area < 0 {
+			return 0, false
+		}
+		sum += area
+	}
+	return sum, true
+}
+
+/* Draws the triangles over the poly, see dumpSvg */
+func dumpTriangulation(t *testing.T, poly Poly[float64], tris [][3]int) {
+	t.Helper()
+	svg := Svg[float64]{}
+	for _, tri := range tris {
+		svg.Poly(Poly[float64]{poly[tri[0]], poly[tri[1]], poly[tri[2]]}, SvgStyle{Stroke: "blue", Fill: "lightblue"})
+	}
+	svg.Poly(poly, SvgStyle{Stroke: "red", StrokeWidth: 2})
+	svg.Points(poly, SvgStyle{})
+	dumpSvg(t, svg)
+}
+
+func TestPolyTriangulate(t *testing.T) {
+	cases := []struct {
+		poly    Poly[float64]
+		numTris int
+		area    float64
+	}{
+		{Poly[float64]{}, 0, 0},
+		{Poly[float64]{{0, 0}, {1, 0}}, 0, 0},
+		{Poly[float64]{{0, 0}, {1, 0}, {0, 1}}, 1, 0.5},
+		{Poly[float64]{{0, 0}, {0, 1}, {1, 0}}, 1, 0.5},
+		{Poly[float64]{{0, 0}, {2, 0}, {2, 2}, {0, 2}}, 2, 4},
+		{Poly[float64]{{0, 2}, {2, 2}, {2, 0}, {0, 0}}, 2, 4},
+		{ // concave L shape
+			Poly[float64]{{0, 0}, {2, 0}, {2, 1}, {1, 1}, {1, 2}, {0, 2}},
+			4, 3,
+		},
+		{ // concave arrow, reversed winding
+			Poly[float64]{{0, 0}, {2, 1}, {4, 0}, {2, 4}},
+			2, 6,
+		},
+		{ // comb
+			Poly[float64]{{0, 0}, {5, 0}, {5, 3}, {4, 3}, {4, 1}, {3, 1}, {3, 3}, {2, 3}, {2, 1}, {1, 1}, {1, 3}, {0, 3}},
+			10, 11,
+		},
+		{ // collinear verts on an edge
+			Poly[float64]{{0, 0}, {1, 0}, {2, 0}, {2, 2}, {0, 2}},
+			3, 4,
+		},
+		{ // duplicate verts
+			Poly[float64]{{0, 0}, {2, 0}, {2, 0}, {2, 2}, {0, 2}, {0, 0}},
+			2, 4,
+		},
+		{ // all collinear
+			Poly[float64]{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+			0, 0,
+		},
+		{ // all the same point
+			Poly[float64]{{1, 1}, {1, 1}, {1, 1}},
+			0, 0,
+		},
+	}
+
+	for _, c := range cases {
+		tris := c.poly.Triangulate()
+		if len(tris) != c.numTris {
+			t.Errorf("poly: %v, expected %v triangles, got: %v", c.poly, c.numTris, tris)
+			dumpTriangulation(t, c.poly, tris)
+			continue
+		}
+
+		area, ok := triangulationArea(c.poly, tris)
+		if !ok {
+			t.Errorf("poly: %v, expected clockwise triangles, got: %v", c.poly, tris)
+		}
+		if !floatIdentical(c.area, area) {
+			t.Errorf("poly: %v, expected area: %v, got: %v", c.poly, c.area, area)
+		}
+		if !ok || !floatIdentical(c.area, area) {
+			dumpTriangulation(t, c.poly, tris)
+		}
+	}
+}
+
+func TestPolyTriangulateCircle(t *testing.T) {
+	circle := Poly[float64]{}
+	for i := 0; i < 64; i++ {
+		theta := float64(i) * 2 * math.Pi / 64
+		circle = append(circle, Vec2[float64]{math.Cos(theta), math.Sin(theta)})
+	}
+
+	tris := circle.Triangulate()
+	if len(tris) != 62 {
+		t.Errorf("expected 62 triangles, got: %v", len(tris))
+	}
+
+	area, ok := triangulationArea(circle, tris)
+	if !ok || !floatIdentical(circle.Area(), area) {
+		t.Errorf("expected area: %v, got: %v", circle.Area(), area)
+	}
+}
+
+func TestPolyTriangulateHoles(t *testing.T) {
+	cases := []struct {
+		outer Poly[float64]
+		holes []Poly[float64]
+		area  float64
+	}{
+		{
+			Poly[float64]{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+			nil,
+			16,
+		},
+		{
+			Poly[float64]{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+			[]Poly[float64]{{{1, 1}, {3, 1}, {3, 3}, {1, 3}}},
+			12,
+		},
+		{ // hole with the same winding as the outer ring
+			Poly[float64]{{0, 0}, {0, 4}, {4, 4}, {4, 0}},
+			[]Poly[float64]{{{1, 1}, {1, 3}, {3, 3}, {3, 1}}},
+			12,
+		},
+		{ // two holes
+			Poly[float64]{{0, 0}, {10, 0}, {10, 4}, {0, 4}},
+			[]Poly[float64]{
+				{{1, 1}, {3, 1}, {3, 3}, {1, 3}},
+				{{6, 1}, {9, 1}, {9, 3}, {6, 3}},
+			},
+			40 - 4 - 6,
+		},
+		{ // hole touching the outer ring
+			Poly[float64]{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+			[]Poly[float64]{{{0, 1}, {2, 1}, {2, 3}, {0, 3}}},
+			12,
+		},
+		{ // triangle hole inside concave outer ring
+			Poly[float64]{{0, 0}, {6, 0}, {6, 6}, {3, 3}, {0, 6}},
+			[]Poly[float64]{{{2, 1}, {4, 1}, {3, 2}}},
+			27 - 1,
+		},
+		{ // degenerate holes are ignored
+			Poly[float64]{{0, 0}, {4, 0}, {4, 4}, {0, 4}},
+			[]Poly[float64]{{}, {{1, 1}, {2, 2}, {3, 3}}},
+			16,
+		},
+	}
+
+	for _, c := range cases {
+		verts := append(Poly[float64]{}, c.outer...)
+		for _, hole := range c.holes {
+			verts = append(verts, hole...)
+		}
+
+		tris := c.outer.TriangulateHoles(c.holes)
+		area, ok := triangulationArea(verts, tris)
+		if !ok {
+			t.Errorf("outer: %v, expected clockwise triangles, got: %v", c.outer, tris)
+		}
+		if !floatIdentical(c.area, area) {
+			t.Errorf("outer: %v, holes: %v, expected area: %v, got: %v", c.outer, c.holes, c.area, area)
+		}
+
+		for _, tri := range tris {
+			centre := verts[tri[0]].Plus(verts[tri[1]]).Plus(verts[tri[2]]).ScaledBy(1. / 3.)
+			for _, hole := range c.holes {
+				if len(hole) > 2 && hole.Area() != 0 && hole.Contains(centre) {
+					t.Errorf("triangle %v is inside hole %v", tri, hole)
+				}
+			}
+		}
+	}
+}
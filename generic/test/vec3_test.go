@@ -240,3 +240,26 @@ func TestVec3NormPitchYaw(t *testing.T) {
 		}
 	}
 }
+
+func TestVec3Cross(t *testing.T) {
+	cases := []struct {
+		a, b   Vec3[float64]
+		result Vec3[float64]
+	}{
+		{Vec3[float64]{}, Vec3[float64]{}, Vec3[float64]{}},
+		{Vec3[float64]{1, 0, 0}, Vec3[float64]{0, 1, 0}, Vec3[float64]{0, 0, 1}},
+		{Vec3[float64]{0, 1, 0}, Vec3[float64]{0, 0, 1}, Vec3[float64]{1, 0, 0}},
+		{Vec3[float64]{0, 0, 1}, Vec3[float64]{1, 0, 0}, Vec3[float64]{0, 1, 0}},
+		{Vec3[float64]{0, 1, 0}, Vec3[float64]{1, 0, 0}, Vec3[float64]{0, 0, -1}},
+		{Vec3[float64]{1, 2, 3}, Vec3[float64]{4, 5, 6}, Vec3[float64]{-3, 6, -3}},
+		{Vec3[float64]{1, 2, 3}, Vec3[float64]{2, 4, 6}, Vec3[float64]{}},
+	}
+
+	for _, c := range cases {
+		expected := c.result
+		actual := c.a.Cross(c.b)
+		if !vec3Identical(expected, actual) {
+			t.Errorf("a: %v, b: %v, expected: %v, got: %v", c.a, c.b, expected, actual)
+		}
+	}
+}
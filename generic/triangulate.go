@@ -0,0 +1,452 @@
+package geom
+
+import "sort"
+
+/* Ear clipping triangulation, a port of the earcut algorithm.
+ * Returns index triples into poly, every triangle is clockwise (positive Area)
+ * regardless of the winding of poly. Duplicate and collinear verts are
+ * skipped, degenerate input produces fewer or no triangles.
+ */
+func (poly Poly[T]) Triangulate() [][3]int {
+	return poly.TriangulateHoles(nil)
+}
+
+/* Triangulates poly with the hole rings removed. Indices refer to the verts of
+ * poly followed by the verts of each hole in order, as if all rings were
+ * appended into a single slice. Holes may have either winding.
+ */
+func (poly Poly[T]) TriangulateHoles(holes []Poly[T]) [][3]int {
+	tris := [][3]int{}
+
+	outer := earLinkedList(poly, 0, true)
+	if outer == nil || outer.next == outer.prev {
+		return tris
+	}
+
+	offset := len(poly)
+	queue := []*earNode[T]{}
+	for _, hole := range holes {
+		list := earLinkedList(hole, offset, false)
+		offset += len(hole)
+		if list == nil {
+			continue
+		}
+		if list == list.next {
+			list.steiner = true
+		}
+		queue = append(queue, list.leftmost())
+	}
+
+	sort.SliceStable(queue, func(i, j int) bool {
+		if queue[i].x != queue[j].x {
+			return queue[i].x < queue[j].x
+		}
+		return queue[i].y < queue[j].y
+	})
+
+	for _, hole := range queue {
+		outer = earEliminateHole(hole, outer)
+	}
+
+	earcutLinked(outer, &tris, 0)
+	return tris
+}
+
+type earNode[T Num] struct {
+	i          int
+	x, y       T
+	prev, next *earNode[T]
+	steiner    bool
+}
+
+func earLinkedList[T Num](ring Poly[T], offset int, positive bool) *earNode[T] {
+	if len(ring) == 0 {
+		return nil
+	}
+
+	var sum T
+	for i := range ring {
+		j := (i + 1) % len(ring)
+		sum += ring[i].Cross(ring[j])
+	}
+
+	var last *earNode[T]
+	if positive == (sum > 0) {
+		for i := range ring {
+			last = earInsertNode(offset+i, ring[i], last)
+		}
+	} else {
+		for i := len(ring) - 1; i >= 0; i-- {
+			last = earInsertNode(offset+i, ring[i], last)
+		}
+	}
+
+	if last != nil && last.equals(last.next) {
+		last.remove()
+		last = last.next
+	}
+
+	return last
+}
+
+func earInsertNode[T Num](i int, v Vec2[T], last *earNode[T]) *earNode[T] {
+	p := &earNode[T]{i: i, x: v.X, y: v.Y}
+	if last == nil {
+		p.prev = p
+		p.next = p
+	} else {
+		p.next = last.next
+		p.prev = last
+		last.next.prev = p
+		last.next = p
+	}
+	return p
+}
+
+func (p *earNode[T]) remove() {
+	p.next.prev = p.prev
+	p.prev.next = p.next
+}
+
+func (p *earNode[T]) equals(q *earNode[T]) bool {
+	return p.x == q.x && p.y == q.y
+}
+
+func (start *earNode[T]) leftmost() *earNode[T] {
+	p, left := start, start
+	for {
+		if p.x < left.x || (p.x == left.x && p.y < left.y) {
+			left = p
+		}
+		p = p.next
+		if p == start {
+			return left
+		}
+	}
+}
+
+/* negative for a convex turn in a positive ring */
+func earArea[T Num](p, q, r *earNode[T]) T {
+	return (q.y-p.y)*(r.x-q.x) - (q.x-p.x)*(r.y-q.y)
+}
+
+func earPointInTriangle[T Num](ax, ay, bx, by, cx, cy, px, py T) bool {
+	return (cx-px)*(ay-py) >= (ax-px)*(cy-py) &&
+		(ax-px)*(by-py) >= (bx-px)*(ay-py) &&
+		(bx-px)*(cy-py) >= (cx-px)*(by-py)
+}
+
+func earcutLinked[T Num](ear *earNode[T], tris *[][3]int, pass int) {
+	if ear == nil {
+		return
+	}
+
+	stop := ear
+	for ear.prev != ear.next {
+		prev, next := ear.prev, ear.next
+
+		if ear.isEar() {
+			*tris = append(*tris, [3]int{prev.i, ear.i, next.i})
+			ear.remove()
+			ear = next.next
+			stop = next.next
+			continue
+		}
+
+		ear = next
+		if ear == stop {
+			switch pass {
+			case 0: // remove degenerate verts and try again
+				earcutLinked(earFilterPoints(ear, nil), tris, 1)
+			case 1: // clip small self-intersections and try again
+				ear = earCureLocalIntersections(earFilterPoints(ear, nil), tris)
+				earcutLinked(ear, tris, 2)
+			case 2: // split into two polygons along a valid diagonal
+				earSplit(ear, tris)
+			}
+			return
+		}
+	}
+}
+
+func (ear *earNode[T]) isEar() bool {
+	a, b, c := ear.prev, ear, ear.next
+	if earArea(a, b, c) >= 0 {
+		return false // reflex
+	}
+
+	for p := c.next; p != a; p = p.next {
+		if p.equals(a) {
+			continue
+		}
+		if earPointInTriangle(a.x, a.y, b.x, b.y, c.x, c.y, p.x, p.y) &&
+			earArea(p.prev, p, p.next) >= 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func earFilterPoints[T Num](start, end *earNode[T]) *earNode[T] {
+	if start == nil {
+		return start
+	}
+	if end == nil {
+		end = start
+	}
+
+	p := start
+	for {
+		again := false
+		if !p.steiner && (p.equals(p.next) || earArea(p.prev, p, p.next) == 0) {
+			p.remove()
+			p = p.prev
+			end = p
+			if p == p.next {
+				break
+			}
+			again = true
+		} else {
+			p = p.next
+		}
+
+		if !again && p == end {
+			break
+		}
+	}
+
+	return end
+}
+
+func earCureLocalIntersections[T Num](start *earNode[T], tris *[][3]int) *earNode[T] {
+	p := start
+	for {
+		a, b := p.prev, p.next.next
+		if !a.equals(b) && earIntersects(a, p, p.next, b) &&
+			earLocallyInside(a, b) && earLocallyInside(b, a) {
+			*tris = append(*tris, [3]int{a.i, p.i, b.i})
+			p.next.remove()
+			p.remove()
+			p = b
+			start = b
+		}
+
+		p = p.next
+		if p == start {
+			break
+		}
+	}
+
+	return earFilterPoints(p, nil)
+}
+
+func earSplit[T Num](start *earNode[T], tris *[][3]int) {
+	a := start
+	for {
+		for b := a.next.next; b != a.prev; b = b.next {
+			if a.i != b.i && earIsValidDiagonal(a, b) {
+				c := earSplitPolygon(a, b)
+				a = earFilterPoints(a, a.next)
+				c = earFilterPoints(c, c.next)
+				earcutLinked(a, tris, 0)
+				earcutLinked(c, tris, 0)
+				return
+			}
+		}
+
+		a = a.next
+		if a == start {
+			return
+		}
+	}
+}
+
+func earEliminateHole[T Num](hole, outer *earNode[T]) *earNode[T] {
+	bridge := earFindHoleBridge(hole, outer)
+	if bridge == nil {
+		return outer
+	}
+
+	reverse := earSplitPolygon(bridge, hole)
+	earFilterPoints(reverse, reverse.next)
+	return earFilterPoints(bridge, bridge.next)
+}
+
+/* David Eberly's algorithm for finding a vertex of outer visible from hole */
+func earFindHoleBridge[T Num](hole, outer *earNode[T]) *earNode[T] {
+	hx, hy := hole.x, hole.y
+	var m *earNode[T]
+	var qx T
+	found := false
+
+	// find a segment intersected by a ray from the hole's leftmost point to the left
+	p := outer
+	for {
+		if hy <= p.y && hy >= p.next.y && p.next.y != p.y {
+			x := p.x + (hy-p.y)*(p.next.x-p.x)/(p.next.y-p.y)
+			if x <= hx && (!found || x > qx) {
+				qx = x
+				found = true
+				m = p
+				if p.next.x < p.x {
+					m = p.next
+				}
+				if x == hx {
+					return m // hole touches the outer segment
+				}
+			}
+		}
+
+		p = p.next
+		if p == outer {
+			break
+		}
+	}
+
+	if m == nil {
+		return nil
+	}
+
+	// look for points inside the triangle of hole point, segment intersection and
+	// endpoint, choosing the one with the minimum angle to the ray
+	stop := m
+	mx, my := m.x, m.y
+	tanMin := -1.0
+
+	p = m
+	for {
+		if hx >= p.x && p.x >= mx && hx != p.x {
+			ax, cx := qx, hx
+			if hy < my {
+				ax, cx = hx, qx
+			}
+
+			if earPointInTriangle(ax, hy, mx, my, cx, hy, p.x, p.y) {
+				dy := float64(hy - p.y)
+				if dy < 0 {
+					dy = -dy
+				}
+				tan := dy / float64(hx-p.x)
+
+				if earLocallyInside(p, hole) && (tanMin < 0 || tan < tanMin ||
+					(tan == tanMin && (p.x > m.x || (p.x == m.x && earSectorContainsSector(m, p))))) {
+					m = p
+					tanMin = tan
+				}
+			}
+		}
+
+		p = p.next
+		if p == stop {
+			break
+		}
+	}
+
+	return m
+}
+
+func earSectorContainsSector[T Num](m, p *earNode[T]) bool {
+	return earArea(m.prev, m, p.prev) < 0 && earArea(p.next, m, m.next) < 0
+}
+
+func earIsValidDiagonal[T Num](a, b *earNode[T]) bool {
+	return a.next.i != b.i && a.prev.i != b.i && !earIntersectsPolygon(a, b) &&
+		(earLocallyInside(a, b) && earLocallyInside(b, a) && earMiddleInside(a, b) &&
+			(earArea(a.prev, a, b.prev) != 0 || earArea(a, b.prev, b) != 0) ||
+			a.equals(b) && earArea(a.prev, a, a.next) > 0 && earArea(b.prev, b, b.next) > 0)
+}
+
+func earSign[T Num](v T) int {
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func earOnSegment[T Num](p, q, r *earNode[T]) bool {
+	return q.x <= max(p.x, r.x) && q.x >= min(p.x, r.x) &&
+		q.y <= max(p.y, r.y) && q.y >= min(p.y, r.y)
+}
+
+func earIntersects[T Num](p1, q1, p2, q2 *earNode[T]) bool {
+	o1 := earSign(earArea(p1, q1, p2))
+	o2 := earSign(earArea(p1, q1, q2))
+	o3 := earSign(earArea(p2, q2, p1))
+	o4 := earSign(earArea(p2, q2, q1))
+
+	switch {
+	case o1 != o2 && o3 != o4:
+		return true
+	case o1 == 0 && earOnSegment(p1, p2, q1):
+		return true
+	case o2 == 0 && earOnSegment(p1, q2, q1):
+		return true
+	case o3 == 0 && earOnSegment(p2, p1, q2):
+		return true
+	case o4 == 0 && earOnSegment(p2, q1, q2):
+		return true
+	}
+	return false
+}
+
+func earIntersectsPolygon[T Num](a, b *earNode[T]) bool {
+	p := a
+	for {
+		if p.i != a.i && p.next.i != a.i && p.i != b.i && p.next.i != b.i &&
+			earIntersects(p, p.next, a, b) {
+			return true
+		}
+		p = p.next
+		if p == a {
+			return false
+		}
+	}
+}
+
+func earLocallyInside[T Num](a, b *earNode[T]) bool {
+	if earArea(a.prev, a, a.next) < 0 {
+		return earArea(a, b, a.next) >= 0 && earArea(a, a.prev, b) >= 0
+	}
+	return earArea(a, b, a.prev) < 0 || earArea(a, a.next, b) < 0
+}
+
+func earMiddleInside[T Num](a, b *earNode[T]) bool {
+	p := a
+	inside := false
+	px, py := (a.x+b.x)/2, (a.y+b.y)/2
+
+	for {
+		if (p.y > py) != (p.next.y > py) && p.next.y != p.y &&
+			px < (p.next.x-p.x)*(py-p.y)/(p.next.y-p.y)+p.x {
+			inside = !inside
+		}
+		p = p.next
+		if p == a {
+			return inside
+		}
+	}
+}
+
+/* links a to b with a bridge, splitting the ring into two */
+func earSplitPolygon[T Num](a, b *earNode[T]) *earNode[T] {
+	a2 := &earNode[T]{i: a.i, x: a.x, y: a.y}
+	b2 := &earNode[T]{i: b.i, x: b.x, y: b.y}
+	an, bp := a.next, b.prev
+
+	a.next = b
+	b.prev = a
+
+	a2.next = an
+	an.prev = a2
+
+	b2.next = a2
+	a2.prev = b2
+
+	bp.next = b2
+	b2.prev = bp
+
+	return b2
+}
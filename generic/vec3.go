@@ -17,6 +17,14 @@ func (a Vec3[T]) Dot(b Vec3[T]) T {
 	return a.X*b.X + a.Y*b.Y + a.Z*b.Z
 }
 
+func (a Vec3[T]) Cross(b Vec3[T]) Vec3[T] {
+	return Vec3[T]{
+		a.Y*b.Z - a.Z*b.Y,
+		a.Z*b.X - a.X*b.Z,
+		a.X*b.Y - a.Y*b.X,
+	}
+}
+
 func (a Vec3[T]) Plus(b Vec3[T]) Vec3[T] {
 	return Vec3[T]{a.X + b.X, a.Y + b.Y, a.Z + b.Z}
 }
@@ -101,3 +109,23 @@ func Vec3NormRand[T Num]() Vec3[T] {
 		Z: T(long),
 	}
 }
+
+func vec3Axis[T Num](v Vec3[T], axis int) T {
+	switch axis {
+	case 0:
+		return v.X
+	case 1:
+		return v.Y
+	}
+	return v.Z
+}
+
+func vec3Unit[T Num](axis int, sign T) Vec3[T] {
+	switch axis {
+	case 0:
+		return Vec3[T]{sign, 0, 0}
+	case 1:
+		return Vec3[T]{0, sign, 0}
+	}
+	return Vec3[T]{0, 0, sign}
+}